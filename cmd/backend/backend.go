@@ -0,0 +1,133 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+// Backend is a Go version manager that gos can drive
+type Backend interface {
+	// Name identifies the backend ("native", "gobrew" or "g")
+	Name() string
+	// Available reports whether the backend's tooling is present on this machine
+	Available() bool
+	// Install downloads a version ("latest" resolves to the newest stable release)
+	Install(version string) error
+	// Uninstall removes an installed version
+	Uninstall(version string) error
+	// Use makes an installed version the active one ("latest" picks the newest installed)
+	Use(version string) error
+	// ListInstalled returns the installed versions
+	ListInstalled() ([]string, error)
+	// ListRemote returns the versions available for installation
+	ListRemote() ([]string, error)
+	// Current returns the active version
+	Current() (string, error)
+	// Root returns the directory the backend keeps its state in
+	Root() string
+	// GOROOT returns the GOROOT of a version; an empty version means the active one
+	GOROOT(version string) string
+	// BinDirs returns the PATH entries the backend needs, most specific first
+	BinDirs() []string
+}
+
+// Names lists the supported backends in detection order
+var Names = []string{"native", "gobrew", "g"}
+
+// ByName returns the backend with the given name
+func ByName(name string) (Backend, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "native", "gos":
+		return NewNative(), nil
+	case "gobrew":
+		return NewGobrew(), nil
+	case "g":
+		return NewG(), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q (supported: %s)", name, strings.Join(Names, ", "))
+}
+
+// All returns every supported backend in detection order
+func All() []Backend {
+	return []Backend{NewNative(), NewGobrew(), NewG()}
+}
+
+// Detect selects the backend to use. An explicit choice (GOS_BACKEND or
+// "backend" in ~/.gos/config) wins; otherwise the first backend that already
+// holds toolchains is used, falling back to the native installer.
+func Detect() (Backend, error) {
+	if name := config.Get("backend"); name != "" {
+		return ByName(name)
+	}
+
+	for _, b := range All() {
+		if !b.Available() {
+			continue
+		}
+		if versions, err := b.ListInstalled(); err == nil && len(versions) > 0 {
+			return b, nil
+		}
+	}
+	return NewNative(), nil
+}
+
+// Active returns the selected backend, printing guidance when it cannot be used
+func Active() (Backend, error) {
+	b, err := Detect()
+	if err != nil {
+		ui.Hint.Printf("💡 Fix the 'backend' setting in %s or GOS_BACKEND\n", config.Path())
+		return nil, &errs.UsageError{Err: err}
+	}
+
+	if !b.Available() {
+		ui.Hint.Printf("💡 Run first: gos setup --%s\n", b.Name())
+		return nil, &errs.BackendMissingError{Backend: b.Name()}
+	}
+	ui.Debug.Printf("backend: %s (%s)\n", b.Name(), b.Root())
+	return b, nil
+}
+
+// PathLine returns the shell PATH assignment for a backend, including $GOPATH/bin
+func PathLine(b Backend) string {
+	homeDir := common.GetHomeDir()
+
+	var entries []string
+	for _, dir := range b.BinDirs() {
+		if rel, err := filepath.Rel(homeDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = "$HOME/" + filepath.ToSlash(rel)
+		}
+		entries = append(entries, dir)
+	}
+	entries = append(entries, "$HOME/go/bin")
+
+	return fmt.Sprintf(`export PATH="%s:$PATH"`, strings.Join(entries, ":"))
+}
+
+// parseListOutput splits version manager list output into versions and the active one.
+// marker is the character the tool uses to flag the current version.
+func parseListOutput(output, marker string) (versions []string, current string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "=>") {
+			continue
+		}
+
+		isCurrent := strings.Contains(line, marker) || strings.Contains(line, "current")
+		line = strings.TrimSpace(strings.NewReplacer(marker, "", "(current)", "", "current", "").Replace(line))
+		if line == "" {
+			continue
+		}
+
+		versions = append(versions, line)
+		if isCurrent {
+			current = line
+		}
+	}
+	return versions, current
+}
@@ -0,0 +1,121 @@
+package backend
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+func TestParseListOutput(t *testing.T) {
+	t.Run("gobrew marks current with an asterisk", func(t *testing.T) {
+		versions, current := parseListOutput("1.20.14\n1.21.5*\n\n", "*")
+
+		if !reflect.DeepEqual(versions, []string{"1.20.14", "1.21.5"}) {
+			t.Errorf("unexpected versions %v", versions)
+		}
+		if current != "1.21.5" {
+			t.Errorf("expected current 1.21.5, got %q", current)
+		}
+	})
+
+	t.Run("g marks current with an arrow", func(t *testing.T) {
+		versions, current := parseListOutput("  1.19.2\n> 1.21.0\n", ">")
+
+		if !reflect.DeepEqual(versions, []string{"1.19.2", "1.21.0"}) {
+			t.Errorf("unexpected versions %v", versions)
+		}
+		if current != "1.21.0" {
+			t.Errorf("expected current 1.21.0, got %q", current)
+		}
+	})
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv(common.HomeVar, t.TempDir())
+	t.Setenv("PATH", "")
+
+	t.Run("explicit configuration wins", func(t *testing.T) {
+		t.Setenv("GOS_BACKEND", "gobrew")
+
+		b, err := Detect()
+		if err != nil {
+			t.Fatalf("Detect failed: %v", err)
+		}
+		if b.Name() != "gobrew" {
+			t.Errorf("expected gobrew, got %s", b.Name())
+		}
+	})
+
+	t.Run("unknown backend is rejected", func(t *testing.T) {
+		t.Setenv("GOS_BACKEND", "gvm")
+
+		if _, err := Detect(); err == nil {
+			t.Error("expected an error for an unknown backend")
+		}
+	})
+
+	t.Run("falls back to native", func(t *testing.T) {
+		t.Setenv("GOS_BACKEND", "")
+
+		b, err := Detect()
+		if err != nil {
+			t.Fatalf("Detect failed: %v", err)
+		}
+		if b.Name() != "native" {
+			t.Errorf("expected native, got %s", b.Name())
+		}
+	})
+}
+
+func TestGobrew(t *testing.T) {
+	g := &Gobrew{home: "/home/gopher/.gobrew"}
+
+	t.Run("installed versions and current", func(t *testing.T) {
+		fake := runner.NewFake().On("gobrew ls", runner.Response{Stdout: "1.21.5\n1.22.1*\n"})
+		defer runner.Replace(fake)()
+
+		versions, err := g.ListInstalled()
+		if err != nil || !reflect.DeepEqual(versions, []string{"1.21.5", "1.22.1"}) {
+			t.Errorf("ListInstalled() = %v, %v", versions, err)
+		}
+		if current, err := g.Current(); err != nil || current != "1.22.1" {
+			t.Errorf("Current() = %q, %v", current, err)
+		}
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		fake := runner.NewFake()
+		defer runner.Replace(fake)()
+
+		if versions, err := g.ListInstalled(); err != nil || len(versions) != 0 {
+			t.Errorf("ListInstalled() = %v, %v", versions, err)
+		}
+		if len(fake.Calls()) != 0 {
+			t.Errorf("unexpected commands %v", fake.Lines())
+		}
+	})
+
+	t.Run("install failure explains itself", func(t *testing.T) {
+		fake := runner.NewFake().On("gobrew install 1.99.0", runner.Response{Stderr: "version 1.99.0 not found\n", ExitCode: 1})
+		defer runner.Replace(fake)()
+
+		err := g.Install("1.99.0")
+		if err == nil || !strings.Contains(err.Error(), "version 1.99.0 not found") {
+			t.Errorf("Install() error = %v", err)
+		}
+	})
+
+	t.Run("remote versions", func(t *testing.T) {
+		fake := runner.NewFake().On("gobrew ls-remote", runner.Response{Stdout: "1.21\t1.21.0  1.21.1\n1.22\t1.22.0\n"})
+		defer runner.Replace(fake)()
+
+		versions, err := g.ListRemote()
+		want := []string{"1.21", "1.21.0", "1.21.1", "1.22", "1.22.0"}
+		if err != nil || !reflect.DeepEqual(versions, want) {
+			t.Errorf("ListRemote() = %v, %v", versions, err)
+		}
+	})
+}
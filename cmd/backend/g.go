@@ -0,0 +1,119 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+// G drives the stefanmaric/g version manager (~/.g)
+type G struct {
+	home string
+}
+
+// NewG creates the g backend
+func NewG() *G {
+	return &G{home: filepath.Join(common.GetHomeDir(), ".g")}
+}
+
+// Name implements Backend
+func (g *G) Name() string { return "g" }
+
+// Available implements Backend
+func (g *G) Available() bool {
+	return g.binary() != ""
+}
+
+// binary locates the g executable, which is often not on PATH
+func (g *G) binary() string {
+	if path, err := runner.LookPath("g"); err == nil {
+		return path
+	}
+	candidates := []string{
+		filepath.Join(g.home, "bin", "g"),
+		filepath.Join(common.GetHomeDir(), "go", "bin", "g"),
+		common.UsrLocalBinG,
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Install implements Backend
+func (g *G) Install(version string) error {
+	return runner.Run(g.binary(), "install", version, "--non-interactive")
+}
+
+// Uninstall implements Backend
+func (g *G) Uninstall(version string) error {
+	return runner.Run(g.binary(), "remove", version, "--non-interactive")
+}
+
+// Use implements Backend
+func (g *G) Use(version string) error {
+	return runner.Run(g.binary(), "set", version)
+}
+
+// ListInstalled implements Backend
+func (g *G) ListInstalled() ([]string, error) {
+	if !g.Available() {
+		return []string{}, nil
+	}
+	output, err := runner.Output(g.binary(), "list")
+	if err != nil {
+		return nil, err
+	}
+	versions, _ := parseListOutput(output, ">")
+	return versions, nil
+}
+
+// ListRemote implements Backend
+func (g *G) ListRemote() ([]string, error) {
+	output, err := runner.Output(g.binary(), "list-all")
+	if err != nil {
+		return nil, err
+	}
+	versions, _ := parseListOutput(output, ">")
+	return versions, nil
+}
+
+// Current implements Backend
+func (g *G) Current() (string, error) {
+	if target, err := os.Readlink(g.GOROOT("")); err == nil {
+		return filepath.Base(target), nil
+	}
+
+	output, err := runner.Output(g.binary(), "list")
+	if err != nil {
+		return "", err
+	}
+	if _, current := parseListOutput(output, ">"); current != "" {
+		return current, nil
+	}
+	return "", fmt.Errorf("g has no active version")
+}
+
+// Root implements Backend
+func (g *G) Root() string {
+	return g.home
+}
+
+// GOROOT implements Backend
+func (g *G) GOROOT(version string) string {
+	if version == "" {
+		return filepath.Join(g.home, "go")
+	}
+	return filepath.Join(g.home, "versions", strings.TrimPrefix(version, "go"))
+}
+
+// BinDirs implements Backend
+func (g *G) BinDirs() []string {
+	return []string{filepath.Join(g.home, "go", "bin"), filepath.Join(g.home, "bin")}
+}
@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+// Gobrew drives the kevincobain2000/gobrew version manager
+type Gobrew struct {
+	home string
+}
+
+// NewGobrew creates the gobrew backend (~/.gobrew)
+func NewGobrew() *Gobrew {
+	return &Gobrew{home: filepath.Join(common.GetHomeDir(), common.GobrewDir)}
+}
+
+// Name implements Backend
+func (g *Gobrew) Name() string { return "gobrew" }
+
+// Available implements Backend
+func (g *Gobrew) Available() bool {
+	return common.IsCommandAvailable("gobrew")
+}
+
+// Install implements Backend
+func (g *Gobrew) Install(version string) error {
+	return runner.Run("gobrew", "install", version)
+}
+
+// Uninstall implements Backend
+func (g *Gobrew) Uninstall(version string) error {
+	return runner.Run("gobrew", "uninstall", version)
+}
+
+// Use implements Backend
+func (g *Gobrew) Use(version string) error {
+	return runner.Run("gobrew", "use", version)
+}
+
+// ListInstalled implements Backend
+func (g *Gobrew) ListInstalled() ([]string, error) {
+	if !g.Available() {
+		return []string{}, nil
+	}
+	output, err := runner.Output("gobrew", "ls")
+	if err != nil {
+		return nil, err
+	}
+	versions, _ := parseListOutput(output, "*")
+	return versions, nil
+}
+
+// ListRemote implements Backend
+func (g *Gobrew) ListRemote() ([]string, error) {
+	output, err := runner.Output("gobrew", "ls-remote")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, line := range strings.Split(output, "\n") {
+		for _, field := range strings.Fields(line) {
+			if field[0] >= '0' && field[0] <= '9' {
+				versions = append(versions, field)
+			}
+		}
+	}
+	return versions, nil
+}
+
+// Current implements Backend
+func (g *Gobrew) Current() (string, error) {
+	output, err := runner.Output("gobrew", "ls")
+	if err != nil {
+		return "", err
+	}
+	if _, current := parseListOutput(output, "*"); current != "" {
+		return current, nil
+	}
+	return "", fmt.Errorf("gobrew has no active version")
+}
+
+// Root implements Backend
+func (g *Gobrew) Root() string {
+	return g.home
+}
+
+// GOROOT implements Backend
+func (g *Gobrew) GOROOT(version string) string {
+	if version == "" {
+		return filepath.Join(g.home, "current")
+	}
+	return filepath.Join(g.home, "versions", version, "go")
+}
+
+// BinDirs implements Backend
+func (g *Gobrew) BinDirs() []string {
+	return []string{filepath.Join(g.home, "current", "bin"), filepath.Join(g.home, "bin")}
+}
@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+)
+
+// Native downloads official Go archives itself, without any external tool
+type Native struct {
+	Installer *toolchain.Installer
+}
+
+// NewNative creates the native backend rooted at the gos home directory
+func NewNative() *Native {
+	return &Native{Installer: toolchain.NewInstaller()}
+}
+
+// Name implements Backend
+func (n *Native) Name() string { return "native" }
+
+// Available implements Backend; the native backend needs nothing external
+func (n *Native) Available() bool { return true }
+
+// Install implements Backend
+func (n *Native) Install(version string) error {
+	if version == "latest" {
+		latest, err := n.Installer.LatestVersion()
+		if err != nil {
+			return err
+		}
+		version = latest
+	}
+	return n.Installer.Install(version)
+}
+
+// Uninstall implements Backend
+func (n *Native) Uninstall(version string) error {
+	return n.Installer.Uninstall(version)
+}
+
+// Use implements Backend
+func (n *Native) Use(version string) error {
+	if version == "latest" {
+		installed, err := n.Installer.ListInstalled()
+		if err != nil {
+			return err
+		}
+		// Linked toolchains sort last but are not releases
+		version = ""
+		for i := len(installed) - 1; i >= 0 && version == ""; i-- {
+			if goversion.IsValid(installed[i]) {
+				version = installed[i]
+			}
+		}
+		if version == "" {
+			return fmt.Errorf("no Go versions installed")
+		}
+	}
+	return n.Installer.Use(version)
+}
+
+// ListInstalled implements Backend
+func (n *Native) ListInstalled() ([]string, error) {
+	return n.Installer.ListInstalled()
+}
+
+// ListRemote implements Backend
+func (n *Native) ListRemote() ([]string, error) {
+	return n.Installer.RemoteVersions()
+}
+
+// Current implements Backend
+func (n *Native) Current() (string, error) {
+	return n.Installer.Current()
+}
+
+// Root implements Backend
+func (n *Native) Root() string {
+	return n.Installer.Root
+}
+
+// GOROOT implements Backend
+func (n *Native) GOROOT(version string) string {
+	if version == "" {
+		return n.Installer.CurrentLink()
+	}
+	return n.Installer.VersionDir(version)
+}
+
+// BinDirs implements Backend; the shims directory comes first once `gos rehash` created it
+func (n *Native) BinDirs() []string {
+	dirs := []string{filepath.Join(n.GOROOT(""), "bin")}
+	if info, err := os.Stat(common.GetShimsDir()); err == nil && info.IsDir() {
+		dirs = append([]string{common.GetShimsDir()}, dirs...)
+	}
+	return dirs
+}
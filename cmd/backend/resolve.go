@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+)
+
+// ResolveRemote turns a version spec (1.21, ~1.22, ">=1.21 <1.23", stable,
+// oldstable) into a concrete version the backend can install. Exact versions
+// and "latest" are passed through without listing remote versions.
+func ResolveRemote(b Backend, spec string) (string, error) {
+	c, err := goversion.ParseConstraint(spec)
+	if err != nil {
+		return "", &errs.UsageError{Err: err}
+	}
+	if c.IsExact() {
+		return goversion.Normalize(spec), nil
+	}
+	if c.Channel() == goversion.Latest {
+		return goversion.Latest, nil
+	}
+
+	versions, err := b.ListRemote()
+	if err != nil {
+		return "", fmt.Errorf("listing available versions: %w", err)
+	}
+	version, err := goversion.Resolve(spec, versions)
+	if err != nil {
+		return "", &errs.VersionNotFoundError{Spec: spec}
+	}
+	return version, nil
+}
+
+// ResolveInstalled turns a version spec into one of the backend's installed
+// versions, spelled the way the backend lists it. Channels pick among the
+// installed versions: stable is the newest installed release. The name of
+// a linked toolchain (see gos link) matches only itself.
+func ResolveInstalled(b Backend, spec string) (string, error) {
+	installed, err := b.ListInstalled()
+	if err != nil {
+		return "", fmt.Errorf("listing installed versions: %w", err)
+	}
+	for _, version := range installed {
+		if version == strings.TrimSpace(spec) && !goversion.IsValid(version) {
+			return version, nil
+		}
+	}
+
+	c, err := goversion.ParseConstraint(spec)
+	if err != nil {
+		return "", &errs.UsageError{Err: err}
+	}
+
+	if c.IsExact() {
+		for _, version := range installed {
+			if goversion.Equal(version, spec) {
+				return version, nil
+			}
+		}
+		return "", &errs.VersionNotFoundError{Spec: goversion.Normalize(spec), Installed: true}
+	}
+
+	version, err := goversion.Resolve(spec, installed)
+	if err != nil {
+		return "", &errs.VersionNotFoundError{Spec: spec, Installed: true}
+	}
+	for _, candidate := range installed {
+		if goversion.Equal(candidate, version) {
+			return candidate, nil
+		}
+	}
+	return version, nil
+}
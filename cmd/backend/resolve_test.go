@@ -0,0 +1,60 @@
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+)
+
+func TestResolve(t *testing.T) {
+	testenv.Sandbox(t)
+	b := testenv.NewBackend("1.21.5", "1.22.0", "1.22.3", "corp-1.22-fips")
+	b.Remote = []string{"1.23rc1", "1.22.6", "1.22.3", "1.21.13", "1.21.5"}
+
+	t.Run("installed partial version picks the newest match", func(t *testing.T) {
+		got, err := backend.ResolveInstalled(b, "1.22")
+		if err != nil || got != "1.22.3" {
+			t.Errorf("expected 1.22.3, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("installed exact version accepts prefixes", func(t *testing.T) {
+		got, err := backend.ResolveInstalled(b, "go1.21.5")
+		if err != nil || got != "1.21.5" {
+			t.Errorf("expected 1.21.5, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("linked toolchain by name", func(t *testing.T) {
+		got, err := backend.ResolveInstalled(b, "corp-1.22-fips")
+		if err != nil || got != "corp-1.22-fips" {
+			t.Errorf("expected corp-1.22-fips, got %q (%v)", got, err)
+		}
+		if got, _ := backend.ResolveInstalled(b, "stable"); got != "1.22.3" {
+			t.Errorf("expected stable 1.22.3, got %q", got)
+		}
+	})
+
+	t.Run("missing exact version", func(t *testing.T) {
+		if _, err := backend.ResolveInstalled(b, "1.20.1"); err == nil {
+			t.Error("expected an error for a version that is not installed")
+		}
+	})
+
+	t.Run("remote channels", func(t *testing.T) {
+		if got, _ := backend.ResolveRemote(b, "stable"); got != "1.22.6" {
+			t.Errorf("expected stable 1.22.6, got %q", got)
+		}
+		if got, _ := backend.ResolveRemote(b, "oldstable"); got != "1.21.13" {
+			t.Errorf("expected oldstable 1.21.13, got %q", got)
+		}
+	})
+
+	t.Run("remote range", func(t *testing.T) {
+		got, err := backend.ResolveRemote(b, ">=1.21 <1.22")
+		if err != nil || got != "1.21.13" {
+			t.Errorf("expected 1.21.13, got %q (%v)", got, err)
+		}
+	})
+}
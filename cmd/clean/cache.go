@@ -1,18 +1,18 @@
 package clean
 
 import (
-	"fmt"
-	"os/exec"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
 // CleanGoCache cleans Go cache and modules
 func CleanGoCache() {
 	// Try to clean with go command if available
-	if _, err := exec.LookPath("go"); err == nil {
-		fmt.Println("  Running go clean -modcache...")
-		exec.Command("go", "clean", "-modcache").Run()
+	if runner.Available("go") {
+		ui.Note.Println("  Running go clean -modcache...")
+		runner.Run("go", "clean", "-modcache")
 		
-		fmt.Println("  Running go clean -cache...")
-		exec.Command("go", "clean", "-cache").Run()
+		ui.Note.Println("  Running go clean -cache...")
+		runner.Run("go", "clean", "-cache")
 	}
 }
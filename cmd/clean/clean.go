@@ -1,15 +1,16 @@
 package clean
 
 import (
-	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/cristobalcontreras/gos/cmd/backend"
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 )
@@ -25,9 +26,9 @@ func NewCleanCmd() *cobra.Command {
 - Manual system installations
 - User directories with special permissions
 - Shell configuration cleanup`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			force, _ := cmd.Flags().GetBool("force")
-			DeepCleanGo(force)
+			return DeepCleanGo(force)
 		},
 	}
 
@@ -40,10 +41,10 @@ func NewCleanCmd() *cobra.Command {
 		Long: `Clean your PATH environment variable from conflicting Go installations.
 This will generate a script or modify your shell configuration to remove
 multiple Go paths and keep only the version manager paths.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			interactive, _ := cmd.Flags().GetBool("interactive")
 			script, _ := cmd.Flags().GetBool("script")
-			CleanPathConflicts(interactive, script)
+			return CleanPathConflicts(interactive, script)
 		},
 	}
 
@@ -55,26 +56,21 @@ multiple Go paths and keep only the version manager paths.`,
 	return cmd
 }
 
-// DeepCleanGo performs comprehensive Go cleanup
-func DeepCleanGo(force bool) {
-	red := color.New(color.FgRed)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-
-	red.Println("🗑️  Complete Go system cleanup...")
+// DeepCleanGo performs comprehensive Go cleanup. Removal steps are best
+// effort; only failing to rewrite shell files is returned as an error.
+func DeepCleanGo(force bool) error {
+	ui.Problem.Println("🗑️  Complete Go system cleanup...")
 
 	if !force {
-		yellow.Println("\n⚠️  WARNING: This will remove ALL Go installations and configurations!")
-		fmt.Print("Are you sure you want to continue? (y/N): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(strings.ToLower(input))
-
-		if input != "y" && input != "yes" {
-			yellow.Println("Cleanup cancelled.")
-			return
+		ui.Warn.Println("\n⚠️  WARNING: This will remove ALL Go installations and configurations!")
+		ui.Hint.Println("💡 To keep toolchains from gvm, goenv, ~/sdk or Homebrew, run 'gos import' first.")
+		confirmed, err := ui.Confirm("Are you sure you want to continue?", false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Hint.Println("Cleanup cancelled.")
+			return nil
 		}
 	}
 
@@ -82,6 +78,7 @@ func DeepCleanGo(force bool) {
 	totalStages := 6
 	mainBar := progressbar.NewOptions(totalStages,
 		progressbar.OptionSetDescription("🧹 Deep cleanup progress"),
+		progressbar.OptionSetWriter(ui.Progress()),
 		progressbar.OptionSetWidth(50),
 		progressbar.OptionSetTheme(progressbar.Theme{
 			Saucer: "█", SaucerHead: "█", SaucerPadding: "░", BarStart: "[", BarEnd: "]",
@@ -89,103 +86,107 @@ func DeepCleanGo(force bool) {
 		progressbar.OptionShowCount(),
 	)
 
-	fmt.Println()
+	ui.Note.Println()
 
-	blue.Println("▸ Cleaning existing Go cache and modules…")
+	ui.Info.Println("▸ Cleaning existing Go cache and modules…")
 	CleanGoCache()
 	mainBar.Add(1)
 
-	blue.Println("\n▸ Removing Homebrew installations…")
-	CleanHomebrewGo()
+	// A sandboxed home leaves the machine-wide installations alone
+	ui.Info.Println("\n▸ Removing Homebrew installations…")
+	if common.Sandboxed() {
+		ui.Note.Println("  Skipped: GOS_HOME is set")
+	} else {
+		CleanHomebrewGo()
+	}
 	mainBar.Add(1)
 
-	blue.Println("\n▸ Removing manual system installations…")
-	CleanSystemGo()
+	ui.Info.Println("\n▸ Removing manual system installations…")
+	if common.Sandboxed() {
+		ui.Note.Println("  Skipped: GOS_HOME is set")
+	} else {
+		CleanSystemGo()
+	}
 	mainBar.Add(1)
 
-	blue.Println("\n▸ Removing user directories with special permissions…")
+	ui.Info.Println("\n▸ Removing user directories with special permissions…")
 	CleanUserDirectories()
 	mainBar.Add(1)
 
-	blue.Println("\n▸ Removing other managers and directories…")
+	ui.Info.Println("\n▸ Removing other managers and directories…")
 	CleanOtherManagers()
 	mainBar.Add(1)
 
-	blue.Println("\n▸ Cleaning shell configuration…")
-	CleanShellConfig()
+	ui.Info.Println("\n▸ Cleaning shell configuration…")
+	shellErr := CleanShellConfig()
 	mainBar.Add(1)
 
 	// Clear command hash
-	exec.Command("hash", "-r").Run()
+	runner.Run("hash", "-r")
 
 	mainBar.Finish()
-	fmt.Println()
-	green.Println("✅ Complete Go cleanup finished.")
-	yellow.Println("📋 Backups of your configuration files were created.")
-	yellow.Println("🔄 Run 'source ~/.zshrc' or open a new terminal.")
+	fmt.Fprintln(ui.Progress())
+	ui.Success.Println("✅ Complete Go cleanup finished.")
+	ui.Hint.Println("📋 Only the gos block was removed from your shell files; other lines were kept.")
+	ui.Hint.Println("🔄 Run 'source ~/.zshrc' or open a new terminal.")
+	return shellErr
 }
 
 // CleanPathConflicts handles PATH cleanup for Go installation conflicts
-func CleanPathConflicts(interactive, scriptOnly bool) {
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-
-	blue.Println("🧹 Go PATH Conflict Cleanup")
-	fmt.Println()
+func CleanPathConflicts(interactive, scriptOnly bool) error {
+	ui.Info.Println("🧹 Go PATH Conflict Cleanup")
+	ui.Note.Println()
 
 	// Check for conflicts
 	conflicts := detectPathConflicts()
 	if len(conflicts) == 0 {
-		green.Println("✅ No PATH conflicts detected!")
-		fmt.Println("💡 Your PATH appears to be clean.")
-		return
+		ui.Success.Println("✅ No PATH conflicts detected!")
+		ui.Note.Println("💡 Your PATH appears to be clean.")
+		return nil
 	}
 
 	// Show detected conflicts
-	yellow.Printf("⚠️  Detected %d conflicting Go paths in your environment:\n", len(conflicts))
+	ui.Caution.Printf("⚠️  Detected %d conflicting Go paths in your environment:\n", len(conflicts))
 	for _, conflict := range conflicts {
-		fmt.Printf("  • %s\n", conflict)
+		ui.Note.Printf("  • %s\n", conflict)
 	}
-	fmt.Println()
+	ui.Hint.Println("💡 'gos which go' shows which go each one holds and which one wins")
+	ui.Note.Println()
 
 	if scriptOnly {
 		// Clean current session PATH directly
 		cleanCurrentSessionPath()
-		return
+		return nil
 	}
 
 	if interactive {
-		// Ask user what they want to do
-		fmt.Println("Choose an option:")
-		fmt.Println("  1️⃣  Clean shell configuration files permanently")
-		fmt.Println("  2️⃣  Clean current session PATH only")
-		fmt.Println("  3️⃣  Show manual instructions")
-		fmt.Println("  4️⃣  Cancel")
-		fmt.Println()
-
-		blue.Print("Enter your choice (1/2/3/4): ")
-
-		var choice string
-		fmt.Scanln(&choice)
+		// Ask user what they want to do; --yes cleans the shell files,
+		// --no-input and unknown answers show the instructions
+		choice, err := ui.Choose("Choose an option:", []string{
+			"Clean shell configuration files permanently",
+			"Clean current session PATH only",
+			"Show manual instructions",
+			"Cancel",
+		}, 0, 2)
+		if err != nil {
+			return err
+		}
 
 		switch choice {
-		case "1":
-			cleanShellConfigFromPath()
-		case "2":
+		case 0:
+			return cleanShellConfigFromPath()
+		case 1:
 			cleanCurrentSessionPath()
-		case "3":
-			showPathCleanupInstructions()
-		case "4":
-			fmt.Println("⏭️  Cleanup cancelled.")
-		default:
-			yellow.Println("Invalid choice. Showing manual instructions...")
+		case 2:
 			showPathCleanupInstructions()
+		case 3:
+			ui.Note.Println("⏭️  Cleanup cancelled.")
 		}
 	} else {
 		// Non-interactive: auto-clean
-		cleanShellConfigFromPath()
+		return cleanShellConfigFromPath()
 	}
+	return nil
 }
 
 // detectPathConflicts finds conflicting Go paths in the current environment
@@ -222,8 +223,8 @@ func isConflictingGoPath(path string) bool {
 		"/snap/go",
 	}
 
-	// Always skip gobrew version manager paths - these are NOT conflicts
-	if strings.Contains(path, "/.gobrew/") {
+	// Always skip version manager paths - these are NOT conflicts
+	if strings.Contains(path, "/.gobrew/") || strings.Contains(path, "/.gos/") {
 		return false
 	}
 
@@ -252,89 +253,35 @@ func removeDuplicates(slice []string) []string {
 	return result
 }
 
-// cleanShellConfigFromPath automatically cleans shell configuration files
-func cleanShellConfigFromPath() {
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	blue := color.New(color.FgBlue)
-
-	blue.Println("🔧 Automatically cleaning shell configuration files...")
-	fmt.Println()
-
-	// First do the existing shell cleanup
-	CleanShellConfig()
-
-	// Then add the proper version manager path
-	addVersionManagerPathToShell()
-
-	fmt.Println()
-	green.Println("✅ Shell configuration cleaned successfully!")
-	yellow.Println("🔄 Please restart your terminal or run 'source ~/.zshrc' to apply changes.")
-}
-
-// addVersionManagerPathToShell adds the correct version manager path to shell config
-func addVersionManagerPathToShell() {
-	homeDir := common.GetHomeDir()
+// cleanShellConfigFromPath rewrites the gos block in the current shell's
+// startup file so it loads `gos init`, replacing older gos snippets
+func cleanShellConfigFromPath() error {
+	ui.Info.Println("🔧 Updating shell configuration...")
+	ui.Note.Println()
 
-	var shellFile string
-	var pathLine string
-
-	// Determine shell file and path
-	shell := os.Getenv("SHELL")
-	if strings.Contains(shell, "zsh") {
-		shellFile = filepath.Join(homeDir, ".zshrc")
-	} else if strings.Contains(shell, "bash") {
-		shellFile = filepath.Join(homeDir, ".bashrc")
-		if _, err := os.Stat(shellFile); os.IsNotExist(err) {
-			shellFile = filepath.Join(homeDir, ".bash_profile")
-		}
-	} else {
-		// Default to .zshrc on modern systems
-		shellFile = filepath.Join(homeDir, ".zshrc")
+	target := rcfile.CurrentTarget()
+	edit, err := rcfile.PlanInit(target)
+	if err == nil {
+		err = rcfile.Apply([]rcfile.Edit{edit})
 	}
-
-	// Use gobrew as the version manager
-	pathLine = `export PATH="$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$HOME/go/bin:$PATH"`
-
-	// Read existing content
-	content := ""
-	if data, err := os.ReadFile(shellFile); err == nil {
-		content = string(data)
-	}
-
-	// Check if path line already exists
-	if strings.Contains(content, pathLine) {
-		fmt.Printf("✅ Version manager path already configured in %s\n", filepath.Base(shellFile))
-		return
+	if err != nil {
+		ui.Warn.Printf("⚠️  Could not update %s: %v\n", target.Path, err)
+		ui.Hint.Println("Please add this line manually:")
+		ui.Note.Println("  " + target.Shell.InitLine())
+		return fmt.Errorf("updating %s: %w", target.Path, err)
 	}
+	reportGoConfig(target.Path, edit.After)
 
-	// Add the path line
-	if content != "" && !strings.HasSuffix(content, "\n") {
-		content += "\n"
-	}
-	content += "\n# Go Version Manager PATH\n"
-	content += pathLine + "\n"
-
-	// Write back
-	if err := os.WriteFile(shellFile, []byte(content), 0644); err != nil {
-		yellow := color.New(color.FgYellow)
-		yellow.Printf("⚠️  Could not write to %s: %v\n", shellFile, err)
-		yellow.Println("Please add this line manually:")
-		fmt.Println("  " + pathLine)
-	} else {
-		green := color.New(color.FgGreen)
-		green.Printf("✅ Added version manager path to %s\n", filepath.Base(shellFile))
-	}
+	ui.Note.Println()
+	ui.Success.Println("✅ Shell configuration updated successfully!")
+	ui.Hint.Printf("🔄 Please restart your terminal or run 'source %s' to apply changes.\n", target.Path)
+	return nil
 }
 
 // cleanCurrentSessionPath cleans the PATH of the current session directly
 func cleanCurrentSessionPath() {
-	green := color.New(color.FgGreen)
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
-
-	blue.Println("🧹 Cleaning PATH for current session...")
-	fmt.Println()
+	ui.Info.Println("🧹 Cleaning PATH for current session...")
+	ui.Note.Println()
 
 	// Get current PATH
 	currentPath := os.Getenv("PATH")
@@ -346,13 +293,9 @@ func cleanCurrentSessionPath() {
 	pathParts := strings.Split(currentPath, pathSep)
 	var cleanedParts []string
 
-	// Use gobrew version manager paths
+	// Use the active backend's paths
 	homeDir := common.GetHomeDir()
-	vmPaths := []string{
-		filepath.Join(homeDir, ".gobrew", "current", "bin"),
-		filepath.Join(homeDir, ".gobrew", "bin"),
-		filepath.Join(homeDir, "go", "bin"),
-	}
+	vmPaths := append(activeBackend().BinDirs(), filepath.Join(homeDir, "go", "bin"))
 
 	// Add version manager paths first
 	for _, vmPath := range vmPaths {
@@ -368,7 +311,7 @@ func cleanCurrentSessionPath() {
 
 		// Skip conflicting Go installations
 		if isConflictingGoPath(part) {
-			yellow.Printf("  Removing conflicting path: %s\n", part)
+			ui.Hint.Printf("  Removing conflicting path: %s\n", part)
 			continue
 		}
 
@@ -394,49 +337,58 @@ func cleanCurrentSessionPath() {
 	// Set the new PATH for the current process
 	os.Setenv("PATH", newPath)
 
-	fmt.Println()
-	green.Println("✅ PATH cleaned for current session!")
+	ui.Note.Println()
+	ui.Success.Println("✅ PATH cleaned for current session!")
 
 	// Test Go version
-	blue.Println("� Testing Go installation:")
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		green.Printf("✅ %s\n", strings.TrimSpace(string(output)))
+	ui.Info.Println("� Testing Go installation:")
+	if output, err := runner.Output("go", "version"); err == nil {
+		ui.Success.Printf("✅ %s\n", strings.TrimSpace(output))
 	} else {
-		yellow.Println("⚠️  Go not found in cleaned PATH")
+		ui.Warn.Println("⚠️  Go not found in cleaned PATH")
 	}
 
-	fmt.Println()
-	yellow.Println("� This change is temporary for this session only.")
-	yellow.Println("💡 For permanent changes, use option 1 (Clean shell configuration files).")
-	fmt.Println()
+	ui.Note.Println()
+	ui.Hint.Println("� This change is temporary for this session only.")
+	ui.Hint.Println("💡 For permanent changes, use option 1 (Clean shell configuration files).")
+	ui.Note.Println()
 }
 
 // showPathCleanupInstructions shows manual cleanup instructions
 func showPathCleanupInstructions() {
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-
-	fmt.Println()
-	blue.Println("📋 Manual PATH Cleanup Instructions:")
-	fmt.Println()
-
-	yellow.Println("1️⃣  Edit your shell configuration file:")
-	fmt.Println("   nano ~/.zshrc     # for zsh")
-	fmt.Println("   nano ~/.bashrc    # for bash")
-	fmt.Println()
-
-	yellow.Println("2️⃣  Remove these conflicting lines:")
-	fmt.Println("   # export PATH=/usr/local/go/bin:$PATH")
-	fmt.Println("   # export PATH=$HOME/sdk/go*/bin:$PATH")
-	fmt.Println("   # export PATH=/opt/go/bin:$PATH")
-	fmt.Println()
-
-	yellow.Println("3️⃣  Add only the gobrew version manager path:")
-	green.Println("   export PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$HOME/go/bin:$PATH\"")
-	fmt.Println()
-
-	yellow.Println("4️⃣  Save and reload:")
-	fmt.Println("   source ~/.zshrc")
-	fmt.Println()
+	ui.Note.Println()
+	ui.Info.Println("📋 Manual PATH Cleanup Instructions:")
+	ui.Note.Println()
+
+	ui.Hint.Println("1️⃣  Edit your shell configuration file:")
+	ui.Note.Println("   nano ~/.zshrc     # for zsh")
+	ui.Note.Println("   nano ~/.bashrc    # for bash")
+	ui.Note.Println()
+
+	ui.Hint.Println("2️⃣  Remove these conflicting lines:")
+	ui.Note.Println("   # export PATH=/usr/local/go/bin:$PATH")
+	ui.Note.Println("   # export PATH=$HOME/sdk/go*/bin:$PATH")
+	ui.Note.Println("   # export PATH=/opt/go/bin:$PATH")
+	ui.Note.Println()
+
+	ui.Hint.Println("3️⃣  Add only the version manager path:")
+	ui.Success.Println("   " + activePathLine())
+	ui.Note.Println()
+
+	ui.Hint.Println("4️⃣  Save and reload:")
+	ui.Note.Println("   source ~/.zshrc")
+	ui.Note.Println()
+}
+
+// activeBackend returns the detected backend, falling back to native when the configuration is invalid
+func activeBackend() backend.Backend {
+	if b, err := backend.Detect(); err == nil {
+		return b
+	}
+	return backend.NewNative()
+}
+
+// activePathLine returns the PATH assignment for the active backend
+func activePathLine() string {
+	return backend.PathLine(activeBackend())
 }
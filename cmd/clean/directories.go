@@ -1,13 +1,14 @@
 package clean
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
 	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
 // CleanUserDirectories removes user Go directories
@@ -16,12 +17,12 @@ func CleanUserDirectories() {
 	
 	// Clean ~/go directory
 	goDir := filepath.Join(homeDir, "go")
-	if _, err := os.Stat(goDir); err == nil {
-		fmt.Printf("  Fixing permissions in %s...\n", goDir)
+	if fsys.Exists(goDir) {
+		ui.Note.Printf("  Fixing permissions in %s...\n", goDir)
 		FixPermissions(goDir)
-		if err := os.RemoveAll(goDir); err != nil {
-			fmt.Printf("  Using sudo to remove %s...\n", goDir)
-			exec.Command("sudo", "rm", "-rf", goDir).Run()
+		if err := fsys.RemoveAll(goDir); err != nil {
+			ui.Note.Printf("  Using sudo to remove %s...\n", goDir)
+			runner.Run("sudo", "rm", "-rf", goDir)
 		}
 	}
 
@@ -32,10 +33,10 @@ func CleanUserDirectories() {
 	}
 
 	for _, cacheDir := range cacheDirs {
-		if _, err := os.Stat(cacheDir); err == nil {
-			fmt.Printf("  Removing cache: %s\n", cacheDir)
+		if fsys.Exists(cacheDir) {
+			ui.Note.Printf("  Removing cache: %s\n", cacheDir)
 			FixPermissions(cacheDir)
-			os.RemoveAll(cacheDir)
+			fsys.RemoveAll(cacheDir)
 		}
 	}
 }
@@ -58,20 +59,20 @@ func CleanOtherManagers() {
 
 // cleanManagerDirectory removes a specific manager directory
 func cleanManagerDirectory(dir string) {
-	if _, err := os.Stat(dir); err != nil {
+	if !fsys.Exists(dir) {
 		return // Directory doesn't exist
 	}
 
 	if strings.HasSuffix(dir, "sdk") {
 		cleanSdkDirectory(dir)
 	} else {
-		os.RemoveAll(dir)
+		fsys.RemoveAll(dir)
 	}
 }
 
 // cleanSdkDirectory removes only Go-related directories from SDK folder
 func cleanSdkDirectory(sdkDir string) {
-	entries, err := os.ReadDir(sdkDir)
+	entries, err := fsys.ReadDir(sdkDir)
 	if err != nil {
 		return
 	}
@@ -79,7 +80,7 @@ func cleanSdkDirectory(sdkDir string) {
 	for _, entry := range entries {
 		if strings.HasPrefix(entry.Name(), "go") {
 			goSdkDir := filepath.Join(sdkDir, entry.Name())
-			os.RemoveAll(goSdkDir)
+			fsys.RemoveAll(goSdkDir)
 		}
 	}
 }
@@ -87,15 +88,11 @@ func cleanSdkDirectory(sdkDir string) {
 // FixPermissions recursively fixes permissions for directories and files
 func FixPermissions(dir string) {
 	// Recursively fix permissions
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		
+	fsys.Walk(dir, func(path string, info fs.FileInfo) error {
 		if info.IsDir() {
-			os.Chmod(path, 0755)
-		} else {
-			os.Chmod(path, 0644)
+			fsys.Chmod(path, 0755)
+		} else if info.Mode().IsRegular() {
+			fsys.Chmod(path, 0644)
 		}
 		return nil
 	})
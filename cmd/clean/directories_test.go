@@ -0,0 +1,44 @@
+package clean
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+)
+
+func TestCleanOtherManagers(t *testing.T) {
+	home, mem, _ := testenv.Sandbox(t, ".gvm/scripts", ".g/versions", "sdk/go1.21.5/bin", "sdk/android", ".gobrew/bin")
+
+	CleanOtherManagers()
+
+	for _, removed := range []string{".gvm", ".g", "sdk/go1.21.5"} {
+		if _, err := mem.Stat(filepath.Join(home, removed)); err == nil {
+			t.Errorf("%s was not removed", removed)
+		}
+	}
+	for _, kept := range []string{"sdk/android", ".gobrew/bin"} {
+		if _, err := mem.Stat(filepath.Join(home, kept)); err != nil {
+			t.Errorf("%s should be kept: %v", kept, err)
+		}
+	}
+}
+
+func TestCleanUserDirectories(t *testing.T) {
+	home, mem, _ := testenv.Sandbox(t, "go/pkg/mod/golang.org", ".cache/go-build/00", ".cache/pip", "projects")
+	readOnly := filepath.Join(home, "go", "pkg", "mod", "golang.org", "go.mod")
+	mem.WriteFile(readOnly, []byte("module x\n"), 0444)
+
+	CleanUserDirectories()
+
+	for _, removed := range []string{"go", ".cache/go-build"} {
+		if _, err := mem.Stat(filepath.Join(home, removed)); err == nil {
+			t.Errorf("%s was not removed", removed)
+		}
+	}
+	for _, kept := range []string{".cache/pip", "projects"} {
+		if _, err := mem.Stat(filepath.Join(home, kept)); err != nil {
+			t.Errorf("%s should be kept: %v", kept, err)
+		}
+	}
+}
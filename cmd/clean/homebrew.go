@@ -1,31 +1,30 @@
 package clean
 
 import (
-	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
 // CleanHomebrewGo removes Go installations from Homebrew
 func CleanHomebrewGo() {
-	if _, err := exec.LookPath("brew"); err != nil {
+	if !runner.Available("brew") {
 		return
 	}
 
 	// Get list of Go formulas
-	cmd := exec.Command("brew", "list", "--formula")
-	output, err := cmd.Output()
+	output, err := runner.Output("brew", "list", "--formula")
 	if err != nil {
 		return
 	}
 
-	lines := strings.Split(string(output), "\n")
+	lines := strings.Split(output, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "go") && (line == "go" || strings.Contains(line, "go@")) {
-			fmt.Printf("  – brew uninstall %s\n", line)
-			uninstallCmd := exec.Command("brew", "uninstall", "--ignore-dependencies", "--force", line)
-			uninstallCmd.Run()
+			ui.Note.Printf("  – brew uninstall %s\n", line)
+			runner.Run("brew", "uninstall", "--ignore-dependencies", "--force", line)
 		}
 	}
 }
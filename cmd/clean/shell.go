@@ -2,73 +2,72 @@ package clean
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
-	"time"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// CleanShellConfig cleans Go configuration from shell files
-func CleanShellConfig() {
-	homeDir := common.GetHomeDir()
-	shellFiles := []string{
-		filepath.Join(homeDir, ".zshrc"),
-		filepath.Join(homeDir, ".bash_profile"),
-		filepath.Join(homeDir, ".bashrc"),
-	}
-
-	for _, shellFile := range shellFiles {
-		if _, err := os.Stat(shellFile); err == nil {
-			cleanShellFile(shellFile)
+// CleanShellConfig removes the gos block (and snippets older gos versions
+// appended) from shell files. Other Go-related lines belong to the user and
+// are only reported. Every file is tried; the first failure is returned.
+func CleanShellConfig() error {
+	var firstErr error
+	for _, target := range rcfile.Targets() {
+		if err := cleanShellFile(target.Path); err != nil {
+			ui.Warn.Printf("⚠️  %v\n", err)
+			if firstErr == nil {
+				firstErr = err
+			}
 		}
 	}
+	return firstErr
 }
 
-// cleanShellFile removes Go-related lines from a shell configuration file
-func cleanShellFile(filename string) {
-	// Create backup
-	timestamp := time.Now().Format("20060102_150405")
-	backupFile := fmt.Sprintf("%s.backup.%s", filename, timestamp)
-	
-	input, err := os.ReadFile(filename)
+// cleanShellFile removes the gos-managed configuration from one shell file
+func cleanShellFile(filename string) error {
+	edit, err := rcfile.PlanRemove(filename)
 	if err != nil {
-		return
+		return fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	if err := rcfile.Apply([]rcfile.Edit{edit}); err != nil {
+		return err
 	}
-	
-	os.WriteFile(backupFile, input, 0644)
 
-	// Filter out Go-related lines
-	lines := strings.Split(string(input), "\n")
-	var filteredLines []string
+	reportGoConfig(filename, edit.After)
+	return nil
+}
 
-	for _, line := range lines {
-		if !containsGoConfig(line) {
-			filteredLines = append(filteredLines, line)
+// reportGoConfig lists Go-related lines gos did not write, for manual review
+func reportGoConfig(filename, content string) {
+	var lines []string
+	for i, line := range strings.Split(content, "\n") {
+		if containsGoConfig(line) {
+			lines = append(lines, fmt.Sprintf("    %d: %s", i+1, strings.TrimSpace(line)))
 		}
 	}
+	if len(lines) == 0 {
+		return
+	}
 
-	output := strings.Join(filteredLines, "\n")
-	os.WriteFile(filename, []byte(output), 0644)
+	ui.Hint.Printf("  ℹ️  %s has Go-related lines gos does not manage; review them manually:\n", filepath.Base(filename))
+	for _, line := range lines {
+		ui.Note.Println(line)
+	}
 }
 
+// goConfigPattern matches Go settings; paths must start at a directory
+// boundary, so "mongo/bin" is not mistaken for "go/bin"
+var goConfigPattern = regexp.MustCompile(`(^|[/\s"'=:$])(go/bin|\.gvm|\.goenv|\.g/bin)\b|\bGOPATH\b|\bGOROOT\b`)
+
 // containsGoConfig checks if a line contains Go-related configuration
 func containsGoConfig(line string) bool {
-	goPatterns := []string{
-		"go/bin",
-		"GOPATH",
-		"GOROOT",
-		".gvm",
-		".goenv",
-		".g/bin",
-		"Go Version Manager",
-	}
-
-	for _, pattern := range goPatterns {
-		if strings.Contains(line, pattern) {
-			return true
-		}
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return false
 	}
-	return false
+	return goConfigPattern.MatchString(trimmed)
 }
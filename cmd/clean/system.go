@@ -1,11 +1,11 @@
 package clean
 
 import (
-	"os/exec"
+	"github.com/cristobalcontreras/gos/cmd/runner"
 )
 
 // CleanSystemGo removes manual system installations
 func CleanSystemGo() {
 	// Remove manual system installations
-	exec.Command("sudo", "rm", "-rf", "/usr/local/go").Run()
+	runner.Run("sudo", "rm", "-rf", "/usr/local/go")
 }
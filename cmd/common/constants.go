@@ -9,4 +9,6 @@ const (
 	ProfileFile     = ".profile"
 	PowerShellProfile = "Documents/WindowsPowerShell/Microsoft.PowerShell_profile.ps1"
 	GobrewDir       = ".gobrew"
+	GosDir          = ".gos"
+	DefaultVersionFile = ".gos-default"
 )
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ExecProcess replaces the current process with binary, so stdin, signals and
+// the exit code belong to it directly. It only returns when exec fails.
+func ExecProcess(binary string, argv, env []string) int {
+	err := syscall.Exec(binary, argv, env)
+	fmt.Fprintf(os.Stderr, "gos: exec %s: %v\n", binary, err)
+	return 126
+}
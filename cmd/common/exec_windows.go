@@ -0,0 +1,33 @@
+//go:build windows
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// ExecProcess runs binary and returns its exit code. Windows has no exec, so
+// gos stays alive as a thin parent that ignores Ctrl+C and lets the child,
+// which shares the console, handle it.
+func ExecProcess(binary string, argv, env []string) int {
+	cmd := exec.Command(binary, argv[1:]...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "gos: run %s: %v\n", binary, err)
+		return 126
+	}
+	return 0
+}
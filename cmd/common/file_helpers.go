@@ -1,39 +1,37 @@
 package common
 
 import (
-	"bufio"
-	"os"
+	"errors"
+	"io/fs"
 	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/fsys"
 )
 
 // AppendToFile appends content to a file
 func AppendToFile(filename, content string) error {
-	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
+	data, err := fsys.ReadFile(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
-	defer file.Close()
 	
-	_, err = file.WriteString(content)
-	return err
+	return fsys.WriteFile(filename, append(data, content...), 0644)
 }
 
 // WriteToFile writes content to a file
 func WriteToFile(filename, content string) error {
-	return os.WriteFile(filename, []byte(content), 0644)
+	return fsys.WriteFile(filename, []byte(content), 0644)
 }
 
 // HasConfigContent checks if a file contains specific configuration content
 func HasConfigContent(filename, content string) bool {
-	file, err := os.Open(filename)
+	data, err := fsys.ReadFile(filename)
 	if err != nil {
 		return false
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		if strings.Contains(scanner.Text(), content) {
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, content) {
 			return true
 		}
 	}
@@ -2,22 +2,23 @@ package common
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
 // GetSystemGoInfo returns information about system Go installation
 func GetSystemGoInfo() (version string, goroot string, found bool) {
 	// Check if Go is installed directly
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		version = strings.TrimSpace(string(output))
+	if output, err := runner.Output("go", "version"); err == nil {
+		version = strings.TrimSpace(output)
 
 		// Try to get GOROOT to see where it's installed
-		if gorootOutput, err := exec.Command("go", "env", "GOROOT").Output(); err == nil {
-			goroot = strings.TrimSpace(string(gorootOutput))
+		if gorootOutput, err := runner.Output("go", "env", "GOROOT"); err == nil {
+			goroot = strings.TrimSpace(gorootOutput)
 		}
 
 		return version, goroot, true
@@ -26,110 +27,115 @@ func GetSystemGoInfo() (version string, goroot string, found bool) {
 	return "", "", false
 }
 
-// SetupGoEnvironment sets up the Go environment variables and PATH
-func SetupGoEnvironment() {
-	green := color.New(color.FgGreen)
-
-	homeDir := GetHomeDir()
-	var expectedGoroot, expectedGopath string
-
-	// Prefer gobrew, fallback to .g
-	if IsCommandAvailable("gobrew") {
-		expectedGoroot = filepath.Join(homeDir, ".gobrew", "current")
-		expectedGopath = filepath.Join(homeDir, "go")
-	} else {
-		expectedGoroot = filepath.Join(homeDir, ".g", "go")
-		expectedGopath = filepath.Join(homeDir, "go")
-	}
+// SetupGoEnvironment sets up the Go environment variables and PATH for the
+// given GOROOT and version manager bin directories
+func SetupGoEnvironment(goroot string, binDirs []string) {
+	expectedGopath := filepath.Join(GetHomeDir(), "go")
 
 	// Set environment variables for current session
 	os.Setenv("GOPATH", expectedGopath)
-	os.Setenv("GOROOT", expectedGoroot)
+	os.Setenv("GOROOT", goroot)
 
 	// Update PATH for current session
-	if UpdatePathForGoEnvironment() {
-		green.Println("✅ PATH updated for current session")
+	if UpdatePathForGoEnvironment(binDirs) {
+		ui.Success.Println("✅ PATH updated for current session")
 	}
 }
 
 // VerifyGoInstallation verifies that Go is available and shows version
 func VerifyGoInstallation() bool {
-	red := color.New(color.FgRed)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-
-	if _, err := exec.LookPath("go"); err != nil {
-		red.Println("❌ Go not found in PATH")
-		yellow.Println("💡 You may need to restart your terminal or run:")
-		yellow.Println("   source ~/.zshrc")
+	if !runner.Available("go") {
+		ui.Problem.Println("❌ Go not found in PATH")
+		ui.Hint.Println("💡 You may need to restart your terminal or run:")
+		ui.Hint.Println("   source ~/.zshrc")
 		return false
 	}
 
 	// Show Go version
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		version := strings.TrimSpace(string(output))
-		green.Printf("✅ %s\n", version)
+	if output, err := runner.Output("go", "version"); err == nil {
+		version := strings.TrimSpace(output)
+		ui.Success.Printf("✅ %s\n", version)
 	}
 
 	return true
 }
 
 // VerifyGoEnvironmentPaths verifies GOROOT and GOPATH settings
-func VerifyGoEnvironmentPaths() {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-
+func VerifyGoEnvironmentPaths(expectedGoroot string) {
 	homeDir := GetHomeDir()
-	expectedGoroot := filepath.Join(homeDir, ".g", "go")
 	expectedGopath := filepath.Join(homeDir, "go")
 
 	// Verify GOROOT
-	if output, err := exec.Command("go", "env", "GOROOT").Output(); err == nil {
-		goroot := strings.TrimSpace(string(output))
+	if output, err := runner.Output("go", "env", "GOROOT"); err == nil {
+		goroot := strings.TrimSpace(output)
 		if goroot == expectedGoroot {
-			green.Printf("✅ GOROOT: %s\n", goroot)
+			ui.Success.Printf("✅ GOROOT: %s\n", goroot)
 		} else {
-			yellow.Printf("⚠️  GOROOT: %s (expected: %s)\n", goroot, expectedGoroot)
+			ui.Caution.Printf("⚠️  GOROOT: %s (expected: %s)\n", goroot, expectedGoroot)
 		}
 	}
 
 	// Verify GOPATH
-	if output, err := exec.Command("go", "env", "GOPATH").Output(); err == nil {
-		gopath := strings.TrimSpace(string(output))
+	if output, err := runner.Output("go", "env", "GOPATH"); err == nil {
+		gopath := strings.TrimSpace(output)
 		if gopath == expectedGopath {
-			green.Printf("✅ GOPATH: %s\n", gopath)
+			ui.Success.Printf("✅ GOPATH: %s\n", gopath)
 		} else {
-			yellow.Printf("⚠️  GOPATH: %s (expected: %s)\n", gopath, expectedGopath)
+			ui.Caution.Printf("⚠️  GOPATH: %s (expected: %s)\n", gopath, expectedGopath)
 		}
 	}
 }
 
-// DisplayCurrentGoVersion displays the current Go version with GOROOT and GOPATH
-func DisplayCurrentGoVersion() {
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
+// GoInfo describes the go binary found in PATH; it is the result of gos version
+type GoInfo struct {
+	Available bool   `json:"available"`          // a go binary was found in PATH
+	Version   string `json:"version,omitempty"`  // release number, e.g. 1.21.5
+	Platform  string `json:"platform,omitempty"` // GOOS/GOARCH the binary targets
+	Binary    string `json:"binary,omitempty"`   // path of the go binary
+	GOROOT    string `json:"goroot,omitempty"`
+	GOPATH    string `json:"gopath,omitempty"`
+}
 
-	blue.Println("📍 Current Go version:")
+// CurrentGo inspects the go binary the current PATH resolves to
+func CurrentGo() GoInfo {
+	binary, err := runner.LookPath("go")
+	if err != nil {
+		return GoInfo{}
+	}
+	info := GoInfo{Available: true, Binary: binary}
 
-	goCmd := exec.Command("go", "version")
-	if err := goCmd.Run(); err != nil {
-		yellow.Println("⚠️  Go is not available in PATH")
-		return
+	if output, err := runner.Output(binary, "version"); err == nil {
+		info.Version = goversion.FromGoVersionOutput(output)
+		if fields := strings.Fields(output); len(fields) > 0 {
+			info.Platform = fields[len(fields)-1]
+		}
+	}
+	if output, err := runner.Output(binary, "env", "GOROOT", "GOPATH"); err == nil {
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) == 2 {
+			info.GOROOT = strings.TrimSpace(lines[0])
+			info.GOPATH = strings.TrimSpace(lines[1])
+		}
 	}
+	return info
+}
 
-	goCmd.Stdout = os.Stdout
-	goCmd.Run()
+// DisplayCurrentGoVersion displays the current Go version with GOROOT and GOPATH
+func DisplayCurrentGoVersion(info GoInfo) {
+	ui.Title.Println("📍 Current Go version:")
 
-	// Show GOROOT and GOPATH
-	if gorootCmd := exec.Command("go", "env", "GOROOT"); gorootCmd.Run() == nil {
-		blue.Print("📂 GOROOT: ")
-		gorootCmd.Stdout = os.Stdout
-		gorootCmd.Run()
+	if !info.Available {
+		ui.Caution.Println("⚠️  Go is not available in PATH")
+		return
 	}
 
-	if gopathCmd := exec.Command("go", "env", "GOPATH"); gopathCmd.Run() == nil {
-		blue.Print("📂 GOPATH: ")
-		gopathCmd.Stdout = os.Stdout
-		gopathCmd.Run()
+	ui.Out.Printf("go version go%s %s\n", info.Version, info.Platform)
+	if info.GOROOT != "" {
+		ui.Title.Print("📂 GOROOT: ")
+		ui.Out.Println(info.GOROOT)
+	}
+	if info.GOPATH != "" {
+		ui.Title.Print("📂 GOPATH: ")
+		ui.Out.Println(info.GOPATH)
 	}
 }
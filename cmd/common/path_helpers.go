@@ -1,23 +1,45 @@
 package common
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// GetHomeDir gets the user's home directory
+// HomeVar makes gos treat another directory as the home directory, so that
+// shell files, ~/go and ~/.gos are all read and written below it
+const HomeVar = "GOS_HOME"
+
+// GetHomeDir gets the user's home directory, or GOS_HOME when it is set
 func GetHomeDir() string {
+	if home := os.Getenv(HomeVar); home != "" {
+		return home
+	}
 	if runtime.GOOS == "windows" {
 		return os.Getenv("USERPROFILE")
 	}
 	return os.Getenv("HOME")
 }
 
+// Sandboxed reports whether GOS_HOME replaces the home directory
+func Sandboxed() bool {
+	return os.Getenv(HomeVar) != ""
+}
+
+// GetGosHome returns the gos-owned state directory (~/.gos)
+func GetGosHome() string {
+	return filepath.Join(GetHomeDir(), GosDir)
+}
+
+// GetShimsDir returns the directory holding the per-directory version shims
+func GetShimsDir() string {
+	return filepath.Join(GetGosHome(), "shims")
+}
+
 // UpdatePathForGobrew updates PATH for gobrew version manager
 func UpdatePathForGobrew(homeDir string) {
 	currentPath := os.Getenv("PATH")
@@ -47,17 +69,13 @@ func UpdatePathForGobrew(homeDir string) {
 	os.Setenv("PATH", newPath)
 }
 
-// UpdatePathForGoEnvironment updates PATH with required Go paths
-func UpdatePathForGoEnvironment() bool {
+// UpdatePathForGoEnvironment updates PATH with the version manager's bin
+// directories and $GOPATH/bin
+func UpdatePathForGoEnvironment(binDirs []string) bool {
 	currentPath := os.Getenv("PATH")
 	homeDir := GetHomeDir()
 
-	// Use gobrew paths
-	requiredPaths := []string{
-		filepath.Join(homeDir, ".gobrew", "current", "bin"),
-		filepath.Join(homeDir, ".gobrew", "bin"),
-		filepath.Join(homeDir, "go", "bin"),
-	}
+	requiredPaths := append(append([]string{}, binDirs...), filepath.Join(homeDir, "go", "bin"))
 
 	newPaths := []string{}
 	for _, reqPath := range requiredPaths {
@@ -69,8 +87,7 @@ func UpdatePathForGoEnvironment() bool {
 	if len(newPaths) > 0 {
 		newPath := strings.Join(newPaths, ":") + ":" + currentPath
 		os.Setenv("PATH", newPath)
-		green := color.New(color.FgGreen)
-		green.Printf("✅ Updated PATH with: %s\n", strings.Join(newPaths, ", "))
+		ui.Success.Printf("✅ Updated PATH with: %s\n", strings.Join(newPaths, ", "))
 		return true
 	}
 
@@ -93,6 +110,7 @@ func CleanGoPathsFromEnvironment() {
 		"/usr/local/go/bin", // System-wide Go installation
 		"/.g/",              // g version manager
 		"/gobrew/",          // gobrew version manager
+		"/.gos/",            // gos native installer
 		"/sdk/go",           // Go SDK installations
 		"/golang/",          // Other Go installations
 		"/versions/go",      // Versioned Go installations
@@ -125,7 +143,7 @@ func CleanGoPathsFromEnvironment() {
 }
 
 // UpdatePathForVersionManagerClean cleanly updates PATH for version managers
-func UpdatePathForVersionManagerClean() {
+func UpdatePathForVersionManagerClean(binDirs []string) {
 	// First, clean all Go paths
 	CleanGoPathsFromEnvironment()
 
@@ -136,12 +154,8 @@ func UpdatePathForVersionManagerClean() {
 		pathSep = ";"
 	}
 
-	var newGoPaths []string
-
-	// Use gobrew paths
-	gobrewCurrentBin := filepath.Join(homeDir, GobrewDir, "current", "bin")
-	gobrewBin := filepath.Join(homeDir, GobrewDir, "bin")
-	newGoPaths = append(newGoPaths, gobrewCurrentBin, gobrewBin)
+	// Use the selected version manager's paths
+	newGoPaths := append([]string{}, binDirs...)
 
 	// Add user's Go bin for installed packages
 	userGoBin := filepath.Join(homeDir, "go", "bin")
@@ -154,50 +168,40 @@ func UpdatePathForVersionManagerClean() {
 	}
 }
 
-// PromptUserForPathCleanup asks user if they want to clean their PATH and provides options
-func PromptUserForPathCleanup() {
-	fmt.Println()
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-
-	yellow.Println("🧹 PATH cleanup detected multiple Go installations!")
-	fmt.Println()
-	fmt.Println("Would you like to:")
-	fmt.Println("  1️⃣  Generate a cleanup script to run")
-	fmt.Println("  2️⃣  Show manual cleanup instructions")
-	fmt.Println("  3️⃣  Skip for now")
-	fmt.Println()
-
-	blue.Print("Enter your choice (1/2/3): ")
-
-	var choice string
-	fmt.Scanln(&choice)
+// PromptUserForPathCleanup asks user if they want to clean their PATH and provides options.
+// pathLine is the shell PATH assignment for the active version manager.
+func PromptUserForPathCleanup(pathLine string) error {
+	ui.Note.Println()
+	ui.Hint.Println("🧹 PATH cleanup detected multiple Go installations!")
+	ui.Note.Println()
+
+	// --yes generates the script; --no-input and unknown answers show the instructions
+	choice, err := ui.Choose("Would you like to:", []string{
+		"Generate a cleanup script to run",
+		"Show manual cleanup instructions",
+		"Skip for now",
+	}, 0, 1)
+	if err != nil {
+		return err
+	}
 
 	switch choice {
-	case "1":
-		generateCleanupScript()
-	case "2":
-		showManualCleanupInstructions()
-	case "3":
-		fmt.Println("⏭️  Skipping PATH cleanup for now.")
-	default:
-		green.Println("ℹ️  Invalid choice. Showing manual instructions...")
-		showManualCleanupInstructions()
+	case 0:
+		generateCleanupScript(pathLine)
+	case 1:
+		showManualCleanupInstructions(pathLine)
+	case 2:
+		ui.Note.Println("⏭️  Skipping PATH cleanup for now.")
 	}
+	return nil
 }
 
 // generateCleanupScript creates a script that user can source to clean their PATH
-func generateCleanupScript() {
+func generateCleanupScript(pathLine string) {
 	homeDir := GetHomeDir()
 	scriptPath := filepath.Join(homeDir, "clean-go-path.sh")
-
-	green := color.New(color.FgGreen)
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
-
-	// Use gobrew paths
-	vmPaths := `export PATH="$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$HOME/go/bin"`
+	// Use the selected version manager's paths
+	vmPaths := strings.TrimSuffix(pathLine, `:$PATH"`)
 
 	scriptContent := `#!/bin/bash
 # Go PATH Cleanup Script
@@ -257,60 +261,55 @@ echo "🔄 To restore original PATH: export PATH=\"$ORIGINAL_PATH\""
 `
 
 	// Write the script
-	if err := os.WriteFile(scriptPath, []byte(scriptContent), 0755); err != nil {
-		yellow.Printf("❌ Error creating script: %v\n", err)
-		fmt.Println("📋 Here are the manual commands instead:")
-		showManualCleanupInstructions()
+	if err := fsys.WriteFile(scriptPath, []byte(scriptContent), 0755); err != nil {
+		ui.Error.Printf("❌ Error creating script: %v\n", err)
+		ui.Note.Println("📋 Here are the manual commands instead:")
+		showManualCleanupInstructions(pathLine)
 		return
 	}
 
-	green.Printf("✅ Cleanup script created: %s\n", scriptPath)
-	fmt.Println()
-	blue.Println("🚀 To clean your PATH in this session, run:")
-	fmt.Printf("   source %s\n", scriptPath)
-	fmt.Println()
-	blue.Println("🔧 To make it permanent, add this to your ~/.zshrc or ~/.bashrc:")
-	pathLine := strings.TrimSuffix(strings.Replace(vmPaths, `export PATH="`, "", 1), `"`)
-	fmt.Printf("   %s:$PATH\n", pathLine)
-	fmt.Println()
-	yellow.Println("⚠️  Remember to restart your terminal or run 'source ~/.zshrc' after editing your shell config!")
+	ui.Success.Printf("✅ Cleanup script created: %s\n", scriptPath)
+	ui.Note.Println()
+	ui.Info.Println("🚀 To clean your PATH in this session, run:")
+	ui.Note.Printf("   source %s\n", scriptPath)
+	ui.Note.Println()
+	ui.Info.Println("🔧 To make it permanent, add this to your ~/.zshrc or ~/.bashrc:")
+	ui.Note.Printf("   %s\n", pathLine)
+	ui.Note.Println()
+	ui.Warn.Println("⚠️  Remember to restart your terminal or run 'source ~/.zshrc' after editing your shell config!")
 }
 
 // showManualCleanupInstructions displays detailed manual cleanup steps
-func showManualCleanupInstructions() {
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-
-	fmt.Println()
-	blue.Println("📋 Manual PATH Cleanup Instructions:")
-	fmt.Println()
+func showManualCleanupInstructions(pathLine string) {
+	ui.Note.Println()
+	ui.Info.Println("📋 Manual PATH Cleanup Instructions:")
+	ui.Note.Println()
 
-	yellow.Println("1️⃣  Edit your shell configuration file:")
+	ui.Hint.Println("1️⃣  Edit your shell configuration file:")
 	if runtime.GOOS == "darwin" || runtime.GOOS == "linux" {
-		fmt.Println("   nano ~/.zshrc     # for zsh")
-		fmt.Println("   nano ~/.bashrc    # for bash")
+		ui.Note.Println("   nano ~/.zshrc     # for zsh")
+		ui.Note.Println("   nano ~/.bashrc    # for bash")
 	} else {
-		fmt.Println("   Edit your shell profile file")
+		ui.Note.Println("   Edit your shell profile file")
 	}
-	fmt.Println()
-
-	yellow.Println("2️⃣  Remove or comment out these types of lines:")
-	fmt.Println("   # export PATH=/usr/local/go/bin:$PATH")
-	fmt.Println("   # export PATH=$HOME/sdk/go1.xx.x/bin:$PATH")
-	fmt.Println("   # export PATH=/opt/go/bin:$PATH")
-	fmt.Println()
-
-	yellow.Println("3️⃣  Add only the version manager path:")
-	green.Println("   export PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$HOME/go/bin:$PATH\"")
-	fmt.Println()
-
-	yellow.Println("4️⃣  Save the file and reload your shell:")
-	fmt.Println("   source ~/.zshrc   # or source ~/.bashrc")
-	fmt.Println()
-
-	blue.Println("🔍 After cleanup, verify with:")
-	fmt.Println("   go version")
-	fmt.Println("   which go")
-	fmt.Println()
+	ui.Note.Println()
+
+	ui.Hint.Println("2️⃣  Remove or comment out these types of lines:")
+	ui.Note.Println("   # export PATH=/usr/local/go/bin:$PATH")
+	ui.Note.Println("   # export PATH=$HOME/sdk/go1.xx.x/bin:$PATH")
+	ui.Note.Println("   # export PATH=/opt/go/bin:$PATH")
+	ui.Note.Println()
+
+	ui.Hint.Println("3️⃣  Add only the version manager path:")
+	ui.Success.Println("   " + pathLine)
+	ui.Note.Println()
+
+	ui.Hint.Println("4️⃣  Save the file and reload your shell:")
+	ui.Note.Println("   source ~/.zshrc   # or source ~/.bashrc")
+	ui.Note.Println()
+
+	ui.Info.Println("🔍 After cleanup, verify with:")
+	ui.Note.Println("   go version")
+	ui.Note.Println("   which go")
+	ui.Note.Println()
 }
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetHomeDir(t *testing.T) {
+	t.Run("GOS_HOME wins", func(t *testing.T) {
+		sandbox := t.TempDir()
+		t.Setenv(HomeVar, sandbox)
+
+		if got := GetHomeDir(); got != sandbox {
+			t.Errorf("GetHomeDir() = %q, want %q", got, sandbox)
+		}
+		if got, want := GetGosHome(), filepath.Join(sandbox, GosDir); got != want {
+			t.Errorf("GetGosHome() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("user home otherwise", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv(HomeVar, "")
+		t.Setenv("HOME", home)
+		t.Setenv("USERPROFILE", home)
+
+		if got := GetHomeDir(); got != home {
+			t.Errorf("GetHomeDir() = %q, want %q", got, home)
+		}
+	})
+}
@@ -2,9 +2,10 @@ package common
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
+
+	"github.com/cristobalcontreras/gos/cmd/runner"
 )
 
 // DetectCurrentShell detects the current shell being used
@@ -77,13 +78,10 @@ func ExecuteWithShell(command string) error {
 	if runtime.GOOS == "windows" {
 		// Try PowerShell first, then cmd
 		if IsCommandAvailable("powershell") {
-			cmd := exec.Command("powershell", "-Command", command)
-			return cmd.Run()
+			return runner.Run("powershell", "-Command", command)
 		}
-		cmd := exec.Command("cmd", "/C", command)
-		return cmd.Run()
+		return runner.Run("cmd", "/C", command)
 	}
 	// Unix-like systems
-	cmd := exec.Command("bash", "-c", command)
-	return cmd.Run()
+	return runner.Run("bash", "-c", command)
 }
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatBytes renders a size the way du -h does
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseBytes reads a size such as 512M, 1.5G, 10GB or 2GiB; units are
+// powers of 1024, like FormatBytes prints them
+func ParseBytes(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	if s != "" {
+		if exp := strings.IndexByte("KMGTPE", s[len(s)-1]); exp >= 0 {
+			for range exp + 1 {
+				multiplier *= 1024
+			}
+			s = s[:len(s)-1]
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q: use a number with an optional unit, e.g. 10G", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
@@ -0,0 +1,31 @@
+package common
+
+import "testing"
+
+func TestParseBytes(t *testing.T) {
+	for input, want := range map[string]int64{
+		"512":    512,
+		"10K":    10 << 10,
+		"512M":   512 << 20,
+		"1.5G":   1536 << 20,
+		"10GB":   10 << 30,
+		"2GiB":   2 << 30,
+		" 1t ":   1 << 40,
+		"1.5KiB": 1536,
+	} {
+		got, err := ParseBytes(input)
+		if err != nil || got != want {
+			t.Errorf("ParseBytes(%q) = %d, %v, want %d", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "G", "lots", "-1G", "1X"} {
+		if _, err := ParseBytes(input); err == nil {
+			t.Errorf("ParseBytes(%q) accepted a bad size", input)
+		}
+	}
+
+	if got := FormatBytes(1536 << 20); got != "1.5G" {
+		t.Errorf("FormatBytes() = %q", got)
+	}
+}
@@ -1,27 +1,14 @@
 package common
 
 import (
-	"os/exec"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/runner"
 )
 
 // IsCommandAvailable checks if a command is available in PATH
 func IsCommandAvailable(cmd string) bool {
-	_, err := exec.LookPath(cmd)
-	return err == nil
-}
-
-// CheckVersionManagerAvailable checks if gobrew version manager is available
-func CheckVersionManagerAvailable() bool {
-	if IsCommandAvailable("gobrew") {
-		return true
-	}
-
-	color.Red("❌ Error: No version manager is installed.")
-	color.Yellow("💡 Run first: gos setup")
-	return false
+	return runner.Available(cmd)
 }
 
 // IsGInstalled checks if the gobrew version manager is installed
@@ -31,17 +18,16 @@ func IsGInstalled() bool {
 
 // GetGobrewVersions returns installed versions using gobrew
 func GetGobrewVersions() []string {
-	if _, err := exec.LookPath("gobrew"); err != nil {
+	if !runner.Available("gobrew") {
 		return []string{}
 	}
 
-	cmd := exec.Command("gobrew", "ls")
-	output, err := cmd.Output()
+	output, err := runner.Output("gobrew", "ls")
 	if err != nil {
 		return []string{}
 	}
 
-	lines := strings.Split(string(output), "\n")
+	lines := strings.Split(output, "\n")
 	var versions []string
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -60,8 +46,8 @@ func GetCurrentGoVersion() string {
 	}
 
 	// Fallback: check system Go
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		return strings.TrimSpace(string(output))
+	if output, err := runner.Output("go", "version"); err == nil {
+		return strings.TrimSpace(output)
 	}
 
 	return ""
@@ -69,17 +55,16 @@ func GetCurrentGoVersion() string {
 
 // getCurrentGoVersionWithGobrew gets current version using gobrew
 func getCurrentGoVersionWithGobrew() string {
-	if _, err := exec.LookPath("gobrew"); err != nil {
+	if !runner.Available("gobrew") {
 		return ""
 	}
 
-	cmd := exec.Command("gobrew", "ls")
-	output, err := cmd.Output()
+	output, err := runner.Output("gobrew", "ls")
 	if err != nil {
 		return ""
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.Contains(line, "*") || strings.Contains(line, "current") {
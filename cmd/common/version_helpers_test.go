@@ -0,0 +1,69 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+func TestGetGobrewVersions(t *testing.T) {
+	t.Run("lists installed versions", func(t *testing.T) {
+		fake := runner.NewFake().On("gobrew ls", runner.Response{Stdout: "1.21.5\n1.22.1*\n\n=> current 1.22.1\n"})
+		defer runner.Replace(fake)()
+
+		want := []string{"1.21.5", "1.22.1*"}
+		if got := GetGobrewVersions(); !reflect.DeepEqual(got, want) {
+			t.Errorf("GetGobrewVersions() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gobrew not installed", func(t *testing.T) {
+		fake := runner.NewFake()
+		defer runner.Replace(fake)()
+
+		if got := GetGobrewVersions(); len(got) != 0 {
+			t.Errorf("GetGobrewVersions() = %v, want none", got)
+		}
+		if len(fake.Calls()) != 0 {
+			t.Errorf("unexpected commands %v", fake.Lines())
+		}
+	})
+
+	t.Run("gobrew fails", func(t *testing.T) {
+		fake := runner.NewFake().On("gobrew ls", runner.Response{Stderr: "boom", ExitCode: 1})
+		defer runner.Replace(fake)()
+
+		if got := GetGobrewVersions(); len(got) != 0 {
+			t.Errorf("GetGobrewVersions() = %v, want none", got)
+		}
+	})
+}
+
+func TestGetCurrentGoVersion(t *testing.T) {
+	t.Run("from gobrew", func(t *testing.T) {
+		fake := runner.NewFake().On("gobrew ls", runner.Response{Stdout: "1.21.5\n1.22.1*\n"})
+		defer runner.Replace(fake)()
+
+		if got := GetCurrentGoVersion(); got != "1.22.1" {
+			t.Errorf("GetCurrentGoVersion() = %q, want 1.22.1", got)
+		}
+	})
+
+	t.Run("falls back to go version", func(t *testing.T) {
+		fake := runner.NewFake().On("go version", runner.Response{Stdout: "go version go1.22.1 linux/amd64\n"})
+		defer runner.Replace(fake)()
+
+		if got := GetCurrentGoVersion(); got != "go version go1.22.1 linux/amd64" {
+			t.Errorf("GetCurrentGoVersion() = %q", got)
+		}
+	})
+
+	t.Run("no go at all", func(t *testing.T) {
+		defer runner.Replace(runner.NewFake())()
+
+		if got := GetCurrentGoVersion(); got != "" {
+			t.Errorf("GetCurrentGoVersion() = %q, want empty", got)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+)
+
+// Path returns the location of the gos configuration file (~/.gos/config)
+func Path() string {
+	return filepath.Join(common.GetGosHome(), "config")
+}
+
+// EnvName returns the environment variable that overrides a configuration key
+func EnvName(key string) string {
+	return "GOS_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// Get returns the value of a configuration key. The GOS_<KEY> environment
+// variable takes precedence over the configuration file.
+func Get(key string) string {
+	if value := os.Getenv(EnvName(key)); value != "" {
+		return value
+	}
+	return Load()[key]
+}
+
+// Load reads all key=value pairs from the configuration file
+func Load() map[string]string {
+	values := map[string]string{}
+
+	data, err := fsys.ReadFile(Path())
+	if err != nil {
+		return values
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
+// Set stores a configuration key, removing it when value is empty
+func Set(key, value string) error {
+	values := Load()
+	if value == "" {
+		delete(values, key)
+	} else {
+		values[key] = value
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("# gos configuration\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, values[k])
+	}
+
+	if err := fsys.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		return err
+	}
+	return fsys.WriteFile(Path(), []byte(b.String()), 0644)
+}
+
+// Enabled reports whether a boolean configuration key is switched on
+// ("true", "yes", "on" or "1")
+func Enabled(key string) bool {
+	switch strings.ToLower(Get(key)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
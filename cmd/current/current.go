@@ -0,0 +1,133 @@
+package current
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewCurrentCmd creates the current command
+func NewCurrentCmd() *cobra.Command {
+	var explain bool
+
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Show which Go version applies to the current directory",
+		Long: `Show the Go version selected for the current directory.
+
+The first setting found wins, in this order:
+  1. GOS_VERSION environment variable
+  2. .go-version
+  3. .tool-versions (golang entry)
+  4. go.mod toolchain directive, then go directive (as a minimum version)
+  5. global default set with 'gos default' (~/.gos-default)
+
+Files are searched from the current directory up to the filesystem root;
+a setting in a nearer directory wins over one in a parent directory.`,
+		Example: `  gos current            # Show the version for this directory
+  gos current --explain  # Show every setting found and which one won`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			result, err := resolver.Resolve(dir)
+			if err != nil {
+				return fmt.Errorf("resolving Go version: %w", err)
+			}
+
+			showCurrent(result)
+			if explain {
+				showExplanation(result)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&explain, "explain", false, "Show every version setting found and why one won")
+
+	return cmd
+}
+
+// showCurrent prints the resolved version and whether it is installed
+func showCurrent(result resolver.Result) {
+	if !result.Found() {
+		ui.Out.Println("ℹ️  No Go version configured for this directory")
+		if b, err := backend.Detect(); err == nil {
+			if version, err := b.Current(); err == nil && version != "" {
+				ui.Out.Printf("  Active version (%s): %s\n", b.Name(), version)
+			}
+		}
+		return
+	}
+
+	ui.Success.Printf("🎯 Go %s", result.Version)
+	ui.Out.Printf(" (from %s)\n", describe(result.Source, result.Path))
+
+	b, err := backend.Detect()
+	if err != nil {
+		return
+	}
+	if installed, err := backend.ResolveInstalled(b, result.Version); err == nil {
+		if installed != result.Version {
+			ui.Out.Printf("  Resolves to installed Go %s\n", installed)
+		}
+	} else {
+		ui.Caution.Printf("  ⚠️  %v\n", err)
+		ui.Hint.Printf("  💡 Install it with: gos install %s\n", result.Version)
+	}
+}
+
+// showExplanation prints every setting found, in precedence order
+func showExplanation(result resolver.Result) {
+	ui.Out.Println()
+	ui.Title.Printf("🔍 Resolution for %s\n", result.Dir)
+	ui.Out.Printf("  Precedence: %s > %s > %s > %s > %s > %s\n",
+		resolver.SourceEnv, resolver.SourceGoVersion, resolver.SourceToolVersions,
+		resolver.SourceGoModToolchain, resolver.SourceGoModGo, resolver.SourceDefault)
+	ui.Out.Printf("  Searched %d directories up to the filesystem root\n", result.Searched)
+	ui.Out.Println()
+
+	if len(result.Steps) == 0 {
+		ui.Out.Printf("  ℹ️  %s is not set and no version files were found\n", resolver.EnvVar)
+		ui.Hint.Printf("  💡 Pin a version with: gos project <version> or gos default <version>\n")
+		return
+	}
+
+	for i, step := range result.Steps {
+		if i == 0 {
+			ui.Success.Printf("  ✅ %-18s %-10s %s\n", step.Source, step.Version, describePath(step.Path))
+			ui.Out.Printf("     %s\n", step.Note)
+			continue
+		}
+		ui.Caution.Printf("  ⏭️  %-18s %-10s %s\n", step.Source, step.Version, describePath(step.Path))
+		ui.Out.Printf("     %s\n", step.Note)
+	}
+
+	if result.Source != resolver.SourceEnv {
+		ui.Out.Println()
+		ui.Out.Printf("  %s is not set\n", resolver.EnvVar)
+	}
+}
+
+// describe names a source for the one-line summary
+func describe(source, path string) string {
+	if path == "" {
+		return source
+	}
+	return path
+}
+
+// describePath renders an empty path as the environment
+func describePath(path string) string {
+	if path == "" {
+		return "(environment)"
+	}
+	return path
+}
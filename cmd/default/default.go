@@ -1,30 +1,43 @@
 package defaultcmd
 
 import (
-	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/output"
 	"github.com/spf13/cobra"
 )
 
 // CreateDefaultCommand creates the default command
 func CreateDefaultCommand() *cobra.Command {
-	return &cobra.Command{
+	return output.Enable(&cobra.Command{
 		Use:   "default [version]",
 		Short: "Set a default Go version",
 		Long: `Set a specific Go version as the default. This version will be used when no project-specific version is configured.
 If no version is specified, shows the current default version.`,
 		Example: `  gos default 1.21.5       # Set Go 1.21.5 as default
-  gos default               # Show current default version`,
+  gos default 1.22          # Set the newest installed 1.22.x as default
+  gos default               # Show current default version
+  gos default -o json       # Show it as JSON`,
 		Args: cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			if !common.CheckVersionManagerAvailable() {
-				return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := backend.Active()
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				if err := SetDefaultVersion(b, args[0]); err != nil {
+					return err
+				}
+			}
+
+			result := GetDefaultVersion(b)
+			if output.Structured() {
+				return output.Print(result)
 			}
-			
 			if len(args) == 0 {
-				ShowDefaultVersion()
-			} else {
-				SetDefaultVersion(args[0])
+				ShowDefaultVersion(result)
 			}
+			return nil
 		},
-	}
+	})
 }
@@ -2,50 +2,43 @@ package defaultcmd
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
 // SetDefaultVersion sets a specific Go version as the default
-func SetDefaultVersion(version string) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
-
-	blue.Printf("📌 Setting Go %s as default version...\n", version)
-
-	// Use gobrew to set the default version
-	if _, err := exec.LookPath("gobrew"); err != nil {
-		red.Println("❌ gobrew is not installed")
-		red.Println("💡 Run first: gos setup")
-		return
+func SetDefaultVersion(b backend.Backend, version string) error {
+	resolved, err := backend.ResolveInstalled(b, version)
+	if err != nil {
+		return err
 	}
+	version = resolved
 
-	cmd := exec.Command("gobrew", "use", version)
+	ui.Info.Printf("📌 Setting Go %s as default version...\n", version)
 
-	homeDir := common.GetHomeDir()
-	if err := cmd.Run(); err != nil {
-		red.Printf("❌ Error setting default version: %v\n", err)
-		return
+	if err := b.Use(version); err != nil {
+		return fmt.Errorf("setting default version: %w", err)
 	}
+	shim.Refresh(b)
 
 	// Save the default version to a file for persistence
-	defaultFile := filepath.Join(homeDir, ".gos-default")
-	if err := os.WriteFile(defaultFile, []byte(version), 0644); err != nil {
-		red.Printf("⚠️  Warning: Could not save default version: %v\n", err)
+	defaultFile := resolver.DefaultFile()
+	if err := fsys.WriteFile(defaultFile, []byte(version), 0644); err != nil {
+		ui.Warn.Printf("⚠️  Warning: Could not save default version: %v\n", err)
 	}
 
-	green.Printf("✅ Go %s is now the default version\n", version)
+	ui.Success.Printf("✅ Go %s is now the default version\n", version)
 
 	// Verify the change
-	fmt.Println()
-	blue.Println("🔍 Verifying...")
-	if goCmd := exec.Command("go", "version"); goCmd.Run() == nil {
-		output, _ := goCmd.Output()
-		fmt.Printf("  %s", output)
+	ui.Note.Println()
+	ui.Info.Println("🔍 Verifying...")
+	if output, err := runner.Output("go", "version"); err == nil {
+		ui.Note.Printf("  %s", output)
 	}
+	return nil
 }
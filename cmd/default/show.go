@@ -1,80 +1,58 @@
 package defaultcmd
 
 import (
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// ShowDefaultVersion displays the current default Go version
-func ShowDefaultVersion() {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
+// Sources of the default version
+const (
+	SourceSaved   = "saved"   // set with gos default and kept in ~/.gos-default
+	SourceBackend = "backend" // the backend's active version
+	SourceNone    = "none"    // no default is set
+)
+
+// Default is the result of gos default
+type Default struct {
+	Version string `json:"version,omitempty"`
+	Source  string `json:"source"`
+	Backend string `json:"backend"`
+	File    string `json:"file,omitempty"` // where a saved default is kept
+}
 
-	blue.Println("📌 Default Go version:")
-	
-	homeDir := common.GetHomeDir()
-	
-	// First check if we have a saved default version
-	defaultFile := filepath.Join(homeDir, ".gos-default")
-	if content, err := os.ReadFile(defaultFile); err == nil {
-		version := strings.TrimSpace(string(content))
-		if version != "" {
-			green.Printf("  ✅ %s (saved default)\n", version)
-			return
+// GetDefaultVersion returns the default Go version: the saved one, or else
+// the backend's active version
+func GetDefaultVersion(b backend.Backend) Default {
+	result := Default{Source: SourceNone, Backend: b.Name()}
+
+	defaultFile := resolver.DefaultFile()
+	if content, err := fsys.ReadFile(defaultFile); err == nil {
+		if version := strings.TrimSpace(string(content)); version != "" {
+			result.Version, result.Source, result.File = version, SourceSaved, defaultFile
+			return result
 		}
 	}
-	
-	if common.IsCommandAvailable("gobrew") {
-		// gobrew: check current version
-		cmd := exec.Command("gobrew", "use")
-		if output, err := cmd.Output(); err == nil {
-			version := strings.TrimSpace(string(output))
-			if version != "" {
-				green.Printf("  ✅ %s (via gobrew)\n", version)
-				return
-			}
-		}
-	} else {
-		// Try different possible locations for g
-		gPaths := []string{
-			filepath.Join(homeDir, ".g", "bin", "g"),
-			filepath.Join(homeDir, "go", "bin", "g"),
-			"/usr/local/bin/g",
-		}
-		
-		for _, gPath := range gPaths {
-			if _, err := os.Stat(gPath); err == nil {
-				// Check current active version in g using 'list' command
-				cmd := exec.Command(gPath, "list")
-				if output, err := cmd.Output(); err == nil {
-					lines := strings.Split(string(output), "\n")
-					for _, line := range lines {
-						line = strings.TrimSpace(line)
-						if strings.HasPrefix(line, ">") {
-							version := strings.TrimSpace(strings.TrimPrefix(line, ">"))
-							green.Printf("  ✅ %s (via g)\n", version)
-							return
-						}
-					}
-				}
-				break
-			}
-		}
-		
-		// Alternative: check symlink in .g directory
-		goLink := filepath.Join(homeDir, ".g", "go")
-		if target, err := os.Readlink(goLink); err == nil {
-			version := filepath.Base(target)
-			green.Printf("  ✅ %s (via g symlink)\n", version)
-			return
-		}
+
+	if version, err := b.Current(); err == nil && version != "" {
+		result.Version, result.Source = version, SourceBackend
+	}
+	return result
+}
+
+// ShowDefaultVersion displays the current default Go version
+func ShowDefaultVersion(result Default) {
+	ui.Title.Println("📌 Default Go version:")
+
+	switch result.Source {
+	case SourceSaved:
+		ui.Success.Printf("  ✅ %s (saved default)\n", result.Version)
+	case SourceBackend:
+		ui.Success.Printf("  ✅ %s (via %s)\n", result.Version, result.Backend)
+	default:
+		ui.Caution.Println("  ⚠️  No default version set")
 	}
-	
-	yellow.Println("  ⚠️  No default version set")
 }
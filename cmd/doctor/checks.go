@@ -0,0 +1,377 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+func init() {
+	Register(Check{
+		ID:          "goroot-override",
+		Section:     SectionEnvironment,
+		Severity:    SeverityWarning,
+		Explanation: "go takes its standard library and tools from GOROOT, so a GOROOT left over from another installation mixes two Go versions. go finds its own root when GOROOT is unset.",
+		Detect:      detectGorootOverride,
+	})
+	Register(Check{
+		ID:          "path-entries",
+		Section:     SectionPath,
+		Severity:    SeverityWarning,
+		Explanation: "The active Go and the tools installed with go install are only found when their directories are in PATH.",
+		Detect:      detectPathEntries,
+	})
+	Register(Check{
+		ID:          "path-order",
+		Section:     SectionPath,
+		Severity:    SeverityError,
+		Explanation: "Commands run the first go in PATH, so while another one comes first, versions switched with gos have no effect.",
+		Detect:      detectPathOrder,
+	})
+	Register(Check{
+		ID:          "shadowed-binaries",
+		Section:     SectionPath,
+		Severity:    SeverityWarning,
+		Explanation: "A program found twice in PATH runs from the first directory only; the other copy is stale or unused.",
+		Detect:      detectShadowedBinaries,
+	})
+	Register(Check{
+		ID:          "gopath-bin",
+		Section:     SectionDirectories,
+		Severity:    SeverityWarning,
+		Explanation: "go install puts tools in GOPATH/bin; shells skip PATH entries that do not exist until the next login.",
+		Detect:      detectGopathBin,
+		Fix:         fixGopathBin,
+	})
+	Register(Check{
+		ID:          "stale-rc-snippets",
+		Section:     SectionShell,
+		Severity:    SeverityWarning,
+		Explanation: "Snippets written by older gos versions export a fixed GOROOT and PATH that fight with the gos block.",
+		Detect:      detectStaleSnippets,
+		Fix:         fixStaleSnippets,
+	})
+	Register(Check{
+		ID:          "backend-installed",
+		Section:     SectionBackend,
+		Severity:    SeverityError,
+		Explanation: "Every gos command goes through the selected backend.",
+		Detect:      detectBackendInstalled,
+	})
+	Register(Check{
+		ID:          "backend-empty",
+		Section:     SectionBackend,
+		Severity:    SeverityWarning,
+		Explanation: "The backend has no Go version to switch to.",
+		Detect:      detectBackendEmpty,
+	})
+	Register(Check{
+		ID:          "go-binary",
+		Section:     SectionGo,
+		Severity:    SeverityError,
+		Explanation: "No working go was found in PATH.",
+		Detect:      detectGoBinary,
+	})
+	Register(Check{
+		ID:          "cgo-compiler",
+		Section:     SectionGo,
+		Severity:    SeverityWarning,
+		Explanation: "With CGO enabled, builds of packages using cgo (net, os/user and many drivers) fail without a C compiler.",
+		Detect:      detectCgoCompiler,
+	})
+}
+
+// detectGorootOverride flags a GOROOT that is not the active Go's
+func detectGorootOverride(env *Env) Finding {
+	goroot := os.Getenv("GOROOT")
+	expected := env.Backend.GOROOT("")
+	switch {
+	case goroot == "":
+		return ok("GOROOT is not set")
+	case samePath(goroot, expected):
+		return ok("GOROOT is %s", goroot)
+	}
+	return problem("Remove the GOROOT export from your shell files",
+		"GOROOT is set to %s, but the active Go is %s", goroot, expected)
+}
+
+// detectPathEntries flags managed directories missing from PATH
+func detectPathEntries(env *Env) Finding {
+	var missing []string
+	for _, dir := range env.ManagedDirs() {
+		if !env.InPath(dir) {
+			missing = append(missing, dir)
+		}
+	}
+	if len(missing) > 0 {
+		target := rcfile.CurrentTarget()
+		return problem(fmt.Sprintf("Run 'gos setup', or add %s to %s", target.Shell.InitLine(), target.Path),
+			"Missing from PATH: %s", strings.Join(missing, ", "))
+	}
+	return ok("PATH has %s", strings.Join(env.ManagedDirs(), ", "))
+}
+
+// detectPathOrder flags a go outside the backend that comes first in PATH
+func detectPathOrder(env *Env) Finding {
+	found := lookAll(env.Path, "go")
+	if len(found) == 0 {
+		return ok("No go in PATH")
+	}
+
+	if versions, err := env.Backend.ListInstalled(); err != nil || len(versions) == 0 {
+		return ok("go resolves to %s; the %s backend has no version to put first", found[0], env.Backend.Name())
+	}
+
+	first := filepath.Dir(found[0])
+	for _, dir := range append(env.Backend.BinDirs(), common.GetShimsDir()) {
+		if samePath(first, dir) {
+			return ok("go resolves to %s", found[0])
+		}
+	}
+	return problem("Run 'gos clean path' to take the other installation out of PATH",
+		"go resolves to %s, ahead of the %s backend's go", found[0], env.Backend.Name())
+}
+
+// detectShadowedBinaries flags go, gofmt and GOPATH/bin tools found more than once in PATH
+func detectShadowedBinaries(env *Env) Finding {
+	names := []string{"go", "gofmt"}
+	if entries, err := fsys.ReadDir(filepath.Join(env.GOPATH, "bin")); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, strings.TrimSuffix(entry.Name(), ".exe"))
+			}
+		}
+	}
+
+	var shadowed []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		// The shims and the versions they run hold the same programs on purpose
+		var found []string
+		for _, path := range lookAll(env.Path, name) {
+			if !samePath(filepath.Dir(path), common.GetShimsDir()) {
+				found = append(found, path)
+			}
+		}
+		if len(found) > 1 {
+			shadowed = append(shadowed, fmt.Sprintf("%s hides %s", found[0], strings.Join(found[1:], ", ")))
+		}
+	}
+	if len(shadowed) > 0 {
+		return problem("Remove the copies you do not use, or run 'gos clean path'",
+			"Found more than once in PATH: %s", strings.Join(shadowed, "; "))
+	}
+	return ok("No program is shadowed in PATH")
+}
+
+// detectGopathBin flags a missing GOPATH/bin
+func detectGopathBin(env *Env) Finding {
+	dir := filepath.Join(env.GOPATH, "bin")
+	if fsys.Exists(dir) {
+		return ok("%s exists", dir)
+	}
+	return problem("", "%s does not exist", dir)
+}
+
+// fixGopathBin creates GOPATH/bin
+func fixGopathBin(env *Env) error {
+	return fsys.MkdirAll(filepath.Join(env.GOPATH, "bin"), 0755)
+}
+
+// StaleTargets returns the shell files with legacy snippets or an outdated gos block
+func StaleTargets() []rcfile.Target {
+	var stale []rcfile.Target
+	for _, target := range rcfile.Targets() {
+		content, err := rcfile.Read(target.Path)
+		if err != nil || content == "" {
+			continue
+		}
+		body, hasBlock := rcfile.Extract(content)
+		if rcfile.RemoveLegacy(content) != content || (hasBlock && body != target.Shell.InitLine()) {
+			stale = append(stale, target)
+		}
+	}
+	return stale
+}
+
+// detectStaleSnippets flags shell files with snippets from older gos versions
+func detectStaleSnippets(env *Env) Finding {
+	stale := StaleTargets()
+	if len(stale) == 0 {
+		return ok("No outdated gos snippets in shell files")
+	}
+	var paths []string
+	for _, target := range stale {
+		paths = append(paths, target.Path)
+	}
+	return problem("", "Outdated gos snippets in %s", strings.Join(paths, ", "))
+}
+
+// fixStaleSnippets replaces the outdated snippets with the current gos
+// block once the diff is confirmed, like gos env --fix
+func fixStaleSnippets(env *Env) error {
+	var edits []rcfile.Edit
+	for _, target := range StaleTargets() {
+		edit, err := rcfile.PlanInit(target)
+		if err != nil {
+			return err
+		}
+		if edit.Changed() {
+			edit.PrintDiff()
+			edits = append(edits, edit)
+		}
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	apply, err := ui.Confirm("Rewrite these shell files?", false)
+	if err != nil {
+		return err
+	}
+	if !apply {
+		return errors.New("not confirmed; nothing was changed")
+	}
+	for _, edit := range edits {
+		if err := edit.Write(); err != nil {
+			return fmt.Errorf("writing %s: %w", edit.Path, err)
+		}
+		ui.Success.Printf("  ✅ Updated %s\n", edit.Path)
+	}
+	return nil
+}
+
+// detectBackendInstalled flags a selected backend whose tool is missing
+func detectBackendInstalled(env *Env) Finding {
+	if env.Backend.Available() {
+		return ok("The %s backend is installed", env.Backend.Name())
+	}
+	return problem("Run 'gos setup --"+env.Backend.Name()+"', or pick another backend with GOS_BACKEND",
+		"The %s backend is selected but not installed", env.Backend.Name())
+}
+
+// detectBackendEmpty flags an installed backend that holds no Go version
+func detectBackendEmpty(env *Env) Finding {
+	if !env.Backend.Available() {
+		return ok("Skipped: the %s backend is not installed", env.Backend.Name())
+	}
+	versions, err := env.Backend.ListInstalled()
+	if err != nil {
+		return problem("", "Could not list the %s backend's versions: %v", env.Backend.Name(), err)
+	}
+	if len(versions) == 0 {
+		return problem("Install a Go version with: gos install latest",
+			"The %s backend holds no Go versions", env.Backend.Name())
+	}
+	return ok("%d Go version(s) installed with %s", len(versions), env.Backend.Name())
+}
+
+// detectGoBinary flags a missing or broken go
+func detectGoBinary(env *Env) Finding {
+	goPath, err := runner.LookPath("go")
+	if err != nil {
+		return problem("Install a Go version with: gos install latest", "go is not in PATH")
+	}
+	output, err := runner.Output("go", "version")
+	if err != nil {
+		return problem("Reinstall the active version with gos remove and gos install", "%s does not run: %v", goPath, err)
+	}
+	return ok("%s (%s)", strings.TrimSpace(output), goPath)
+}
+
+// detectCgoCompiler flags CGO_ENABLED=1 without the C compiler go would call
+func detectCgoCompiler(env *Env) Finding {
+	if !runner.Available("go") {
+		return ok("Skipped: go is not in PATH")
+	}
+	output, err := runner.Output("go", "env", "CGO_ENABLED", "CC")
+	if err != nil {
+		return ok("Skipped: go env failed")
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "1" {
+		return ok("CGO is disabled")
+	}
+
+	fields := strings.Fields(lines[1])
+	if len(fields) == 0 || runner.Available(fields[0]) {
+		return ok("CGO is enabled and %s is installed", strings.TrimSpace(lines[1]))
+	}
+	return problem(compilerHint(), "CGO is enabled but the C compiler %s is not installed", fields[0])
+}
+
+// compilerHint tells how to install a C compiler on this platform
+func compilerHint() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "Install the Command Line Tools with: xcode-select --install (or set CGO_ENABLED=0)"
+	case "windows":
+		return "Install a MinGW-w64 gcc and add it to PATH (or set CGO_ENABLED=0)"
+	}
+	return "Install gcc, e.g. sudo apt install build-essential (or set CGO_ENABLED=0)"
+}
+
+// lookAll returns every executable called name in the PATH entries, in
+// order. A file reached through several entries (/bin linking to /usr/bin)
+// is listed once.
+func lookAll(path []string, name string) []string {
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	var found []string
+	seen := map[string]bool{}
+	for _, dir := range path {
+		if dir == "" {
+			continue
+		}
+		file := filepath.Join(dir, name)
+		info, err := fsys.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		real := file
+		if resolved, err := filepath.EvalSymlinks(file); err == nil {
+			real = resolved
+		}
+		if !seen[real] {
+			seen[real] = true
+			found = append(found, file)
+		}
+	}
+	return found
+}
+
+// indexOf returns the position of dir in the PATH entries, or -1
+func indexOf(path []string, dir string) int {
+	for i, entry := range path {
+		if samePath(entry, dir) {
+			return i
+		}
+	}
+	return -1
+}
+
+// samePath reports whether two paths name the same directory, following symbolic links
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
+}
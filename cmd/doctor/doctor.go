@@ -0,0 +1,159 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCmd creates the doctor command
+func NewDoctorCmd() *cobra.Command {
+	cmd := output.Enable(&cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the Go setup and fix what is safe to fix",
+		Long: `Run every check on the Go setup: PATH order and shadowed binaries, GOROOT
+overrides, GOPATH/bin, outdated shell snippets, the backend and the C compiler
+CGO needs. Failed checks explain the problem and how to solve it; --fix
+applies the fixes that are safe to apply. Exits 8 when an error is left.
+
+Checks: ` + strings.Join(IDs(), ", "),
+		Example: `  gos doctor                    # Run every check
+  gos doctor --fix              # Also apply the safe fixes
+  gos doctor --check path-order # Run one check
+  gos doctor -o json            # The report as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, _ := cmd.Flags().GetStringSlice("check")
+			fix, _ := cmd.Flags().GetBool("fix")
+			return RunDoctor(ids, fix)
+		},
+	})
+
+	cmd.Flags().StringSlice("check", nil, "Run only the check with this ID (repeatable)")
+	cmd.Flags().Bool("fix", false, "Apply the safe fixes for failed checks")
+	return cmd
+}
+
+// RunDoctor runs the checks with the given IDs, or all of them, and prints
+// the report; it fails when an error is left
+func RunDoctor(ids []string, fix bool) error {
+	checks, err := selectChecks(ids)
+	if err != nil {
+		return err
+	}
+
+	b, err := backend.Detect()
+	if err != nil {
+		return &errs.UsageError{Err: err}
+	}
+
+	report := Run(NewEnv(b), checks, fix)
+	if output.Structured() {
+		if err := output.Print(report); err != nil {
+			return err
+		}
+	} else {
+		ShowReport(report, fix)
+	}
+
+	if report.Errors > 0 {
+		return &errs.CheckFailedError{Problems: report.Errors}
+	}
+	return nil
+}
+
+// selectChecks returns the checks with the given IDs, or every check when none are given
+func selectChecks(ids []string) ([]Check, error) {
+	if len(ids) == 0 {
+		return Checks(), nil
+	}
+
+	var checks []Check
+	for _, id := range ids {
+		check, found := Lookup(id)
+		if !found {
+			return nil, &errs.UsageError{Err: fmt.Errorf("unknown check %q (checks: %s)", id, strings.Join(IDs(), ", "))}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// SectionTitles are the headings of the text reports
+var SectionTitles = map[string]string{
+	SectionEnvironment: "📋 Environment Variables:",
+	SectionPath:        "🛤️  PATH:",
+	SectionDirectories: "📁 Directories:",
+	SectionShell:       "🐚 Shell Configuration:",
+	SectionBackend:     "🔧 Version Manager:",
+	SectionGo:          "🐹 Go Binary:",
+}
+
+// ShowReport prints the results grouped by section, with the explanation
+// and hint of each failed check, and a summary
+func ShowReport(report Report, fixed bool) {
+	ui.Title.Println("🩺 gos doctor")
+
+	section := ""
+	fixable := 0
+	for _, result := range report.Results {
+		if result.Section != section {
+			section = result.Section
+			ui.Out.Println()
+			ui.Title.Println(SectionTitles[section])
+		}
+		ShowResult(result)
+		if result.Fixable && result.FixError == "" {
+			fixable++
+		}
+	}
+
+	ui.Out.Println()
+	ui.Title.Println("📊 Summary:")
+	if report.Fixed > 0 {
+		ui.Success.Printf("  🔧 Fixed %d problem(s)\n", report.Fixed)
+	}
+	switch {
+	case report.Errors > 0:
+		ui.Problem.Printf("  ❌ %d error(s), %d warning(s)\n", report.Errors, report.Warnings)
+	case report.Warnings > 0:
+		ui.Caution.Printf("  ⚠️  %d warning(s)\n", report.Warnings)
+	default:
+		ui.Success.Println("  ✅ Everything looks good!")
+	}
+	if fixable > 0 && !fixed {
+		ui.Hint.Printf("  💡 %d can be fixed with: gos doctor --fix\n", fixable)
+	}
+}
+
+// ShowResult prints one check with the icon and color of its status, and
+// the explanation and hint when it failed
+func ShowResult(result Result) {
+	switch {
+	case result.Fixed:
+		ui.Success.Printf("  🔧 %s (fixed) [%s]\n", result.Message, result.ID)
+		return
+	case result.Status == StatusOK:
+		ui.Success.Printf("  ✅ %s [%s]\n", result.Message, result.ID)
+		return
+	case result.Status == SeverityError:
+		ui.Problem.Printf("  ❌ %s [%s]\n", result.Message, result.ID)
+	case result.Status == SeverityWarning:
+		ui.Caution.Printf("  ⚠️  %s [%s]\n", result.Message, result.ID)
+	default:
+		ui.Out.Printf("  ℹ️  %s [%s]\n", result.Message, result.ID)
+	}
+
+	ui.Note.Printf("      %s\n", result.Explanation)
+	if result.FixError != "" {
+		ui.Problem.Printf("      ❌ The fix failed: %s\n", result.FixError)
+	}
+	if result.Hint != "" {
+		ui.Hint.Printf("      💡 %s\n", result.Hint)
+	}
+}
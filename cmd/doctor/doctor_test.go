@@ -0,0 +1,255 @@
+package doctor
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+// newEnv returns an environment whose home, files and commands are all fake
+func newEnv(t *testing.T, installed ...string) (*Env, *fsys.Mem, *runner.Fake) {
+	t.Helper()
+	home, mem, fake := testenv.Sandbox(t)
+	return &Env{Backend: testenv.NewBackend(installed...), Home: home, GOPATH: filepath.Join(home, "go")}, mem, fake
+}
+
+// install puts an empty executable at dir/name
+func install(t *testing.T, mem *fsys.Mem, dir, name string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if err := mem.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteFile(filepath.Join(dir, name), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	for _, check := range Checks() {
+		t.Run(check.ID, func(t *testing.T) {
+			if _, ok := SectionTitles[check.Section]; !ok {
+				t.Errorf("unknown section %q", check.Section)
+			}
+			switch check.Severity {
+			case SeverityError, SeverityWarning, SeverityInfo:
+			default:
+				t.Errorf("unknown severity %q", check.Severity)
+			}
+			if check.Explanation == "" || check.Detect == nil {
+				t.Error("a check needs an explanation and a Detect function")
+			}
+		})
+	}
+
+	t.Run("unknown check", func(t *testing.T) {
+		if _, err := selectChecks([]string{"path-order", "nope"}); errs.Code(err) != errs.ExitUsage {
+			t.Errorf("selectChecks() error = %v, want a usage error", err)
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	broken := true
+	checks := []Check{
+		{ID: "fine", Section: SectionGo, Severity: SeverityError, Detect: func(*Env) Finding { return ok("fine") }},
+		{
+			ID: "fixable", Section: SectionGo, Severity: SeverityError, Explanation: "why",
+			Detect: func(*Env) Finding {
+				if broken {
+					return problem("", "broken")
+				}
+				return ok("repaired")
+			},
+			Fix: func(*Env) error { broken = false; return nil },
+		},
+		{
+			ID: "stubborn", Section: SectionGo, Severity: SeverityWarning,
+			Detect: func(*Env) Finding { return problem("do it by hand", "still broken") },
+			Fix:    func(*Env) error { return errors.New("permission denied") },
+		},
+	}
+
+	report := Run(&Env{}, checks, false)
+	if report.OK || report.Errors != 1 || report.Warnings != 1 || !report.Results[1].Fixable {
+		t.Fatalf("Run() = %+v", report)
+	}
+	if report.Results[1].Explanation != "why" || report.Results[0].Explanation != "" {
+		t.Errorf("explanations: %+v", report.Results)
+	}
+
+	report = Run(&Env{}, checks, true)
+	if !report.OK || report.Fixed != 1 || report.Warnings != 1 {
+		t.Fatalf("Run() with fix = %+v", report)
+	}
+	if got := report.Results[1]; !got.Fixed || got.Message != "repaired" {
+		t.Errorf("fixed result = %+v", got)
+	}
+	if got := report.Results[2]; got.FixError != "permission denied" || got.Status != SeverityWarning {
+		t.Errorf("failed fix = %+v", got)
+	}
+}
+
+func TestChecks(t *testing.T) {
+	detect := func(t *testing.T, id string, env *Env) Finding {
+		t.Helper()
+		return mustLookup(t, id).Detect(env)
+	}
+
+	t.Run("path-entries names the shell", func(t *testing.T) {
+		env, _, _ := newEnv(t, "1.22.1")
+		t.Setenv("SHELL", "/bin/zsh")
+
+		finding := detect(t, "path-entries", env)
+		if !finding.Problem || !strings.Contains(finding.Hint, `eval "$(gos init zsh)"`) {
+			t.Errorf("detect = %+v", finding)
+		}
+	})
+
+	t.Run("path-entries matches whole entries", func(t *testing.T) {
+		env, _, _ := newEnv(t, "1.22.1")
+		for _, dir := range env.ManagedDirs() {
+			env.Path = append(env.Path, dir+"2")
+		}
+		if finding := detect(t, "path-entries", env); !finding.Problem {
+			t.Errorf("detect = %+v", finding)
+		}
+
+		env.Path = env.ManagedDirs()
+		if finding := detect(t, "path-entries", env); finding.Problem {
+			t.Errorf("detect = %+v", finding)
+		}
+	})
+
+	t.Run("path-order", func(t *testing.T) {
+		env, mem, _ := newEnv(t, "1.22.1")
+		managed := env.Backend.BinDirs()[0]
+		install(t, mem, "/usr/local/go/bin", "go")
+		install(t, mem, managed, "go")
+
+		env.Path = []string{managed, "/usr/local/go/bin"}
+		if finding := detect(t, "path-order", env); finding.Problem {
+			t.Errorf("managed go first: %+v", finding)
+		}
+
+		env.Path = []string{"/usr/local/go/bin", managed}
+		finding := detect(t, "path-order", env)
+		if !finding.Problem || !strings.Contains(finding.Message, filepath.Join("/usr/local/go/bin", "go")) {
+			t.Errorf("other go first: %+v", finding)
+		}
+	})
+
+	t.Run("shadowed-binaries", func(t *testing.T) {
+		env, mem, _ := newEnv(t, "1.22.1")
+		install(t, mem, "/usr/bin", "gopls")
+		install(t, mem, filepath.Join(env.GOPATH, "bin"), "gopls")
+		env.Path = []string{"/usr/bin", filepath.Join(env.GOPATH, "bin")}
+
+		finding := detect(t, "shadowed-binaries", env)
+		if !finding.Problem || !strings.Contains(finding.Message, filepath.Join(env.GOPATH, "bin", "gopls")) {
+			t.Errorf("detect = %+v", finding)
+		}
+	})
+
+	t.Run("goroot-override", func(t *testing.T) {
+		env, _, _ := newEnv(t)
+		t.Setenv("GOROOT", "/usr/local/go")
+		if finding := detect(t, "goroot-override", env); !finding.Problem {
+			t.Errorf("detect = %+v", finding)
+		}
+		t.Setenv("GOROOT", env.Backend.GOROOT(""))
+		if finding := detect(t, "goroot-override", env); finding.Problem {
+			t.Errorf("detect = %+v", finding)
+		}
+	})
+
+	t.Run("gopath-bin is fixed", func(t *testing.T) {
+		env, mem, _ := newEnv(t)
+		mem.MkdirAll(env.GOPATH, 0755)
+
+		report := Run(env, []Check{mustLookup(t, "gopath-bin")}, true)
+		if report.Fixed != 1 {
+			t.Errorf("Run() = %+v", report)
+		}
+		if _, err := mem.Stat(filepath.Join(env.GOPATH, "bin")); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("stale-rc-snippets is fixed once confirmed", func(t *testing.T) {
+		env, mem, _ := newEnv(t)
+		target := rcfile.Targets()[0]
+		legacy := "export EDITOR=vim\n\n# gos (Go version manager)\nexport GOROOT=$HOME/.gobrew/current/go\n"
+		mem.MkdirAll(filepath.Dir(target.Path), 0755)
+		mem.WriteFile(target.Path, []byte(legacy), 0644)
+
+		configure(t, ui.Options{NoInput: true, Quiet: true})
+		report := Run(env, []Check{mustLookup(t, "stale-rc-snippets")}, true)
+		if report.Fixed != 0 || report.Results[0].FixError == "" {
+			t.Fatalf("Run() without confirmation = %+v", report)
+		}
+		if data, _ := mem.ReadFile(target.Path); string(data) != legacy {
+			t.Fatalf("%s was changed without confirmation:\n%s", target.Path, data)
+		}
+
+		configure(t, ui.Options{AssumeYes: true, Quiet: true})
+		report = Run(env, []Check{mustLookup(t, "stale-rc-snippets")}, true)
+		if report.Fixed != 1 {
+			t.Fatalf("Run() = %+v", report)
+		}
+		data, _ := mem.ReadFile(target.Path)
+		if strings.Contains(string(data), "GOROOT") || !strings.Contains(string(data), target.Shell.InitLine()) {
+			t.Errorf("%s =\n%s", target.Path, data)
+		}
+	})
+
+	t.Run("cgo-compiler", func(t *testing.T) {
+		env, _, fake := newEnv(t)
+		fake.On("go env CGO_ENABLED CC", runner.Response{Stdout: "1\nclang\n"})
+
+		finding := detect(t, "cgo-compiler", env)
+		if !finding.Problem || !strings.Contains(finding.Message, "clang") {
+			t.Errorf("detect = %+v", finding)
+		}
+
+		fake.Path("clang", "/usr/bin/clang")
+		if finding := detect(t, "cgo-compiler", env); finding.Problem {
+			t.Errorf("detect with clang = %+v", finding)
+		}
+	})
+
+	t.Run("backend-empty", func(t *testing.T) {
+		env, _, _ := newEnv(t)
+		if finding := detect(t, "backend-empty", env); !finding.Problem {
+			t.Errorf("detect = %+v", finding)
+		}
+	})
+}
+
+func mustLookup(t *testing.T, id string) Check {
+	t.Helper()
+	check, found := Lookup(id)
+	if !found {
+		t.Fatalf("no check %s", id)
+	}
+	return check
+}
+
+// configure applies the ui options until the test ends
+func configure(t *testing.T, opts ui.Options) {
+	t.Helper()
+	previous := ui.Current()
+	ui.Configure(opts)
+	t.Cleanup(func() { ui.Configure(previous) })
+}
@@ -0,0 +1,215 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+)
+
+// Sections group checks in reports, in the order they are shown
+const (
+	SectionEnvironment = "environment"
+	SectionPath        = "path"
+	SectionDirectories = "directories"
+	SectionShell       = "shell"
+	SectionBackend     = "backend"
+	SectionGo          = "go"
+)
+
+// Severities of a failed check; they are also the status it reports
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// StatusOK is the status of a check that found nothing wrong
+const StatusOK = "ok"
+
+// Check is one diagnosis gos doctor runs
+type Check struct {
+	ID          string // stable name used by --check, e.g. "path-order"
+	Section     string // one of the Section constants
+	Severity    string // how bad a failure is; one of the Severity constants
+	Explanation string // why a failure matters, shown with it
+
+	// Detect inspects the environment
+	Detect func(env *Env) Finding
+	// Fix repairs what Detect found, or is nil when there is no safe fix
+	Fix func(env *Env) error
+}
+
+// Finding is what a check detected
+type Finding struct {
+	Problem bool
+	Message string
+	Hint    string // what the user can do when there is no fix, or besides it
+}
+
+// ok returns a finding with nothing wrong
+func ok(format string, args ...any) Finding {
+	return Finding{Message: fmt.Sprintf(format, args...)}
+}
+
+// problem returns a failed finding
+func problem(hint, format string, args ...any) Finding {
+	return Finding{Problem: true, Message: fmt.Sprintf(format, args...), Hint: hint}
+}
+
+// Env is what the checks inspect: the active backend and the locations gos
+// expects. Files and commands go through fsys and runner, so tests can fake them.
+type Env struct {
+	Backend backend.Backend
+	Home    string
+	GOPATH  string   // first GOPATH entry, ~/go by default
+	Path    []string // PATH entries in order
+}
+
+// NewEnv describes the current environment for the backend
+func NewEnv(b backend.Backend) *Env {
+	// In a GOS_HOME sandbox go runs with GOPATH inside it, whatever the shell exports
+	gopath := filepath.Join(common.GetHomeDir(), "go")
+	if entries := filepath.SplitList(os.Getenv("GOPATH")); !common.Sandboxed() && len(entries) > 0 && entries[0] != "" {
+		gopath = entries[0]
+	}
+	return &Env{
+		Backend: b,
+		Home:    common.GetHomeDir(),
+		GOPATH:  gopath,
+		Path:    filepath.SplitList(os.Getenv("PATH")),
+	}
+}
+
+// ManagedDirs returns the directories gos needs in PATH: the backend's bin
+// directories and GOPATH/bin
+func (env *Env) ManagedDirs() []string {
+	return append(append([]string{}, env.Backend.BinDirs()...), filepath.Join(env.GOPATH, "bin"))
+}
+
+// InPath reports whether dir is one of the PATH entries
+func (env *Env) InPath(dir string) bool {
+	return indexOf(env.Path, dir) >= 0
+}
+
+// registry holds the checks in the order they run
+var registry []Check
+
+// Register adds a check; IDs must be unique
+func Register(check Check) {
+	if _, found := Lookup(check.ID); found {
+		panic("doctor: check registered twice: " + check.ID)
+	}
+	registry = append(registry, check)
+}
+
+// Checks returns every registered check, grouped by section
+func Checks() []Check {
+	order := map[string]int{}
+	for i, section := range []string{SectionEnvironment, SectionPath, SectionDirectories, SectionShell, SectionBackend, SectionGo} {
+		order[section] = i
+	}
+
+	checks := append([]Check(nil), registry...)
+	sort.SliceStable(checks, func(i, j int) bool { return order[checks[i].Section] < order[checks[j].Section] })
+	return checks
+}
+
+// ChecksIn returns the registered checks of the given sections, in run order
+func ChecksIn(sections ...string) []Check {
+	var checks []Check
+	for _, check := range Checks() {
+		if slices.Contains(sections, check.Section) {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// Lookup returns the check with the given ID
+func Lookup(id string) (Check, bool) {
+	for _, check := range registry {
+		if check.ID == id {
+			return check, true
+		}
+	}
+	return Check{}, false
+}
+
+// IDs lists the registered check IDs in run order
+func IDs() []string {
+	var ids []string
+	for _, check := range Checks() {
+		ids = append(ids, check.ID)
+	}
+	return ids
+}
+
+// Report is the result of gos doctor
+type Report struct {
+	OK       bool     `json:"ok"` // no error is left
+	Errors   int      `json:"errors"`
+	Warnings int      `json:"warnings"`
+	Fixed    int      `json:"fixed"`
+	Results  []Result `json:"results"`
+}
+
+// Result is the outcome of one check
+type Result struct {
+	ID          string `json:"id"`
+	Section     string `json:"section"`
+	Status      string `json:"status"` // ok, or the check's severity when it failed
+	Message     string `json:"message"`
+	Explanation string `json:"explanation,omitempty"` // set when the check failed
+	Hint        string `json:"hint,omitempty"`
+	Fixable     bool   `json:"fixable"`             // the check failed and has a safe fix
+	Fixed       bool   `json:"fixed,omitempty"`     // --fix repaired it
+	FixError    string `json:"fix_error,omitempty"` // --fix tried and failed
+}
+
+// Run runs the checks. With fix, failed checks that have a fix are repaired
+// and detected again, so the report shows what is left.
+func Run(env *Env, checks []Check, fix bool) Report {
+	report := Report{Results: []Result{}}
+	for _, check := range checks {
+		result := run(env, check)
+		if fix && result.Fixable {
+			if err := check.Fix(env); err != nil {
+				result.FixError = err.Error()
+			} else {
+				fixed := run(env, check)
+				fixed.Fixed = fixed.Status == StatusOK
+				result = fixed
+			}
+		}
+
+		switch result.Status {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		}
+		if result.Fixed {
+			report.Fixed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.OK = report.Errors == 0
+	return report
+}
+
+// run detects one check
+func run(env *Env, check Check) Result {
+	finding := check.Detect(env)
+	result := Result{ID: check.ID, Section: check.Section, Status: StatusOK, Message: finding.Message, Hint: finding.Hint}
+	if finding.Problem {
+		result.Status = check.Severity
+		result.Explanation = check.Explanation
+		result.Fixable = check.Fix != nil
+	}
+	return result
+}
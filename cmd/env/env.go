@@ -1,41 +1,51 @@
 package env
 
 import (
+	"fmt"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/output"
 	"github.com/spf13/cobra"
 )
 
 // CreateEnvCommand creates the env command
 func CreateEnvCommand() *cobra.Command {
-	envCmd := &cobra.Command{
+	envCmd := output.Enable(&cobra.Command{
 		Use:   "env",
 		Short: "Show or fix environment configuration",
 		Long: `Show current environment configuration and optionally fix it.
 	
 Examples:
   gos env          # Show current environment
-  gos env --fix    # Fix environment configuration
+  gos env --fix    # Create GOPATH and set up the shell files, after confirmation
   gos env --export # Export current environment for sourcing
-  gos env --check  # Run comprehensive environment validation`,
-		Run: func(cmd *cobra.Command, args []string) {
+  gos env --check  # Run the gos doctor checks (exits 8 on errors)
+  gos env --check -o json  # The validation result as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			fix, _ := cmd.Flags().GetBool("fix")
 			export, _ := cmd.Flags().GetBool("export")
 			check, _ := cmd.Flags().GetBool("check")
 
+			if output.Structured() && !check {
+				return &errs.UsageError{Err: fmt.Errorf("--output %s is only supported with --check", output.Current())}
+			}
+
 			if export {
 				ExportEnvironment()
 			} else if fix {
-				FixEnvironment()
+				return FixEnvironment()
 			} else if check {
-				ValidateEnvironment()
+				return ValidateEnvironment()
 			} else {
 				ShowDetailedEnvironment()
 			}
+			return nil
 		},
-	}
+	})
 
-	envCmd.Flags().Bool("fix", false, "Fix environment configuration")
+	envCmd.Flags().Bool("fix", false, "Show and apply the GOPATH and shell file changes the environment needs")
 	envCmd.Flags().Bool("export", false, "Export environment variables for sourcing")
-	envCmd.Flags().Bool("check", false, "Run comprehensive environment validation")
+	envCmd.Flags().Bool("check", false, "Run the gos doctor checks")
 
 	return envCmd
 }
@@ -2,77 +2,168 @@ package env
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/doctor"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// FixEnvironment fixes environment configuration issues
-func FixEnvironment() {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
+// FixPlan is what gos env --fix changes
+type FixPlan struct {
+	Dirs  []string      // GOPATH directories to create
+	Edits []rcfile.Edit // shell files whose gos block is added or rewritten
+}
+
+// Empty reports whether there is nothing to change
+func (p FixPlan) Empty() bool {
+	return len(p.Dirs) == 0 && len(p.Edits) == 0
+}
+
+// PlanFix computes the changes the environment needs: the GOPATH
+// directories, and the gos block in the current shell's startup file and in
+// files holding snippets from older gos versions. The block loads `gos init`,
+// which puts the backend's bin directories ahead of any other Go in PATH.
+func PlanFix(env *doctor.Env) (FixPlan, error) {
+	var plan FixPlan
+	for _, dir := range []string{env.GOPATH, filepath.Join(env.GOPATH, "bin")} {
+		if !fsys.Exists(dir) {
+			plan.Dirs = append(plan.Dirs, dir)
+		}
+	}
+
+	targets := []rcfile.Target{rcfile.CurrentTarget()}
+	for _, target := range doctor.StaleTargets() {
+		if target.Path != targets[0].Path {
+			targets = append(targets, target)
+		}
+	}
+	for _, target := range targets {
+		edit, err := rcfile.PlanInit(target)
+		if err != nil {
+			return FixPlan{}, fmt.Errorf("reading %s: %w", target.Path, err)
+		}
+		if edit.Changed() {
+			plan.Edits = append(plan.Edits, edit)
+		}
+	}
+	return plan, nil
+}
+
+// Show prints the directories to create and the diff of every edit
+func (p FixPlan) Show() {
+	for _, dir := range p.Dirs {
+		ui.Out.Printf("  📁 Create %s\n", dir)
+	}
+	for _, edit := range p.Edits {
+		ui.Out.Println()
+		edit.PrintDiff()
+	}
+}
 
-	blue.Println("🔧 Fixing Go environment configuration...")
+// Apply creates the directories and writes the edits
+func (p FixPlan) Apply() error {
+	for _, dir := range p.Dirs {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", dir, err)
+		}
+		ui.Success.Printf("  ✅ Created %s\n", dir)
+	}
+	for _, edit := range p.Edits {
+		if err := edit.Write(); err != nil {
+			return fmt.Errorf("writing %s: %w", edit.Path, err)
+		}
+		ui.Success.Printf("  ✅ Updated %s\n", edit.Path)
+	}
+	return nil
+}
 
-	homeDir := os.Getenv("HOME")
-	expectedGopath := filepath.Join(homeDir, "go")
+// FixEnvironment shows the changes the environment needs for the detected
+// backend and shell, applies them once confirmed, and runs the checks again
+// to report what changed
+func FixEnvironment() error {
+	ui.Info.Println("🔧 Fixing Go environment configuration...")
 
-	// Create GOPATH directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Join(expectedGopath, "bin"), 0755); err == nil {
-		green.Printf("✅ Created GOPATH directory: %s\n", expectedGopath)
+	env := currentEnv()
+	before := doctor.Run(env, doctor.Checks(), false)
+
+	plan, err := PlanFix(env)
+	if err != nil {
+		return err
+	}
+	if plan.Empty() {
+		ui.Success.Println("✅ Nothing to change: GOPATH exists and the shell files load gos")
+		showRemaining(before)
+		return nil
 	}
 
-	// Add to shell configuration
-	shellFiles := []string{
-		filepath.Join(homeDir, ".zshrc"),
-		filepath.Join(homeDir, ".bashrc"),
+	ui.Note.Println()
+	ui.Title.Printf("📋 Planned changes for the %s backend:\n", env.Backend.Name())
+	plan.Show()
+	ui.Note.Println()
+
+	apply, err := ui.Confirm("Apply these changes?", false)
+	if err != nil {
+		return err
+	}
+	if !apply {
+		ui.Hint.Println("Nothing was changed.")
+		return nil
 	}
 
-	for _, shellFile := range shellFiles {
-		if _, err := os.Stat(shellFile); err == nil {
-			yellow.Printf("💡 Please add this to %s and restart your shell:\n", shellFile)
-			break
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+
+	after := doctor.Run(env, doctor.Checks(), false)
+	ui.Note.Println()
+	ui.Title.Println("📊 Result:")
+	for i, result := range after.Results {
+		if before.Results[i].Status != doctor.StatusOK && result.Status == doctor.StatusOK {
+			ui.Success.Printf("  ✅ Fixed: %s\n", result.Message)
 		}
 	}
+	showRemaining(after)
+	return nil
+}
 
-	ExportEnvironment()
-	
-	fmt.Println("")
-	yellow.Println("📋 To apply changes immediately, run:")
-	fmt.Println("  source ~/.zshrc")
-	fmt.Println("  # or")
-	fmt.Println("  eval $(gos env --export)")
+// showRemaining lists the problems env --fix does not solve. The shell files
+// load gos by now, so PATH problems only need the shell to be reloaded.
+func showRemaining(report doctor.Report) {
+	for _, result := range report.Results {
+		switch result.Status {
+		case doctor.SeverityError:
+			ui.Problem.Printf("  ❌ %s\n", result.Message)
+		case doctor.SeverityWarning:
+			ui.Caution.Printf("  ⚠️  %s\n", result.Message)
+		default:
+			continue
+		}
+		hint := result.Hint
+		if result.Section == doctor.SectionPath {
+			hint = "Open a new terminal, or run 'gos reload', to load the new PATH"
+		}
+		if hint != "" {
+			ui.Hint.Printf("    💡 %s\n", hint)
+		}
+	}
 }
 
-// ExportEnvironment exports environment variables for sourcing
+// ExportEnvironment prints the GOPATH export and the managed directories
+// missing from PATH, for sourcing. GOROOT is left unset: go finds its own.
 func ExportEnvironment() {
-	homeDir := os.Getenv("HOME")
-	expectedGoroot := filepath.Join(homeDir, ".g", "go")
-	expectedGopath := filepath.Join(homeDir, "go")
-	
-	fmt.Printf("export GOROOT=%s\n", expectedGoroot)
-	fmt.Printf("export GOPATH=%s\n", expectedGopath)
-	
-	// Build PATH
-	requiredPaths := []string{
-		filepath.Join(homeDir, ".g", "bin"),
-		filepath.Join(homeDir, ".g", "go", "bin"),
-		filepath.Join(homeDir, "go", "bin"),
-	}
-	
-	currentPath := os.Getenv("PATH")
-	newPaths := []string{}
-	
-	for _, reqPath := range requiredPaths {
-		if !strings.Contains(currentPath, reqPath) {
-			newPaths = append(newPaths, reqPath)
+	env := currentEnv()
+	fmt.Printf("export GOPATH=%s\n", env.GOPATH)
+
+	var missing []string
+	for _, dir := range env.ManagedDirs() {
+		if !env.InPath(dir) {
+			missing = append(missing, dir)
 		}
 	}
-	
-	if len(newPaths) > 0 {
-		fmt.Printf("export PATH=%s:$PATH\n", strings.Join(newPaths, ":"))
+	if len(missing) > 0 {
+		fmt.Printf("export PATH=%s:$PATH\n", strings.Join(missing, ":"))
 	}
 }
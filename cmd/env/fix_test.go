@@ -0,0 +1,96 @@
+package env
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/doctor"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+func TestPlanFix(t *testing.T) {
+	t.Run("missing GOPATH and shell setup", func(t *testing.T) {
+		home, mem, _ := testenv.Sandbox(t)
+		t.Setenv("SHELL", "/bin/bash")
+		stale := rcfile.Targets()[0]
+		legacy := "export EDITOR=vim\n\n# gos (Go version manager)\nexport GOROOT=$HOME/.gobrew/current/go\n"
+		mem.MkdirAll(filepath.Dir(stale.Path), 0755)
+		mem.WriteFile(stale.Path, []byte(legacy), 0644)
+
+		env := doctor.NewEnv(backend.NewNative())
+		plan, err := PlanFix(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gopath := filepath.Join(home, "go")
+		if len(plan.Dirs) != 2 || plan.Dirs[0] != gopath || plan.Dirs[1] != filepath.Join(gopath, "bin") {
+			t.Errorf("Dirs = %v", plan.Dirs)
+		}
+		bashrc := rcfile.CurrentTarget().Path
+		if len(plan.Edits) != 2 || plan.Edits[0].Path != bashrc || plan.Edits[1].Path != stale.Path {
+			t.Fatalf("Edits = %+v", plan.Edits)
+		}
+
+		if err := plan.Apply(); err != nil {
+			t.Fatal(err)
+		}
+		if !fsys.Exists(filepath.Join(gopath, "bin")) {
+			t.Error("GOPATH/bin was not created")
+		}
+		data, _ := mem.ReadFile(bashrc)
+		if !strings.Contains(string(data), shell.Bash.InitLine()) {
+			t.Errorf("%s =\n%s", bashrc, data)
+		}
+		data, _ = mem.ReadFile(stale.Path)
+		if strings.Contains(string(data), "GOROOT") || !strings.Contains(string(data), "EDITOR") {
+			t.Errorf("%s =\n%s", stale.Path, data)
+		}
+
+		if plan, err := PlanFix(env); err != nil || !plan.Empty() {
+			t.Errorf("PlanFix() after Apply = %+v, %v; want nothing to change", plan, err)
+		}
+	})
+}
+
+func TestFixEnvironment(t *testing.T) {
+	t.Run("--yes applies the plan", func(t *testing.T) {
+		home, mem, _ := testenv.Sandbox(t)
+		t.Setenv("SHELL", "/bin/bash")
+		previous := ui.Current()
+		ui.Configure(ui.Options{AssumeYes: true, Quiet: true})
+		t.Cleanup(func() { ui.Configure(previous) })
+
+		if err := FixEnvironment(); err != nil {
+			t.Fatal(err)
+		}
+		if !fsys.Exists(filepath.Join(home, "go", "bin")) {
+			t.Error("GOPATH/bin was not created")
+		}
+		data, _ := mem.ReadFile(rcfile.CurrentTarget().Path)
+		if !strings.Contains(string(data), shell.Bash.InitLine()) {
+			t.Errorf("shell file =\n%s", data)
+		}
+	})
+
+	t.Run("--no-input changes nothing", func(t *testing.T) {
+		home, _, _ := testenv.Sandbox(t)
+		t.Setenv("SHELL", "/bin/bash")
+		previous := ui.Current()
+		ui.Configure(ui.Options{NoInput: true, Quiet: true})
+		t.Cleanup(func() { ui.Configure(previous) })
+
+		if err := FixEnvironment(); err != nil {
+			t.Fatal(err)
+		}
+		if fsys.Exists(filepath.Join(home, "go")) || fsys.Exists(rcfile.CurrentTarget().Path) {
+			t.Error("files were changed without confirmation")
+		}
+	})
+}
@@ -1,287 +1,155 @@
 package env
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
-
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/doctor"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// ValidateEnvironment runs comprehensive environment validation
-func ValidateEnvironment() {
-	blue := color.New(color.FgBlue)
-	blue.Println("🔍 Comprehensive Environment Validation")
-	fmt.Println("")
-
-	config := getValidationConfig()
-	validationResult := &ValidationResult{}
-
-	// Run all validation checks
-	validateEnvironmentVariables(config, validationResult)
-	validatePathConfiguration(config, validationResult)
-	validateDirectoryStructure(config, validationResult)
-	validateShellConfiguration(config, validationResult)
-	hasVersionManager := validateVersionManager(validationResult)
-	validateGoBinary(hasVersionManager, validationResult)
-
-	// Display summary
-	displayValidationSummary(validationResult)
-}
-
-// ValidationResult holds the results of environment validation
-type ValidationResult struct {
-	HasErrors   bool
-	HasWarnings bool
-}
-
-// ValidationConfig extends EnvironmentConfig with validation-specific fields
-type ValidationConfig struct {
-	EnvironmentConfig
-	HomeDir      string
-	CurrentShell string
-	ShellFile    string
-}
-
-// getValidationConfig returns the validation configuration
-func getValidationConfig() *ValidationConfig {
-	homeDir := common.GetHomeDir()
-	envConfig := getEnvironmentConfig()
-
-	return &ValidationConfig{
-		EnvironmentConfig: envConfig,
-		HomeDir:           homeDir,
-		CurrentShell:      common.DetectCurrentShell(),
-		ShellFile:         common.GetShellFileForCurrentShell(common.DetectCurrentShell(), homeDir),
-	}
-}
-
-// validateEnvironmentVariables validates GOROOT and GOPATH
-func validateEnvironmentVariables(config *ValidationConfig, result *ValidationResult) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	red := color.New(color.FgRed)
+// ValidateEnvironment runs comprehensive environment validation; it fails
+// when a check found an error, warnings alone pass
+func ValidateEnvironment() error {
+	result := CheckEnvironment()
 
-	blue.Println("📋 Environment Variables:")
-
-	// GOROOT validation
-	actualGoroot := os.Getenv("GOROOT")
-	if actualGoroot == config.ExpectedGoroot {
-		green.Println("  ✅ GOROOT is correctly set")
-	} else if actualGoroot == "" {
-		red.Println("  ❌ GOROOT is not set")
-		result.HasErrors = true
+	if output.Structured() {
+		if err := output.Print(result); err != nil {
+			return err
+		}
 	} else {
-		yellow.Printf("  ⚠️  GOROOT is set to %s (expected %s)\n", actualGoroot, config.ExpectedGoroot)
-		result.HasWarnings = true
+		displayValidation(result)
 	}
 
-	// GOPATH validation
-	actualGopath := os.Getenv("GOPATH")
-	if actualGopath == config.ExpectedGopath {
-		green.Println("  ✅ GOPATH is correctly set")
-	} else if actualGopath == "" {
-		red.Println("  ❌ GOPATH is not set")
-		result.HasErrors = true
-	} else {
-		yellow.Printf("  ⚠️  GOPATH is set to %s (expected %s)\n", actualGopath, config.ExpectedGopath)
-		result.HasWarnings = true
+	if result.Errors > 0 {
+		return &errs.CheckFailedError{Problems: result.Errors}
 	}
+	return nil
 }
 
-// validatePathConfiguration validates PATH environment variable
-func validatePathConfiguration(config *ValidationConfig, result *ValidationResult) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-
-	fmt.Println("")
-	blue.Println("🛤️  PATH Validation:")
-
-	path := os.Getenv("PATH")
-	pathMissing := 0
-
-	for _, reqPath := range config.RequiredPaths {
-		if strings.Contains(path, reqPath) {
-			green.Printf("  ✅ %s is in PATH\n", reqPath)
-		} else {
-			yellow.Printf("  ⚠️  %s is missing from PATH\n", reqPath)
-			pathMissing++
-		}
-	}
-
-	if pathMissing > 0 {
-		fmt.Printf("    💡 Run 'gos setup' or 'gos env --fix' to add missing PATH entries\n")
-		result.HasWarnings = true
-	}
+// CheckEnvironment runs the gos doctor checks and reports them by section
+func CheckEnvironment() *ValidationResult {
+	return validate(doctor.Checks())
 }
 
-// validateDirectoryStructure validates required directories
-func validateDirectoryStructure(config *ValidationConfig, result *ValidationResult) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	red := color.New(color.FgRed)
-
-	fmt.Println("")
-	blue.Println("📁 Directory Structure:")
+// validate runs checks on the current environment
+func validate(checks []doctor.Check) *ValidationResult {
+	report := doctor.Run(currentEnv(), checks, false)
 
-	dirs := getRequiredDirectoriesForValidation(config)
-
-	for name, dir := range dirs {
-		if _, err := os.Stat(dir); err == nil {
-			green.Printf("  ✅ %s exists: %s\n", name, dir)
-		} else {
-			if strings.Contains(name, "GOPATH") {
-				yellow.Printf("  ⚠️  %s missing: %s\n", name, dir)
-				result.HasWarnings = true
-			} else {
-				red.Printf("  ❌ %s missing: %s\n", name, dir)
-				result.HasErrors = true
-			}
-		}
+	result := &ValidationResult{Checks: []Check{}}
+	for _, r := range report.Results {
+		result.add(r.Section, r.Status, r.Message, r.Hint)
 	}
+	result.OK = result.Errors == 0
+	return result
 }
 
-// getRequiredDirectoriesForValidation returns the map of required directories
-func getRequiredDirectoriesForValidation(config *ValidationConfig) map[string]string {
-	dirs := map[string]string{
-		"GOPATH":     config.ExpectedGopath,
-		"GOPATH bin": filepath.Join(config.ExpectedGopath, "bin"),
-	}
+// Check sections, in the order they run
+const (
+	SectionEnvironment = doctor.SectionEnvironment
+	SectionPath        = doctor.SectionPath
+	SectionDirectories = doctor.SectionDirectories
+	SectionShell       = doctor.SectionShell
+	SectionBackend     = doctor.SectionBackend
+	SectionGo          = doctor.SectionGo
+)
 
-	// Add version manager directories if detected
-	if common.IsCommandAvailable("gobrew") {
-		dirs["gobrew directory"] = filepath.Join(config.HomeDir, ".gobrew")
-		dirs["gobrew bin"] = filepath.Join(config.HomeDir, ".gobrew", "bin")
-		dirs["Go installation"] = config.ExpectedGoroot
-	}
+// Check statuses
+const (
+	StatusOK      = doctor.StatusOK
+	StatusInfo    = doctor.SeverityInfo
+	StatusWarning = doctor.SeverityWarning
+	StatusError   = doctor.SeverityError
+)
 
-	return dirs
+// ValidationResult is the result of gos env --check
+type ValidationResult struct {
+	OK       bool    `json:"ok"` // no check found an error
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+	Checks   []Check `json:"checks"`
 }
 
-// validateShellConfiguration validates shell configuration files
-func validateShellConfiguration(config *ValidationConfig, result *ValidationResult) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-
-	fmt.Println("")
-	blue.Println("🐚 Shell Configuration:")
-	blue.Printf("  🔍 Detected shell: %s\n", config.CurrentShell)
-
-	if config.ShellFile == "" {
-		yellow.Println("  ⚠️  Could not determine shell configuration file")
-		result.HasWarnings = true
-		return
-	}
-
-	fullPath := filepath.Join(config.HomeDir, config.ShellFile)
-	if _, err := os.Stat(fullPath); err == nil {
-		if hasGoConfig(fullPath) {
-			green.Printf("  ✅ Go configuration found in %s\n", config.ShellFile)
-		} else {
-			yellow.Printf("  ⚠️  %s exists but no Go configuration found\n", config.ShellFile)
-			fmt.Printf("    💡 Run 'gos setup' or 'gos env --fix' to add configuration\n")
-			result.HasWarnings = true
-		}
-	} else {
-		yellow.Printf("  ⚠️  Shell file %s does not exist\n", config.ShellFile)
-		fmt.Printf("    💡 Run 'gos setup' to create configuration\n")
-		result.HasWarnings = true
-	}
+// Check is the outcome of one validation check
+type Check struct {
+	Section string `json:"section"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"` // command that fixes the problem
 }
 
-// validateVersionManager validates version manager availability
-func validateVersionManager(result *ValidationResult) bool {
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
-
-	fmt.Println("")
-	blue.Println("🔧 Version Manager:")
-
-	if common.IsCommandAvailable("gobrew") {
-		return validateGobrewManager(result)
+// add records a check and counts errors and warnings
+func (r *ValidationResult) add(section, status, message, hint string) {
+	r.Checks = append(r.Checks, Check{Section: section, Status: status, Message: message, Hint: hint})
+	switch status {
+	case StatusError:
+		r.Errors++
+	case StatusWarning:
+		r.Warnings++
 	}
-
-	yellow.Println("  ⚠️  No version manager found (gobrew)")
-	fmt.Println("    💡 Run 'gos setup' to install a version manager")
-	result.HasWarnings = true
-	return false
 }
 
-// validateGobrewManager validates gobrew version manager
-func validateGobrewManager(result *ValidationResult) bool {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-
-	green.Println("  ✅ 'gobrew' version manager is available")
-
-	if versions := common.GetGobrewVersions(); len(versions) > 0 {
-		green.Printf("  ✅ %d Go version(s) installed\n", len(versions))
-	} else {
-		yellow.Println("  ⚠️  No Go versions installed with gobrew")
-		result.HasWarnings = true
-	}
-
-	return true
+// displayValidation prints the checks and the summary
+func displayValidation(result *ValidationResult) {
+	ui.Title.Println("🔍 Comprehensive Environment Validation")
+	showChecks(result)
+	displayValidationSummary(result)
 }
 
-// validateGoBinary validates Go binary availability
-func validateGoBinary(hasVersionManager bool, result *ValidationResult) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-
-	fmt.Println("")
-	blue.Println("🐹 Go Binary:")
+// showChecks prints the checks grouped by section, each section's hints
+// once after its checks
+func showChecks(result *ValidationResult) {
+	var section string
+	var hints []string
+	for _, check := range result.Checks {
+		if check.Section != section {
+			showHints(hints)
+			hints = nil
+			section = check.Section
+			ui.Out.Println("")
+			ui.Title.Println(doctor.SectionTitles[section])
+		}
 
-	if !hasVersionManager {
-		yellow.Println("  ℹ️  Skipping Go binary check (no version manager)")
-		return
+		showCheck(check)
+		if check.Hint != "" && (len(hints) == 0 || hints[len(hints)-1] != check.Hint) {
+			hints = append(hints, check.Hint)
+		}
 	}
+	showHints(hints)
+}
 
-	goPath, err := exec.LookPath("go")
-	if err != nil {
-		yellow.Println("  ⚠️  Go binary not found in PATH")
-		fmt.Println("    💡 Install a Go version with 'gos install latest'")
-		result.HasWarnings = true
-		return
+// showCheck prints one check with the icon and color of its status
+func showCheck(check Check) {
+	switch check.Status {
+	case StatusOK:
+		ui.Success.Printf("  ✅ %s\n", check.Message)
+	case StatusWarning:
+		ui.Caution.Printf("  ⚠️  %s\n", check.Message)
+	case StatusError:
+		ui.Problem.Printf("  ❌ %s\n", check.Message)
+	default:
+		ui.Out.Printf("  ℹ️  %s\n", check.Message)
 	}
+}
 
-	green.Printf("  ✅ Go binary found: %s\n", goPath)
-
-	// Check if go version works
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		version := strings.TrimSpace(string(output))
-		green.Printf("  ✅ Go version: %s\n", version)
-	} else {
-		yellow.Println("  ⚠️  Go binary exists but 'go version' failed")
-		result.HasWarnings = true
+// showHints prints the fixes suggested by a section's checks
+func showHints(hints []string) {
+	for _, hint := range hints {
+		ui.Hint.Printf("    💡 %s\n", hint)
 	}
 }
 
 // displayValidationSummary displays the final validation summary
 func displayValidationSummary(result *ValidationResult) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	red := color.New(color.FgRed)
-
-	fmt.Println("")
-	blue.Println("📊 Validation Summary:")
-
-	if result.HasErrors {
-		red.Println("  ❌ Environment has critical issues that need fixing")
-		fmt.Println("  💡 Run 'gos env --fix' to attempt automatic fixes")
-	} else if result.HasWarnings {
-		yellow.Println("  ⚠️  Environment has minor issues")
-		fmt.Println("  💡 Consider running 'gos env --fix' to optimize configuration")
+	ui.Out.Println("")
+	ui.Title.Println("📊 Validation Summary:")
+
+	if result.Errors > 0 {
+		ui.Problem.Println("  ❌ Environment has critical issues that need fixing")
+		ui.Hint.Println("  💡 Run 'gos doctor --fix' to apply the safe fixes")
+	} else if result.Warnings > 0 {
+		ui.Caution.Println("  ⚠️  Environment has minor issues")
+		ui.Hint.Println("  💡 Run 'gos doctor' for explanations")
 	} else {
-		green.Println("  ✅ Environment is properly configured!")
+		ui.Success.Println("  ✅ Environment is properly configured!")
 	}
 }
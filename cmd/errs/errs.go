@@ -0,0 +1,114 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+)
+
+// Exit codes gos returns; they are documented in the README
+const (
+	ExitOK                 = 0 // success
+	ExitFailure            = 1 // any other failure
+	ExitUsage              = 2 // invalid command, flag or argument
+	ExitVersionNotFound    = 3 // no installed or released Go version matches
+	ExitBackendMissing     = 4 // the selected backend's tooling is not installed
+	ExitChecksumMismatch   = 5 // a download failed SHA-256 verification
+	ExitPermissionDenied   = 6 // a file or directory could not be accessed
+	ExitNetworkUnreachable = 7 // go.dev or a mirror could not be reached
+	ExitCheckFailed        = 8 // a check (doctor, env --check) found problems
+)
+
+// VersionNotFoundError reports a version spec nothing matches
+type VersionNotFoundError struct {
+	Spec      string // version spec as requested
+	Installed bool   // the spec was matched against installed versions
+	Platform  string // set when the release exists but not for this platform
+}
+
+func (e *VersionNotFoundError) Error() string {
+	switch {
+	case e.Installed:
+		return fmt.Sprintf("no installed Go version matches %s", e.Spec)
+	case e.Platform != "":
+		return fmt.Sprintf("Go %s is not available for %s", e.Spec, e.Platform)
+	}
+	return fmt.Sprintf("no Go release matches %s", e.Spec)
+}
+
+// ExitCode implements the exit code lookup of Code
+func (e *VersionNotFoundError) ExitCode() int { return ExitVersionNotFound }
+
+// BackendMissingError reports a selected backend whose tooling is not installed
+type BackendMissingError struct {
+	Backend string
+}
+
+func (e *BackendMissingError) Error() string {
+	return fmt.Sprintf("%s is selected as backend but is not installed", e.Backend)
+}
+
+// ExitCode implements the exit code lookup of Code
+func (e *BackendMissingError) ExitCode() int { return ExitBackendMissing }
+
+// CheckFailedError reports problems found by a check
+type CheckFailedError struct {
+	Problems int
+}
+
+func (e *CheckFailedError) Error() string {
+	if e.Problems == 1 {
+		return "1 problem found"
+	}
+	return fmt.Sprintf("%d problems found", e.Problems)
+}
+
+// ExitCode implements the exit code lookup of Code
+func (e *CheckFailedError) ExitCode() int { return ExitCheckFailed }
+
+// InputRequiredError reports a prompt that cannot be answered because stdin
+// is not a terminal and neither --yes nor --no-input was given
+type InputRequiredError struct {
+	Prompt string
+}
+
+func (e *InputRequiredError) Error() string {
+	return fmt.Sprintf("%q needs an answer but stdin is not a terminal; pass --yes or --no-input (or set GOS_ASSUME_YES=1)", e.Prompt)
+}
+
+// ExitCode implements the exit code lookup of Code
+func (e *InputRequiredError) ExitCode() int { return ExitUsage }
+
+// UsageError wraps an invalid command line
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// ExitCode implements the exit code lookup of Code
+func (e *UsageError) ExitCode() int { return ExitUsage }
+
+// Code returns the exit code for err. Errors carrying their own code win;
+// permission and network errors are recognized anywhere in the chain.
+func Code(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	if errors.Is(err, fs.ErrPermission) {
+		return ExitPermissionDenied
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitNetworkUnreachable
+	}
+	return ExitFailure
+}
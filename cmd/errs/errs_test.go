@@ -0,0 +1,56 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "no error", err: nil, want: ExitOK},
+		{name: "plain error", err: errors.New("boom"), want: ExitFailure},
+		{name: "usage", err: &UsageError{Err: errors.New("bad flag")}, want: ExitUsage},
+		{name: "version not found", err: &VersionNotFoundError{Spec: "9.9.9"}, want: ExitVersionNotFound},
+		{name: "wrapped version not found", err: fmt.Errorf("installing: %w", &VersionNotFoundError{Spec: "9.9.9"}), want: ExitVersionNotFound},
+		{name: "backend missing", err: &BackendMissingError{Backend: "gobrew"}, want: ExitBackendMissing},
+		{name: "check failed", err: &CheckFailedError{Problems: 2}, want: ExitCheckFailed},
+		{name: "input required", err: &InputRequiredError{Prompt: "Continue?"}, want: ExitUsage},
+		{name: "permission denied", err: &fs.PathError{Op: "open", Path: "/root/.gos", Err: os.ErrPermission}, want: ExitPermissionDenied},
+		{name: "network unreachable", err: fmt.Errorf("fetching index: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), want: ExitNetworkUnreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionNotFoundError(t *testing.T) {
+	tests := []struct {
+		err  *VersionNotFoundError
+		want string
+	}{
+		{err: &VersionNotFoundError{Spec: "1.99"}, want: "no Go release matches 1.99"},
+		{err: &VersionNotFoundError{Spec: "1.21", Installed: true}, want: "no installed Go version matches 1.21"},
+		{err: &VersionNotFoundError{Spec: "1.21.5", Platform: "plan9/arm"}, want: "Go 1.21.5 is not available for plan9/arm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package execcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/install"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/cristobalcontreras/gos/cmd/usage"
+	"github.com/spf13/cobra"
+)
+
+// NewExecCmd creates the exec command
+func NewExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <version> -- <command> [args...]",
+		Short: "Run a command with a specific Go version",
+		Long: `Run a command with a specific Go version without changing the active one.
+
+The command runs with that toolchain's bin directory first in PATH, GOROOT
+pointing at it and GOTOOLCHAIN=local, so the go command never switches to
+another toolchain on its own. Stdin, signals and the exit code are passed
+through. If the version is not installed, gos offers to install it.`,
+		Example: `  gos exec 1.22.4 -- go test ./...     # Test with Go 1.22.4
+  gos exec 1.21 -- go build ./...      # Build with the newest installed 1.21.x
+  gos exec stable -- go version        # Run with the newest installed release`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Flag parsing stops at the version, so the "--" separator is still in args
+			command := args[1:]
+			if command[0] == "--" {
+				command = command[1:]
+			}
+			if len(command) == 0 {
+				return &errs.UsageError{Err: errors.New("no command given after --")}
+			}
+
+			b, err := backend.Active()
+			if err != nil {
+				return err
+			}
+			code, err := runWithVersion(b, args[0], command)
+			if err != nil {
+				return err
+			}
+			os.Exit(code)
+			return nil
+		},
+	}
+
+	// Everything after the version belongs to the command being run
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// runWithVersion runs command under the toolchain matching spec and returns
+// its exit code; the error reports failures of gos itself
+func runWithVersion(b backend.Backend, spec string, command []string) (int, error) {
+	version, err := backend.ResolveInstalled(b, spec)
+	if err != nil {
+		var notFound *errs.VersionNotFoundError
+		if !errors.As(err, &notFound) {
+			return 0, err
+		}
+		if err := offerInstall(b, spec, err); err != nil {
+			return 0, err
+		}
+		if version, err = backend.ResolveInstalled(b, spec); err != nil {
+			return 0, err
+		}
+	}
+
+	usage.Touch(version)
+	goroot := b.GOROOT(version)
+	env := toolchainEnv(os.Environ(), goroot, version)
+
+	// Look the command up in the child's PATH, not ours
+	os.Setenv("PATH", envValue(env, "PATH"))
+	binary, err := exec.LookPath(command[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gos: %s: command not found\n", command[0])
+		return 127, nil
+	}
+
+	return common.ExecProcess(binary, command, env), nil
+}
+
+// offerInstall asks whether a missing version should be installed; declining
+// returns notFound
+func offerInstall(b backend.Backend, spec string, notFound error) error {
+	ui.Warn.Printf("⚠️  No installed Go version matches %s\n", spec)
+	confirmed, err := ui.Confirm(fmt.Sprintf("Install it now with %s?", b.Name()), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Hint.Printf("💡 Install it later with: gos install %s\n", spec)
+		return notFound
+	}
+	return install.InstallVersionWith(b, spec)
+}
+
+// toolchainEnv returns env adjusted to run the toolchain at goroot: its bin
+// directory first in PATH, the gos shims removed so they cannot pick another
+// version, GOROOT set, GOTOOLCHAIN=local, and GOS_VERSION pinned for any
+// nested gos invocations
+func toolchainEnv(env []string, goroot, version string) []string {
+	shims := common.GetShimsDir()
+	paths := []string{filepath.Join(goroot, "bin")}
+	for _, dir := range filepath.SplitList(envValue(env, "PATH")) {
+		if dir != "" && filepath.Clean(dir) != shims {
+			paths = append(paths, dir)
+		}
+	}
+
+	overrides := map[string]string{
+		"PATH":          strings.Join(paths, string(os.PathListSeparator)),
+		"GOROOT":        goroot,
+		"GOTOOLCHAIN":   "local",
+		resolver.EnvVar: version,
+	}
+
+	result := make([]string, 0, len(env)+len(overrides))
+	for _, entry := range env {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := overrides[key]; !ok {
+			result = append(result, entry)
+		}
+	}
+	for _, key := range []string{"PATH", "GOROOT", "GOTOOLCHAIN", resolver.EnvVar} {
+		result = append(result, key+"="+overrides[key])
+	}
+	return result
+}
+
+// envValue returns the value of key in an environment list
+func envValue(env []string, key string) string {
+	for i := len(env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(env[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package execcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+)
+
+func TestToolchainEnv(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+
+	shims := filepath.Join(home, ".gos", "shims")
+	goroot := filepath.Join(home, ".gos", "versions", "1.22.4")
+	sep := string(os.PathListSeparator)
+
+	env := toolchainEnv([]string{
+		"PATH=" + strings.Join([]string{shims, "/usr/bin"}, sep),
+		"GOROOT=/usr/local/go",
+		"GOTOOLCHAIN=auto",
+		"EDITOR=vi",
+	}, goroot, "1.22.4")
+
+	t.Run("toolchain bin first and shims removed", func(t *testing.T) {
+		want := strings.Join([]string{filepath.Join(goroot, "bin"), "/usr/bin"}, sep)
+		if got := envValue(env, "PATH"); got != want {
+			t.Errorf("expected PATH %q, got %q", want, got)
+		}
+	})
+
+	t.Run("toolchain variables override the parent", func(t *testing.T) {
+		for key, want := range map[string]string{
+			"GOROOT":      goroot,
+			"GOTOOLCHAIN": "local",
+			"GOS_VERSION": "1.22.4",
+			"EDITOR":      "vi",
+		} {
+			if got := envValue(env, key); got != want {
+				t.Errorf("expected %s=%q, got %q", key, want, got)
+			}
+		}
+	})
+
+	t.Run("no duplicate entries", func(t *testing.T) {
+		seen := map[string]bool{}
+		for _, entry := range env {
+			key, _, _ := strings.Cut(entry, "=")
+			if seen[key] {
+				t.Errorf("duplicate %s entry", key)
+			}
+			seen[key] = true
+		}
+	})
+}
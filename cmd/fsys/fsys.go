@@ -0,0 +1,182 @@
+package fsys
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// maxLinks bounds how many symbolic links are followed, like the kernel does
+const maxLinks = 40
+
+// FS is the filesystem gos edits: shell startup files, the default version
+// file, its configuration and the directories clean removes
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	ReadDir(name string) ([]fs.DirEntry, error)
+	MkdirAll(path string, perm fs.FileMode) error
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	RemoveAll(path string) error
+	Chmod(name string, mode fs.FileMode) error
+	Symlink(oldname, newname string) error
+	Readlink(name string) (string, error)
+}
+
+// Default is the filesystem gos uses; tests swap it for a Mem or a
+// sandboxed directory with Replace
+var Default FS = OS{}
+
+// Replace makes f the Default filesystem until restore is called
+func Replace(f FS) (restore func()) {
+	previous := Default
+	Default = f
+	return func() { Default = previous }
+}
+
+// OS is the real filesystem
+type OS struct{}
+
+// Stat implements FS
+func (OS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+// Lstat implements FS
+func (OS) Lstat(name string) (fs.FileInfo, error) { return os.Lstat(name) }
+
+// ReadFile implements FS
+func (OS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+// WriteFile implements FS
+func (OS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// ReadDir implements FS
+func (OS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+// MkdirAll implements FS
+func (OS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+// Rename implements FS
+func (OS) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+// Remove implements FS
+func (OS) Remove(name string) error { return os.Remove(name) }
+
+// RemoveAll implements FS
+func (OS) RemoveAll(path string) error { return os.RemoveAll(path) }
+
+// Chmod implements FS
+func (OS) Chmod(name string, mode fs.FileMode) error { return os.Chmod(name, mode) }
+
+// Symlink implements FS
+func (OS) Symlink(oldname, newname string) error { return os.Symlink(oldname, newname) }
+
+// Readlink implements FS
+func (OS) Readlink(name string) (string, error) { return os.Readlink(name) }
+
+// Stat describes a file of the Default filesystem
+func Stat(name string) (fs.FileInfo, error) { return Default.Stat(name) }
+
+// Lstat describes a file of the Default filesystem without following a
+// symbolic link
+func Lstat(name string) (fs.FileInfo, error) { return Default.Lstat(name) }
+
+// Exists reports whether a file or directory exists
+func Exists(name string) bool {
+	_, err := Default.Stat(name)
+	return err == nil
+}
+
+// ReadFile reads a file of the Default filesystem
+func ReadFile(name string) ([]byte, error) { return Default.ReadFile(name) }
+
+// WriteFile writes a file of the Default filesystem
+func WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return Default.WriteFile(name, data, perm)
+}
+
+// ReadDir lists a directory of the Default filesystem, sorted by name
+func ReadDir(name string) ([]fs.DirEntry, error) { return Default.ReadDir(name) }
+
+// MkdirAll creates a directory and its parents in the Default filesystem
+func MkdirAll(path string, perm fs.FileMode) error { return Default.MkdirAll(path, perm) }
+
+// Rename moves a file of the Default filesystem
+func Rename(oldpath, newpath string) error { return Default.Rename(oldpath, newpath) }
+
+// Remove deletes a file or empty directory of the Default filesystem
+func Remove(name string) error { return Default.Remove(name) }
+
+// RemoveAll deletes a path and everything below it in the Default filesystem
+func RemoveAll(path string) error { return Default.RemoveAll(path) }
+
+// Chmod changes the mode of a file of the Default filesystem
+func Chmod(name string, mode fs.FileMode) error { return Default.Chmod(name, mode) }
+
+// Symlink creates newname as a symbolic link to oldname in the Default filesystem
+func Symlink(oldname, newname string) error { return Default.Symlink(oldname, newname) }
+
+// Readlink returns the target of a symbolic link of the Default filesystem
+func Readlink(name string) (string, error) { return Default.Readlink(name) }
+
+// IsLink reports whether name is a symbolic link
+func IsLink(name string) bool {
+	info, err := Default.Lstat(name)
+	return err == nil && info.Mode()&fs.ModeSymlink != 0
+}
+
+// FollowLinks returns the file name leads to once the symbolic links in its
+// last element are followed; relative targets are taken from the link's
+// directory. name is returned unchanged when it is not a link.
+func FollowLinks(name string) (string, error) {
+	for range maxLinks {
+		if !IsLink(name) {
+			return name, nil
+		}
+		target, err := Default.Readlink(name)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(name), target)
+		}
+		name = target
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: syscall.ELOOP}
+}
+
+// Walk calls fn for root and everything below it, directories before their
+// contents. Symbolic links below root are reported but not followed.
+func Walk(root string, fn func(path string, info fs.FileInfo) error) error {
+	info, err := Default.Stat(root)
+	if err != nil {
+		return err
+	}
+	return walk(root, info, fn)
+}
+
+func walk(path string, info fs.FileInfo, fn func(path string, info fs.FileInfo) error) error {
+	if err := fn(path, info); err != nil || !info.IsDir() {
+		return err
+	}
+
+	entries, err := Default.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		entryInfo, err := entry.Info()
+		if err != nil {
+			continue // removed while walking
+		}
+		if err := walk(filepath.Join(path, entry.Name()), entryInfo, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package fsys
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMem(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "home", "gopher")
+	file := filepath.Join(root, ".zshrc")
+
+	t.Run("write needs the parent directory", func(t *testing.T) {
+		m := NewMem()
+		if err := m.WriteFile(file, []byte("x"), 0644); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("WriteFile() error = %v, want fs.ErrNotExist", err)
+		}
+		if err := m.MkdirAll(root, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.WriteFile(file, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if data, err := m.ReadFile(file); err != nil || string(data) != "x" {
+			t.Errorf("ReadFile() = %q, %v", data, err)
+		}
+	})
+
+	t.Run("rewrite keeps the mode", func(t *testing.T) {
+		m := NewMem()
+		m.MkdirAll(root, 0755)
+		m.WriteFile(file, []byte("a"), 0600)
+		m.WriteFile(file, []byte("b"), 0644)
+
+		info, err := m.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode() != 0600 || info.Size() != 1 || info.Name() != ".zshrc" {
+			t.Errorf("Stat() = %v %d %s", info.Mode(), info.Size(), info.Name())
+		}
+	})
+
+	t.Run("directories", func(t *testing.T) {
+		m := NewMem()
+		m.MkdirAll(filepath.Join(root, "sdk", "go1.21.5"), 0755)
+		m.MkdirAll(filepath.Join(root, "sdk", "go1.22.1", "bin"), 0755)
+		m.WriteFile(filepath.Join(root, "sdk", "README"), nil, 0644)
+
+		entries, err := m.ReadDir(filepath.Join(root, "sdk"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		if want := []string{"README", "go1.21.5", "go1.22.1"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("ReadDir() = %v, want %v", names, want)
+		}
+
+		if err := m.Remove(filepath.Join(root, "sdk")); err == nil {
+			t.Error("Remove() deleted a directory that is not empty")
+		}
+		if err := m.RemoveAll(filepath.Join(root, "sdk", "go1.22.1")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Stat(filepath.Join(root, "sdk", "go1.22.1", "bin")); err == nil {
+			t.Error("RemoveAll() left the contents behind")
+		}
+	})
+
+	t.Run("rename moves contents", func(t *testing.T) {
+		m := NewMem()
+		m.MkdirAll(filepath.Join(root, "staging", "bin"), 0755)
+		m.WriteFile(filepath.Join(root, "staging", "bin", "go"), []byte("go"), 0755)
+
+		if err := m.Rename(filepath.Join(root, "staging"), filepath.Join(root, "go")); err != nil {
+			t.Fatal(err)
+		}
+		if data, err := m.ReadFile(filepath.Join(root, "go", "bin", "go")); err != nil || string(data) != "go" {
+			t.Errorf("ReadFile() after Rename = %q, %v", data, err)
+		}
+		if _, err := m.Stat(filepath.Join(root, "staging")); err == nil {
+			t.Error("Rename() left the old path behind")
+		}
+	})
+
+	t.Run("links", func(t *testing.T) {
+		m := NewMem()
+		m.MkdirAll(filepath.Join(root, "dotfiles"), 0755)
+		target := filepath.Join(root, "dotfiles", "zshrc")
+		m.WriteFile(target, []byte("a"), 0600)
+		if err := m.Symlink(filepath.Join("dotfiles", "zshrc"), file); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Symlink(target, file); !errors.Is(err, fs.ErrExist) {
+			t.Errorf("Symlink() over a link error = %v, want fs.ErrExist", err)
+		}
+
+		if info, err := m.Lstat(file); err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			t.Errorf("Lstat() = %v, %v, want a link", info, err)
+		}
+		if info, err := m.Stat(file); err != nil || info.Mode() != 0600 {
+			t.Errorf("Stat() = %v, %v, want the target", info, err)
+		}
+		if got, err := m.Readlink(file); err != nil || got != filepath.Join("dotfiles", "zshrc") {
+			t.Errorf("Readlink() = %q, %v", got, err)
+		}
+		if _, err := m.Readlink(target); err == nil {
+			t.Error("Readlink() of a regular file succeeded")
+		}
+
+		m.WriteFile(file, []byte("b"), 0644)
+		if data, _ := m.ReadFile(target); string(data) != "b" {
+			t.Errorf("WriteFile() through the link left the target %q", data)
+		}
+		if err := m.Remove(file); err != nil || !m.isDir(filepath.Dir(target)) {
+			t.Errorf("Remove() = %v", err)
+		}
+		if _, err := m.Stat(target); err != nil {
+			t.Error("Remove() of the link removed its target")
+		}
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		m := NewMem()
+		if _, err := m.ReadFile(file); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("ReadFile() error = %v", err)
+		}
+		if err := m.RemoveAll(file); err != nil {
+			t.Errorf("RemoveAll() of a missing path = %v", err)
+		}
+	})
+}
+
+func TestWalk(t *testing.T) {
+	walkTree := func(t *testing.T, root string) {
+		t.Helper()
+		MkdirAll(filepath.Join(root, "pkg", "mod"), 0755)
+		WriteFile(filepath.Join(root, "pkg", "mod", "cache"), nil, 0444)
+		WriteFile(filepath.Join(root, "bin"), nil, 0755)
+
+		var visited []string
+		err := Walk(root, func(path string, info fs.FileInfo) error {
+			rel, _ := filepath.Rel(root, path)
+			visited = append(visited, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{".", "bin", "pkg", "pkg/mod", "pkg/mod/cache"}
+		if !reflect.DeepEqual(visited, want) {
+			t.Errorf("Walk() visited %v, want %v", visited, want)
+		}
+	}
+
+	t.Run("os", func(t *testing.T) {
+		walkTree(t, t.TempDir())
+	})
+
+	t.Run("mem", func(t *testing.T) {
+		defer Replace(NewMem())()
+		walkTree(t, filepath.Join(string(filepath.Separator), "go"))
+	})
+}
+
+func TestFollowLinks(t *testing.T) {
+	defer Replace(NewMem())()
+	root := filepath.Join(string(filepath.Separator), "home", "gopher")
+	MkdirAll(filepath.Join(root, "dotfiles"), 0755)
+	target := filepath.Join(root, "dotfiles", "zshrc")
+	Symlink(filepath.Join("dotfiles", "zshrc"), filepath.Join(root, ".zshrc"))
+	Symlink(".zshrc", filepath.Join(root, ".zprofile"))
+	Symlink(".loop", filepath.Join(root, ".loop"))
+
+	tests := []struct {
+		name, want string
+	}{
+		{target, target},
+		{filepath.Join(root, ".zshrc"), target},
+		{filepath.Join(root, ".zprofile"), target},
+	}
+	for _, tt := range tests {
+		if got, err := FollowLinks(tt.name); err != nil || got != tt.want {
+			t.Errorf("FollowLinks(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := FollowLinks(filepath.Join(root, ".loop")); err == nil {
+		t.Error("FollowLinks() of a link loop succeeded")
+	}
+}
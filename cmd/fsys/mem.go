@@ -0,0 +1,302 @@
+package fsys
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Mem is an in-memory FS for tests. Paths are cleaned with filepath.Clean;
+// filesystem roots always exist. Symbolic links are only followed as the last
+// element of a path.
+type Mem struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode // includes fs.ModeDir or fs.ModeSymlink
+	modTime time.Time
+}
+
+// NewMem creates an empty in-memory filesystem
+func NewMem() *Mem {
+	return &Mem{files: map[string]*memFile{}}
+}
+
+// Stat implements FS
+func (m *Mem) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name, err := m.follow("stat", filepath.Clean(name))
+	if err != nil {
+		return nil, err
+	}
+	return m.lstat(name)
+}
+
+// Lstat implements FS
+func (m *Mem) Lstat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.lstat(filepath.Clean(name))
+}
+
+// lstat describes name without following it; the caller holds the lock
+func (m *Mem) lstat(name string) (fs.FileInfo, error) {
+	if isRoot(name) {
+		return memInfo{name: name, file: memFile{mode: fs.ModeDir | 0755}}, nil
+	}
+	f, ok := m.files[name]
+	if !ok {
+		return nil, pathError("stat", name, fs.ErrNotExist)
+	}
+	return memInfo{name: filepath.Base(name), file: *f}, nil
+}
+
+// ReadFile implements FS
+func (m *Mem) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name, err := m.follow("open", filepath.Clean(name))
+	if err != nil {
+		return nil, err
+	}
+	f, ok := m.files[name]
+	switch {
+	case !ok:
+		return nil, pathError("open", name, fs.ErrNotExist)
+	case f.mode.IsDir():
+		return nil, pathError("read", name, syscall.EISDIR)
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+// WriteFile implements FS; like os.WriteFile it keeps the mode of an
+// existing file and fails when the parent directory is missing
+func (m *Mem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name, err := m.follow("open", filepath.Clean(name))
+	if err != nil {
+		return err
+	}
+	if !m.isDir(filepath.Dir(name)) {
+		return pathError("open", name, fs.ErrNotExist)
+	}
+	if f, ok := m.files[name]; ok {
+		if f.mode.IsDir() {
+			return pathError("open", name, syscall.EISDIR)
+		}
+		perm = f.mode
+	}
+	m.files[name] = &memFile{data: append([]byte(nil), data...), mode: perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+// ReadDir implements FS
+func (m *Mem) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	if !m.isDir(name) {
+		return nil, pathError("open", name, fs.ErrNotExist)
+	}
+	var entries []fs.DirEntry
+	for path, f := range m.files {
+		if filepath.Dir(path) == name && path != name {
+			entries = append(entries, fs.FileInfoToDirEntry(memInfo{name: filepath.Base(path), file: *f}))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// MkdirAll implements FS
+func (m *Mem) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	for dir := path; !isRoot(dir); dir = filepath.Dir(dir) {
+		if f, ok := m.files[dir]; ok {
+			if !f.mode.IsDir() {
+				return pathError("mkdir", dir, syscall.ENOTDIR)
+			}
+			continue
+		}
+		m.files[dir] = &memFile{mode: fs.ModeDir | perm.Perm(), modTime: time.Now()}
+	}
+	return nil
+}
+
+// Rename implements FS; directories move with their contents
+func (m *Mem) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	if _, ok := m.files[oldpath]; !ok {
+		return pathError("rename", oldpath, fs.ErrNotExist)
+	}
+	if !m.isDir(filepath.Dir(newpath)) {
+		return pathError("rename", newpath, fs.ErrNotExist)
+	}
+	for path, f := range m.files {
+		if rel, ok := below(oldpath, path); ok {
+			delete(m.files, path)
+			m.files[filepath.Join(newpath, rel)] = f
+		}
+	}
+	return nil
+}
+
+// Remove implements FS
+func (m *Mem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	if _, ok := m.files[name]; !ok {
+		return pathError("remove", name, fs.ErrNotExist)
+	}
+	for path := range m.files {
+		if _, ok := below(name, path); ok && path != name {
+			return pathError("remove", name, syscall.ENOTEMPTY)
+		}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// RemoveAll implements FS
+func (m *Mem) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path = filepath.Clean(path)
+	for name := range m.files {
+		if _, ok := below(path, name); ok {
+			delete(m.files, name)
+		}
+	}
+	return nil
+}
+
+// Chmod implements FS
+func (m *Mem) Chmod(name string, mode fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	f, ok := m.files[name]
+	if !ok {
+		return pathError("chmod", name, fs.ErrNotExist)
+	}
+	f.mode = f.mode.Type() | mode.Perm()
+	return nil
+}
+
+// Symlink implements FS; the target is stored as given and may not exist
+func (m *Mem) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	newname = filepath.Clean(newname)
+	if _, ok := m.files[newname]; ok || isRoot(newname) {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: fs.ErrExist}
+	}
+	if !m.isDir(filepath.Dir(newname)) {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: fs.ErrNotExist}
+	}
+	m.files[newname] = &memFile{data: []byte(oldname), mode: fs.ModeSymlink | 0777, modTime: time.Now()}
+	return nil
+}
+
+// Readlink implements FS
+func (m *Mem) Readlink(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	f, ok := m.files[name]
+	switch {
+	case !ok:
+		return "", pathError("readlink", name, fs.ErrNotExist)
+	case f.mode&fs.ModeSymlink == 0:
+		return "", pathError("readlink", name, syscall.EINVAL)
+	}
+	return string(f.data), nil
+}
+
+// follow returns the file name leads to once symbolic links are followed;
+// the caller holds the lock
+func (m *Mem) follow(op, name string) (string, error) {
+	for range maxLinks {
+		f, ok := m.files[name]
+		if !ok || f.mode&fs.ModeSymlink == 0 {
+			return name, nil
+		}
+		target := string(f.data)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(name), target)
+		}
+		name = filepath.Clean(target)
+	}
+	return "", pathError(op, name, syscall.ELOOP)
+}
+
+// isDir reports whether name is a directory; the caller holds the lock
+func (m *Mem) isDir(name string) bool {
+	if isRoot(name) {
+		return true
+	}
+	f, ok := m.files[name]
+	return ok && f.mode.IsDir()
+}
+
+// isRoot reports whether path is a filesystem root such as / or C:\
+func isRoot(path string) bool {
+	return filepath.Dir(path) == path
+}
+
+// below reports whether path is dir or inside it, and returns the path relative to dir
+func below(dir, path string) (string, bool) {
+	if path == dir {
+		return "", true
+	}
+	prefix := dir
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	rel, ok := strings.CutPrefix(path, prefix)
+	return rel, ok
+}
+
+func pathError(op, path string, err error) error {
+	return &fs.PathError{Op: op, Path: path, Err: err}
+}
+
+// memInfo is the fs.FileInfo of a Mem file
+type memInfo struct {
+	name string
+	file memFile
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memInfo) ModTime() time.Time { return i.file.modTime }
+func (i memInfo) IsDir() bool        { return i.file.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
@@ -0,0 +1,208 @@
+package goversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Channels that resolve to a concrete version from the candidate list
+const (
+	Latest    = "latest"
+	Stable    = "stable"
+	OldStable = "oldstable"
+)
+
+// comparator is a single "op version" term of a constraint
+type comparator struct {
+	op      string
+	version Version
+}
+
+// Constraint is a parsed version spec: an exact version, a partial version
+// (1.21), a tilde (~1.22) or caret (^1.21) range, a set of comparators
+// (">=1.21 <1.23") or a channel (latest, stable, oldstable)
+type Constraint struct {
+	raw         string
+	channel     string
+	exact       *Version
+	comparators []comparator
+}
+
+// ParseConstraint parses a version spec
+func ParseConstraint(spec string) (Constraint, error) {
+	raw := strings.TrimSpace(spec)
+	c := Constraint{raw: raw}
+
+	switch strings.ToLower(raw) {
+	case "":
+		return c, fmt.Errorf("empty version spec")
+	case Latest, Stable, OldStable:
+		c.channel = strings.ToLower(raw)
+		return c, nil
+	}
+
+	// Exact or partial versions
+	if v, err := Parse(raw); err == nil {
+		if v.Partial {
+			next := v
+			next.Minor++
+			c.comparators = []comparator{{">=", v}, {"<", next}}
+		} else {
+			c.exact = &v
+		}
+		return c, nil
+	}
+
+	// Comparator lists, separated by spaces or commas
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return r == ' ' || r == ',' })
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		op := ""
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "~", "^"} {
+			if strings.HasPrefix(field, candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return c, fmt.Errorf("invalid version spec %q", spec)
+		}
+
+		operand := strings.TrimPrefix(field, op)
+		if operand == "" && i+1 < len(fields) {
+			// Allow ">= 1.21" with a space after the operator
+			i++
+			operand = fields[i]
+		}
+		v, err := Parse(operand)
+		if err != nil {
+			return c, fmt.Errorf("invalid version spec %q: %v", spec, err)
+		}
+
+		switch op {
+		case "~":
+			// ~1.22 and ~1.22.3 stay within the 1.22 line
+			upper := Version{Major: v.Major, Minor: v.Minor + 1}
+			c.comparators = append(c.comparators, comparator{">=", v}, comparator{"<", upper})
+		case "^":
+			// ^1.21 allows any newer Go 1 release
+			upper := Version{Major: v.Major + 1}
+			c.comparators = append(c.comparators, comparator{">=", v}, comparator{"<", upper})
+		default:
+			c.comparators = append(c.comparators, comparator{op, v})
+		}
+	}
+	return c, nil
+}
+
+// String returns the spec the constraint was parsed from
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// IsExact reports whether the constraint names a single concrete version
+func (c Constraint) IsExact() bool {
+	return c.exact != nil
+}
+
+// Channel returns the channel name, or "" when the constraint is not a channel
+func (c Constraint) Channel() string {
+	return c.channel
+}
+
+// Matches reports whether a version satisfies the constraint. Ranges only
+// match prereleases when one of their bounds is itself a prerelease.
+func (c Constraint) Matches(version string) bool {
+	v, err := Parse(version)
+	if err != nil || v.Partial && !isLegacyRelease(v) {
+		return false
+	}
+	v.Partial = false
+
+	if c.exact != nil {
+		exact := *c.exact
+		return Compare(v, exact) == 0
+	}
+	if c.channel != "" {
+		return v.Stable()
+	}
+
+	allowPre := false
+	for _, cmp := range c.comparators {
+		if !cmp.version.Stable() {
+			allowPre = true
+		}
+	}
+	if !v.Stable() && !allowPre {
+		return false
+	}
+
+	for _, cmp := range c.comparators {
+		bound := cmp.version
+		bound.Partial = false
+		d := Compare(v, bound)
+		ok := true
+		switch cmp.op {
+		case ">=":
+			ok = d >= 0
+		case ">":
+			ok = d > 0
+		case "<=":
+			ok = d <= 0
+		case "<":
+			ok = d < 0
+		case "=":
+			ok = d == 0
+		case "!=":
+			ok = d != 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isLegacyRelease reports whether a two-part version is an actual release
+// name; before Go 1.21 the first release of a line had no patch number
+func isLegacyRelease(v Version) bool {
+	return v.Major == 1 && v.Minor < 21
+}
+
+// Resolve picks the newest candidate satisfying spec. Channels resolve
+// against the candidates too: latest and stable pick the newest stable
+// version, oldstable the newest stable version of the previous minor line.
+func Resolve(spec string, candidates []string) (string, error) {
+	c, err := ParseConstraint(spec)
+	if err != nil {
+		return "", err
+	}
+
+	sorted := append([]string{}, candidates...)
+	Sort(sorted)
+
+	newest := ""
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if c.Matches(sorted[i]) {
+			newest = sorted[i]
+			break
+		}
+	}
+
+	if c.channel == OldStable && newest != "" {
+		top, _ := Parse(newest)
+		newest = ""
+		for i := len(sorted) - 1; i >= 0; i-- {
+			v, err := Parse(sorted[i])
+			if err == nil && v.Stable() && c.Matches(sorted[i]) && (v.Major < top.Major || v.Major == top.Major && v.Minor < top.Minor) {
+				newest = sorted[i]
+				break
+			}
+		}
+	}
+
+	if newest == "" {
+		return "", fmt.Errorf("no Go version matches %q", spec)
+	}
+	return Normalize(newest), nil
+}
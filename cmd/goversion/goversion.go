@@ -0,0 +1,152 @@
+package goversion
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed Go release number such as 1.21.5, 1.21rc2 or 1.21
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// Pre is the prerelease kind ("alpha", "beta" or "rc"); empty for releases
+	Pre    string
+	PreNum int
+	// Partial is set when only major.minor was given (e.g. "1.21")
+	Partial bool
+}
+
+var versionPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:(alpha|beta|rc)(\d+))?$`)
+
+// preOrder ranks prerelease kinds below the final release
+var preOrder = map[string]int{"alpha": 1, "beta": 2, "rc": 3, "": 4}
+
+// Normalize strips surrounding space and the optional "go" or "v" prefix
+func Normalize(version string) string {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(version, "go")
+	return strings.TrimPrefix(version, "v")
+}
+
+// Parse parses go1.21.5, v1.21.5, 1.21.5, 1.21rc2 and 1.21 style versions
+func Parse(version string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(Normalize(version))
+	if match == nil {
+		return Version{}, fmt.Errorf("invalid Go version %q", version)
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(match[1])
+	if match[2] == "" {
+		return Version{}, fmt.Errorf("invalid Go version %q: expected at least major.minor", version)
+	}
+	v.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		v.Patch, _ = strconv.Atoi(match[3])
+	}
+	if match[4] != "" {
+		v.Pre = match[4]
+		v.PreNum, _ = strconv.Atoi(match[5])
+	}
+	v.Partial = match[3] == "" && match[4] == ""
+	return v, nil
+}
+
+// IsValid reports whether a string parses as a Go version
+func IsValid(version string) bool {
+	_, err := Parse(version)
+	return err == nil
+}
+
+// String formats the version the way Go releases are named, without "go"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if !v.Partial && v.Pre == "" && !(v.Patch == 0 && v.Major == 1 && v.Minor < 21) {
+		s += fmt.Sprintf(".%d", v.Patch)
+	}
+	if v.Pre != "" {
+		s += fmt.Sprintf("%s%d", v.Pre, v.PreNum)
+	}
+	return s
+}
+
+// Stable reports whether the version is a final release
+func (v Version) Stable() bool {
+	return v.Pre == ""
+}
+
+// Compare returns -1, 0 or 1 when a is older than, equal to or newer than b.
+// Prereleases sort before the release they precede: 1.21rc2 < 1.21.0.
+func Compare(a, b Version) int {
+	for _, d := range []int{
+		a.Major - b.Major,
+		a.Minor - b.Minor,
+		preOrder[a.Pre] - preOrder[b.Pre],
+		a.PreNum - b.PreNum,
+		a.Patch - b.Patch,
+	} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Less reports whether version a sorts before b; unparsable versions sort
+// after valid ones, by name
+func Less(a, b string) bool {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return a < b
+	case errA != nil:
+		return false
+	case errB != nil:
+		return true
+	}
+	return Compare(va, vb) < 0
+}
+
+// Sort orders versions oldest first
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Less(versions[i], versions[j])
+	})
+}
+
+// Equal reports whether two version strings name the same release,
+// e.g. "go1.21.5" and "1.21.5", or "go1.20" and "1.20.0"
+func Equal(a, b string) bool {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	if errA != nil || errB != nil {
+		return Normalize(a) == Normalize(b)
+	}
+	va.Partial, vb.Partial = false, false
+	return Compare(va, vb) == 0
+}
+
+// FromGoVersionOutput extracts the version from `go version` output such as
+// "go version go1.21.5 darwin/arm64"; it returns "" when none is found
+func FromGoVersionOutput(output string) string {
+	fields := strings.Fields(output)
+	for i, field := range fields {
+		if field == "version" && i+1 < len(fields) && strings.HasPrefix(fields[i+1], "go") {
+			// Development builds report e.g. "go1.22-20240101-abc"; keep the release part
+			version := Normalize(fields[i+1])
+			if cut := strings.IndexAny(version, "- "); cut >= 0 {
+				version = version[:cut]
+			}
+			return version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package goversion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("accepts common spellings", func(t *testing.T) {
+		for _, input := range []string{"go1.21.5", "v1.21.5", "1.21.5", " 1.21.5 "} {
+			v, err := Parse(input)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", input, err)
+			}
+			if v.String() != "1.21.5" {
+				t.Errorf("Parse(%q) = %s, want 1.21.5", input, v)
+			}
+		}
+	})
+
+	t.Run("prereleases and partial versions", func(t *testing.T) {
+		rc, err := Parse("1.21rc2")
+		if err != nil || rc.Pre != "rc" || rc.PreNum != 2 || rc.Stable() {
+			t.Errorf("unexpected rc parse: %+v, %v", rc, err)
+		}
+
+		partial, err := Parse("1.21")
+		if err != nil || !partial.Partial {
+			t.Errorf("expected a partial version, got %+v, %v", partial, err)
+		}
+	})
+
+	t.Run("rejects garbage", func(t *testing.T) {
+		for _, input := range []string{"", "latest", "1", "1.x", "1.21.5-foo"} {
+			if _, err := Parse(input); err == nil {
+				t.Errorf("Parse(%q) should fail", input)
+			}
+		}
+	})
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"1.21.0", "1.9", "1.21rc2", "1.20.14", "1.21beta1", "1.10.3", "1.20"}
+	Sort(versions)
+
+	want := []string{"1.9", "1.10.3", "1.20", "1.20.14", "1.21beta1", "1.21rc2", "1.21.0"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("got %v, want %v", versions, want)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal("go1.21.5", "1.21.5") {
+		t.Error("go1.21.5 and 1.21.5 should be equal")
+	}
+	if Equal("1.21.5", "1.21.50") {
+		t.Error("1.21.5 and 1.21.50 must differ")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	candidates := []string{"1.20", "1.20.14", "1.21.0", "1.21.13", "1.22.0", "1.22.6", "1.23rc1", "1.22rc2"}
+
+	cases := map[string]string{
+		"1.21":           "1.21.13",
+		"go1.21.0":       "1.21.0",
+		"~1.22":          "1.22.6",
+		"~1.20.3":        "1.20.14",
+		">=1.21 <1.23":   "1.22.6",
+		">= 1.20, <1.21": "1.20.14",
+		"^1.20":          "1.22.6",
+		"<1.21":          "1.20.14",
+		"stable":         "1.22.6",
+		"latest":         "1.22.6",
+		"oldstable":      "1.21.13",
+		"1.23rc1":        "1.23rc1",
+		">=1.23rc1":      "1.23rc1",
+	}
+
+	for spec, want := range cases {
+		t.Run(spec, func(t *testing.T) {
+			got, err := Resolve(spec, candidates)
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		if _, err := Resolve("1.19", candidates); err == nil {
+			t.Error("expected an error when nothing matches")
+		}
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		if _, err := Resolve("newest", candidates); err == nil {
+			t.Error("expected an error for an invalid spec")
+		}
+	})
+}
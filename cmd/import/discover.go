@@ -0,0 +1,195 @@
+package importcmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+// Status of a discovered toolchain
+const (
+	StatusNew       = "new"       // can be imported
+	StatusManaged   = "managed"   // gos already has this version
+	StatusDuplicate = "duplicate" // the same version was found in an earlier place
+	StatusInvalid   = "invalid"   // `go version` failed
+	StatusImported  = "imported"
+	StatusFailed    = "failed"
+)
+
+// Toolchain is a Go installation found outside gos
+type Toolchain struct {
+	Source  string `json:"source"`
+	GOROOT  string `json:"goroot"`
+	Version string `json:"version,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Source is a place other tools install Go toolchains in
+type Source struct {
+	Name string
+	// Movable is false for installations a package manager or the system
+	// keeps track of; they are copied instead of moved
+	Movable bool
+	// Roots returns the candidate GOROOTs
+	Roots func() []string
+	// Dir returns the directory a move takes over for a GOROOT, when the
+	// manager keeps the GOROOT below a directory of its own; nil means the
+	// GOROOT itself
+	Dir func(goroot string) string
+}
+
+// Sources are the places gos import looks in, in order; when a version is
+// found twice the first place wins
+var Sources = []Source{
+	{Name: "gobrew", Movable: true, Roots: func() []string {
+		return glob(filepath.Join(common.GetHomeDir(), common.GobrewDir, "versions"), "", "go")
+	}, Dir: filepath.Dir},
+	{Name: "g", Movable: true, Roots: func() []string {
+		return glob(filepath.Join(common.GetHomeDir(), ".g", "versions"), "", "")
+	}},
+	{Name: "gvm", Movable: true, Roots: func() []string {
+		return glob(filepath.Join(envDir("GVM_ROOT", ".gvm"), "gos"), "go", "")
+	}},
+	{Name: "goenv", Movable: true, Roots: func() []string {
+		return glob(filepath.Join(envDir("GOENV_ROOT", ".goenv"), "versions"), "", "")
+	}},
+	{Name: "asdf", Movable: true, Roots: func() []string {
+		return glob(filepath.Join(envDir("ASDF_DATA_DIR", ".asdf"), "installs", "golang"), "", "go")
+	}},
+	{Name: "mise", Movable: true, Roots: func() []string {
+		data := envDir("MISE_DATA_DIR", "")
+		if data == "" {
+			data = filepath.Join(envDir("XDG_DATA_HOME", filepath.Join(".local", "share")), "mise")
+		}
+		return glob(filepath.Join(data, "installs", "go"), "", "")
+	}},
+	{Name: "sdk", Movable: true, Roots: func() []string {
+		return glob(filepath.Join(common.GetHomeDir(), "sdk"), "go", "")
+	}},
+	{Name: "homebrew", Roots: func() []string {
+		var roots []string
+		for _, prefix := range systemDirs("/opt/homebrew", "/usr/local", "/home/linuxbrew/.linuxbrew") {
+			cellar := filepath.Join(prefix, "Cellar")
+			entries, _ := fsys.ReadDir(cellar)
+			for _, entry := range entries {
+				if entry.Name() == "go" || strings.HasPrefix(entry.Name(), "go@") {
+					roots = append(roots, glob(filepath.Join(cellar, entry.Name()), "", "libexec")...)
+				}
+			}
+		}
+		return roots
+	}},
+	{Name: "/usr/local/go", Roots: func() []string {
+		return systemDirs("/usr/local/go")
+	}},
+}
+
+// SourceNames lists the names of the Sources
+func SourceNames() []string {
+	names := make([]string, len(Sources))
+	for i, source := range Sources {
+		names[i] = source.Name
+	}
+	return names
+}
+
+// Discover validates every candidate GOROOT of the sources with
+// `go version`. Versions isInstalled reports are marked as managed.
+func Discover(sources []Source, isInstalled func(version string) bool) []Toolchain {
+	found := []Toolchain{}
+	seen := map[string]bool{}
+	for _, source := range sources {
+		for _, root := range source.Roots() {
+			if !fsys.Exists(goBinary(root)) {
+				continue
+			}
+
+			toolchain := Toolchain{Source: source.Name, GOROOT: root}
+			toolchain.Version, toolchain.Error = validate(root)
+			switch {
+			case toolchain.Error != "":
+				toolchain.Status = StatusInvalid
+			case seen[toolchain.Version]:
+				toolchain.Status = StatusDuplicate
+			case isInstalled(toolchain.Version):
+				toolchain.Status = StatusManaged
+			default:
+				toolchain.Status = StatusNew
+			}
+			if toolchain.Version != "" {
+				seen[toolchain.Version] = true
+			}
+			found = append(found, toolchain)
+		}
+	}
+	return found
+}
+
+// validate runs the toolchain's go and returns the version it reports
+func validate(root string) (string, string) {
+	output, err := runner.Output(goBinary(root), "version")
+	if err != nil {
+		return "", err.Error()
+	}
+	version := goversion.FromGoVersionOutput(output)
+	if version == "" {
+		return "", "unexpected output: " + strings.TrimSpace(output)
+	}
+	return version, ""
+}
+
+// goBinary returns the go executable of a GOROOT
+func goBinary(root string) string {
+	name := "go"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(root, "bin", name)
+}
+
+// glob lists the subdirectories of dir whose names start with prefix, each
+// joined with sub. Links are skipped: managers keep aliases such as
+// "latest" or "1.22" as links to real versions.
+func glob(dir, prefix, sub string) []string {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var roots []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		roots = append(roots, filepath.Join(dir, entry.Name(), sub))
+	}
+	return roots
+}
+
+// envDir returns the directory a manager's environment variable names, or
+// fallback below the home directory. With GOS_HOME set the variables are
+// ignored, since they point into the real home directory.
+func envDir(name, fallback string) string {
+	if dir := os.Getenv(name); dir != "" && !common.Sandboxed() {
+		return dir
+	}
+	if fallback == "" {
+		return ""
+	}
+	return filepath.Join(common.GetHomeDir(), fallback)
+}
+
+// systemDirs returns dirs, or nothing when GOS_HOME keeps gos away from
+// machine-wide installations
+func systemDirs(dirs ...string) []string {
+	if common.Sandboxed() {
+		return nil
+	}
+	return dirs
+}
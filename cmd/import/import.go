@@ -0,0 +1,220 @@
+package importcmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// Options selects what gos import adopts and how
+type Options struct {
+	Versions []string // only these versions; all when empty
+	From     []string // only these sources; all when empty
+	Mode     string   // toolchain.AdoptCopy, AdoptMove or AdoptLink
+	List     bool     // show what was found without importing
+}
+
+// NewImportCmd creates the import command
+func NewImportCmd() *cobra.Command {
+	var opts Options
+
+	cmd := output.Enable(&cobra.Command{
+		Use:   "import [version...]",
+		Short: "Import Go toolchains installed by other managers",
+		Long: `Find Go toolchains installed outside gos and adopt them as versions of the
+native backend (~/.gos/versions), so they survive 'gos clean' and can be
+selected with 'gos use'.
+
+Looked in: ` + strings.Join(SourceNames(), ", ") + `
+
+Each toolchain is checked with 'go version' first. --mode copy (the default)
+leaves the original alone, move takes it over and link registers it in place.
+Homebrew and /usr/local/go toolchains are always copied, since brew and
+the system keep track of them. Linked
+toolchains break when their manager removes them, and 'gos clean' removes
+gvm, goenv, g and ~/sdk.`,
+		Example: `  gos import --list            # Show what would be imported
+  gos import                   # Copy every toolchain found
+  gos import 1.21.5 1.22       # Only these versions
+  gos import --from gvm,sdk    # Only from gvm and ~/sdk
+  gos import --mode link       # Use them where they are`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Versions = args
+			return RunImport(opts)
+		},
+	})
+
+	cmd.Flags().StringVar(&opts.Mode, "mode", toolchain.AdoptCopy, "How to adopt a toolchain: "+strings.Join(toolchain.AdoptModes, ", "))
+	cmd.Flags().StringSliceVar(&opts.From, "from", nil, "Only look in these places: "+strings.Join(SourceNames(), ", "))
+	cmd.Flags().BoolVar(&opts.List, "list", false, "Show the toolchains found without importing them")
+	return cmd
+}
+
+// RunImport discovers toolchains, asks for confirmation and adopts the new
+// ones into the native backend
+func RunImport(opts Options) error {
+	if !slices.Contains(toolchain.AdoptModes, opts.Mode) {
+		return &errs.UsageError{Err: fmt.Errorf("unknown mode %q (modes: %s)", opts.Mode, strings.Join(toolchain.AdoptModes, ", "))}
+	}
+	sources, err := selectSources(opts.From)
+	if err != nil {
+		return err
+	}
+
+	native := backend.NewNative()
+	found := selectVersions(Discover(sources, native.Installer.IsInstalled), opts.Versions)
+
+	importable := 0
+	for _, t := range found {
+		if t.Status == StatusNew {
+			importable++
+		}
+	}
+
+	if !output.Structured() {
+		showFound(found)
+	}
+	if opts.List || importable == 0 {
+		if output.Structured() {
+			return output.Print(found)
+		}
+		if importable == 0 && len(found) > 0 {
+			ui.Success.Println("✅ Nothing to import")
+		}
+		return nil
+	}
+
+	apply, err := ui.Confirm(fmt.Sprintf("Import %d toolchain(s) into %s (%s)?", importable, native.Installer.VersionsDir(), opts.Mode), opts.Mode != toolchain.AdoptMove)
+	if err != nil {
+		return err
+	}
+	if !apply {
+		ui.Hint.Println("Nothing was imported.")
+		return nil
+	}
+
+	failed := adopt(native.Installer, found, sources, opts.Mode)
+	shim.Refresh(native)
+	if output.Structured() {
+		if err := output.Print(found); err != nil {
+			return err
+		}
+	}
+	if imported := importable - failed; imported > 0 {
+		ui.Hint.Println("🎯 Switch with: gos use <version>")
+		if name := config.Get("backend"); name != "" && name != "native" && name != "gos" {
+			ui.Hint.Printf("💡 The %s backend is selected; imported versions belong to the native backend\n", name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d toolchain(s) could not be imported", failed)
+	}
+	return nil
+}
+
+// adopt imports the new toolchains, updating their status, and returns how
+// many failed
+func adopt(installer *toolchain.Installer, found []Toolchain, sources []Source, mode string) int {
+	byName := map[string]Source{}
+	for _, source := range sources {
+		byName[source.Name] = source
+	}
+
+	failed := 0
+	for i := range found {
+		t := &found[i]
+		if t.Status != StatusNew {
+			continue
+		}
+
+		source := byName[t.Source]
+		how := mode
+		if how == toolchain.AdoptMove && !source.Movable {
+			how = toolchain.AdoptCopy
+		}
+		if err := installer.Adopt(t.GOROOT, t.Version, how); err != nil {
+			t.Status, t.Error = StatusFailed, err.Error()
+			ui.Problem.Printf("  ❌ Go %s from %s: %v\n", t.Version, t.Source, err)
+			failed++
+			continue
+		}
+		// Take the manager's whole version directory, not just the GOROOT in it
+		if how == toolchain.AdoptMove && source.Dir != nil {
+			if err := os.RemoveAll(source.Dir(t.GOROOT)); err != nil {
+				ui.Warn.Printf("⚠️  Could not remove %s: %v\n", source.Dir(t.GOROOT), err)
+			}
+		}
+		t.Status = StatusImported
+		ui.Success.Printf("  ✅ Imported Go %s from %s (%s)\n", t.Version, t.Source, how)
+	}
+	return failed
+}
+
+// selectSources returns the sources with the given names, or every source
+func selectSources(names []string) ([]Source, error) {
+	if len(names) == 0 {
+		return Sources, nil
+	}
+	var sources []Source
+	for _, name := range names {
+		i := slices.IndexFunc(Sources, func(s Source) bool { return s.Name == name })
+		if i < 0 {
+			return nil, &errs.UsageError{Err: fmt.Errorf("unknown source %q (sources: %s)", name, strings.Join(SourceNames(), ", "))}
+		}
+		sources = append(sources, Sources[i])
+	}
+	return sources, nil
+}
+
+// selectVersions keeps the toolchains matching one of the versions; a
+// partial version such as 1.22 matches every 1.22.x
+func selectVersions(found []Toolchain, versions []string) []Toolchain {
+	if len(versions) == 0 {
+		return found
+	}
+	selected := []Toolchain{}
+	for _, t := range found {
+		for _, version := range versions {
+			v := goversion.Normalize(version)
+			if goversion.Equal(t.Version, v) || strings.HasPrefix(t.Version, v+".") {
+				selected = append(selected, t)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// showFound lists the toolchains found and what happens to each
+func showFound(found []Toolchain) {
+	if len(found) == 0 {
+		ui.Out.Println("ℹ️  No Go toolchains found outside gos")
+		return
+	}
+
+	ui.Title.Printf("📦 Found %d Go toolchain(s):\n", len(found))
+	for _, t := range found {
+		switch t.Status {
+		case StatusNew:
+			ui.Out.Printf("  ➕ Go %-10s %-14s %s\n", t.Version, t.Source, t.GOROOT)
+		case StatusManaged:
+			ui.Out.Printf("  ✅ Go %-10s %-14s %s (already in gos)\n", t.Version, t.Source, t.GOROOT)
+		case StatusDuplicate:
+			ui.Out.Printf("  ⏭️  Go %-10s %-14s %s (found earlier)\n", t.Version, t.Source, t.GOROOT)
+		case StatusInvalid:
+			ui.Caution.Printf("  ⚠️  %-13s %-14s %s: %s\n", "?", t.Source, t.GOROOT, t.Error)
+		}
+	}
+	ui.Out.Println()
+}
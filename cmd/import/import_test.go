@@ -0,0 +1,144 @@
+package importcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+// fakeToolchain puts a go binary below root and scripts its `go version` output
+func fakeToolchain(t *testing.T, mem *fsys.Mem, fake *runner.Fake, root, output string) {
+	t.Helper()
+	bin := filepath.Dir(goBinary(root))
+	if err := mem.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteFile(goBinary(root), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if output == "" {
+		fake.On(goBinary(root)+" version", runner.Response{ExitCode: 2})
+	} else {
+		fake.On(goBinary(root)+" version", runner.Response{Stdout: output})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	home, mem, fake := testenv.Sandbox(t)
+	gvm := filepath.Join(home, ".gvm", "gos", "go1.22.1")
+	sdk := filepath.Join(home, "sdk", "go1.22.1")
+	goenv := filepath.Join(home, ".goenv", "versions", "1.21.5")
+	asdf := filepath.Join(home, ".asdf", "installs", "golang", "1.20.14", "go")
+	broken := filepath.Join(home, "sdk", "go1.19")
+	fakeToolchain(t, mem, fake, gvm, "go version go1.22.1 linux/amd64\n")
+	fakeToolchain(t, mem, fake, sdk, "go version go1.22.1 linux/amd64\n")
+	fakeToolchain(t, mem, fake, goenv, "go version go1.21.5 linux/amd64\n")
+	fakeToolchain(t, mem, fake, asdf, "go version go1.20.14 linux/amd64\n")
+	fakeToolchain(t, mem, fake, broken, "")
+	mem.MkdirAll(filepath.Join(home, "sdk", "gotip"), 0755) // no bin/go
+
+	found := Discover(Sources, func(version string) bool { return version == "1.21.5" })
+
+	want := map[string]struct{ version, source, status string }{
+		gvm:    {"1.22.1", "gvm", StatusNew},
+		goenv:  {"1.21.5", "goenv", StatusManaged},
+		asdf:   {"1.20.14", "asdf", StatusNew},
+		sdk:    {"1.22.1", "sdk", StatusDuplicate},
+		broken: {"", "sdk", StatusInvalid},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("Discover() = %+v", found)
+	}
+	for _, got := range found {
+		w, ok := want[got.GOROOT]
+		if !ok {
+			t.Errorf("unexpected toolchain %+v", got)
+			continue
+		}
+		if got.Version != w.version || got.Source != w.source || got.Status != w.status {
+			t.Errorf("%s = %+v, want %+v", got.GOROOT, got, w)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Run("versions", func(t *testing.T) {
+		found := []Toolchain{{Version: "1.21.5"}, {Version: "1.22.0"}, {Version: "1.22.1"}, {Version: "1.2"}}
+		got := selectVersions(found, []string{"go1.22", "1.21.5"})
+		if len(got) != 3 || got[0].Version != "1.21.5" || got[2].Version != "1.22.1" {
+			t.Errorf("selectVersions() = %+v", got)
+		}
+	})
+
+	t.Run("sources", func(t *testing.T) {
+		sources, err := selectSources([]string{"sdk", "gvm"})
+		if err != nil || len(sources) != 2 || sources[0].Name != "sdk" {
+			t.Errorf("selectSources() = %+v, %v", sources, err)
+		}
+		if _, err := selectSources([]string{"nix"}); errs.Code(err) != errs.ExitUsage {
+			t.Errorf("selectSources() error = %v, want a usage error", err)
+		}
+	})
+
+	t.Run("machine-wide places are skipped in a sandbox", func(t *testing.T) {
+		testenv.Sandbox(t)
+		for _, source := range Sources {
+			if source.Name == "homebrew" || source.Name == "/usr/local/go" {
+				if roots := source.Roots(); len(roots) != 0 {
+					t.Errorf("%s roots = %v", source.Name, roots)
+				}
+			}
+		}
+	})
+}
+
+func TestAdopt(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+	installer := &toolchain.Installer{Root: filepath.Join(home, ".gos")}
+
+	toolchainIn := func(t *testing.T, root string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(goBinary(root)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goBinary(root), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gobrew := filepath.Join(home, common.GobrewDir, "versions", "1.22.1", "go")
+	system := filepath.Join(home, "usr", "local", "go")
+	toolchainIn(t, gobrew)
+	toolchainIn(t, system)
+
+	found := []Toolchain{
+		{Source: "gobrew", GOROOT: gobrew, Version: "1.22.1", Status: StatusNew},
+		{Source: "/usr/local/go", GOROOT: system, Version: "1.21.5", Status: StatusNew},
+	}
+	previous := ui.Current()
+	ui.Configure(ui.Options{Quiet: true})
+	t.Cleanup(func() { ui.Configure(previous) })
+
+	if failed := adopt(installer, found, Sources, toolchain.AdoptMove); failed != 0 {
+		t.Fatalf("adopt() failed for %+v", found)
+	}
+	for _, version := range []string{"1.22.1", "1.21.5"} {
+		if _, err := os.Stat(goBinary(installer.VersionDir(version))); err != nil {
+			t.Errorf("Go %s was not imported: %v", version, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Dir(gobrew)); !os.IsNotExist(err) {
+		t.Errorf("gobrew's version directory was left behind: %v", err)
+	}
+	if _, err := os.Stat(goBinary(system)); err != nil {
+		t.Errorf("/usr/local/go was moved instead of copied: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package initcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/install"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/session"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// Session variables the auto-switch hook keeps its state in
+const (
+	autoBinVar    = "__GOS_AUTO_BIN"    // bin directory the hook put first in PATH
+	autoWarnedVar = "__GOS_AUTO_WARNED" // setting the hook last warned about
+)
+
+// Configuration keys for the auto-switch hook
+const (
+	AutoSwitchKey  = "auto_switch"
+	AutoInstallKey = "auto_install"
+)
+
+// NewHookEnvCmd creates the hidden hook-env command the auto-switch hook calls
+func NewHookEnvCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:    "hook-env <shell>",
+		Short:  "Print the PATH update for the current directory (used by the auto-switch hook)",
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// stdout is evaluated by the shell; errors are printed to stderr
+			sh, err := shell.Parse(args[0])
+			if err != nil {
+				return &errs.UsageError{Err: err}
+			}
+			b, err := backend.Detect()
+			if err != nil {
+				return &errs.UsageError{Err: err}
+			}
+			dir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(HookEnv(sh, b, dir, os.Getenv, config.Enabled(AutoInstallKey)))
+			return nil
+		},
+	}
+}
+
+// HookEnv returns the shell code that puts the toolchain resolved for dir
+// first in the session's PATH, undoing what the hook did for the previous
+// directory. Only project settings switch; without one, or when the active
+// version already satisfies the setting, the global PATH applies. A missing
+// version is installed when autoInstall is set and reported once on stderr
+// otherwise.
+func HookEnv(sh shell.Shell, b backend.Backend, dir string, getenv func(string) string, autoInstall bool) string {
+	var out strings.Builder
+
+	previous := getenv(autoBinVar)
+	paths := session.RemoveEntry(filepath.SplitList(getenv("PATH")), previous)
+
+	// A version picked with `gos use --shell` wins over project settings
+	bin, warning := "", ""
+	if result, err := resolver.Resolve(dir); err == nil && result.Found() && result.Source != resolver.SourceDefault && getenv(session.BinVar) == "" {
+		bin, warning = sessionBin(b, result, autoInstall)
+	}
+
+	if bin != "" {
+		paths = append([]string{bin}, paths...)
+	}
+	if strings.Join(paths, string(os.PathListSeparator)) != getenv("PATH") {
+		out.WriteString(sh.SetPath(paths) + "\n")
+	}
+
+	switch {
+	case bin != "":
+		out.WriteString(sh.Export(autoBinVar, bin) + "\n")
+	case previous != "":
+		out.WriteString(sh.Unset(autoBinVar) + "\n")
+	}
+
+	// Warn once per setting rather than on every directory change inside the project
+	switch {
+	case warning != "" && getenv(autoWarnedVar) != warning:
+		ui.Warn.Println("⚠️  gos: " + warning)
+		out.WriteString(sh.Export(autoWarnedVar, warning) + "\n")
+	case warning == "" && getenv(autoWarnedVar) != "":
+		out.WriteString(sh.Unset(autoWarnedVar) + "\n")
+	}
+
+	return out.String()
+}
+
+// sessionBin returns the bin directory to put first in PATH for a project
+// setting, or a warning when no installed version satisfies it
+func sessionBin(b backend.Backend, result resolver.Result, autoInstall bool) (bin, warning string) {
+	constraint, err := goversion.ParseConstraint(result.Version)
+	if err != nil {
+		return "", fmt.Sprintf("invalid Go version %q in %s", result.Version, result.Path)
+	}
+
+	// The globally active version is already on PATH
+	if current, err := b.Current(); err == nil && constraint.Channel() == "" && constraint.Matches(current) {
+		return "", ""
+	}
+
+	version, err := backend.ResolveInstalled(b, result.Version)
+	if err != nil && autoInstall {
+		if installErr := installToStderr(b, result.Version); installErr != nil {
+			return "", fmt.Sprintf("installing Go %s (%s) failed: %v", result.Version, describeSource(result), installErr)
+		}
+		version, err = backend.ResolveInstalled(b, result.Version)
+	}
+	if err != nil {
+		return "", fmt.Sprintf("Go %s (%s) is not installed; run: gos install %s",
+			result.Version, describeSource(result), installArg(result.Version))
+	}
+	return filepath.Join(b.GOROOT(version), "bin"), ""
+}
+
+// installToStderr installs a version with its progress output on stderr,
+// since the hook's stdout is evaluated by the shell
+func installToStderr(b backend.Backend, spec string) error {
+	_, restore := session.DivertOutput()
+	defer restore()
+
+	return install.InstallVersionWith(b, spec)
+}
+
+// describeSource names where a setting came from for warnings
+func describeSource(result resolver.Result) string {
+	if result.Path == "" {
+		return result.Source
+	}
+	return result.Path
+}
+
+// installArg quotes a version spec that the shell would otherwise interpret
+func installArg(spec string) string {
+	if strings.ContainsAny(spec, "<>=~^ ,") {
+		return "'" + spec + "'"
+	}
+	return spec
+}
+
+// hookScript returns the code that runs hook-env whenever the shell's working
+// directory changes
+func hookScript(sh shell.Shell) string {
+	switch sh {
+	case shell.Zsh:
+		return `
+# Auto-switch Go on directory change
+__gos_hook() {
+    eval "$(command gos hook-env zsh)"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd __gos_hook
+__gos_hook
+`
+	case shell.Fish:
+		return `
+# Auto-switch Go on directory change
+function __gos_hook --on-variable PWD --description 'Switch Go for the current directory'
+    command gos hook-env fish | source
+end
+__gos_hook
+`
+	case shell.Pwsh:
+		return `
+# Auto-switch Go on directory change
+if (-not $global:__gosPrompt) { $global:__gosPrompt = $function:prompt }
+function global:prompt {
+    if ($PWD.Path -ne $global:__gosLastPwd) {
+        $global:__gosLastPwd = $PWD.Path
+        $gosBinary = Get-Command gos -CommandType Application | Select-Object -First 1
+        (& $gosBinary.Source hook-env pwsh) -join "` + "`" + `n" | Invoke-Expression
+    }
+    & $global:__gosPrompt
+}
+`
+	}
+	// bash has no directory change hook, so check PWD before each prompt
+	return `
+# Auto-switch Go on directory change
+__gos_hook() {
+    local __gos_status=$?
+    if [ "$PWD" != "${__GOS_LAST_PWD:-}" ]; then
+        __GOS_LAST_PWD=$PWD
+        eval "$(command gos hook-env bash)"
+    fi
+    return $__gos_status
+}
+case ";${PROMPT_COMMAND:-};" in
+    *";__gos_hook;"*) ;;
+    *) PROMPT_COMMAND="__gos_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`
+}
@@ -0,0 +1,172 @@
+package initcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+)
+
+func TestHookEnv(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+	t.Setenv("GOS_VERSION", "")
+
+	versions := filepath.Join(home, ".gos", "versions")
+	for _, version := range []string{"1.21.5", "1.22.3"} {
+		if err := os.MkdirAll(filepath.Join(versions, version, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(versions, "1.22.3"), filepath.Join(home, ".gos", "current")); err != nil {
+		t.Fatal(err)
+	}
+	b := backend.NewNative()
+
+	project := filepath.Join(home, "project")
+	other := filepath.Join(home, "other")
+	for _, dir := range []string{filepath.Join(project, "sub"), other} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(project, ".go-version"), []byte("1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin121 := filepath.Join(versions, "1.21.5", "bin")
+	sep := string(os.PathListSeparator)
+
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	t.Run("entering a project puts its toolchain first", func(t *testing.T) {
+		stderr := captureStderr(t)
+		got := HookEnv(shell.Bash, b, filepath.Join(project, "sub"), env(map[string]string{"PATH": "/usr/bin"}), false)
+
+		if !strings.Contains(got, shell.Bash.SetPath([]string{bin121, "/usr/bin"})) {
+			t.Errorf("expected PATH with %s first, got:\n%s", bin121, got)
+		}
+		if !strings.Contains(got, shell.Bash.Export(autoBinVar, bin121)) {
+			t.Errorf("expected %s to be recorded, got:\n%s", autoBinVar, got)
+		}
+		if warning := stderr(); warning != "" {
+			t.Errorf("unexpected warning: %s", warning)
+		}
+	})
+
+	t.Run("leaving a project restores PATH", func(t *testing.T) {
+		got := HookEnv(shell.Bash, b, other, env(map[string]string{
+			"PATH":     bin121 + sep + "/usr/bin",
+			autoBinVar: bin121,
+		}), false)
+
+		if !strings.Contains(got, shell.Bash.SetPath([]string{"/usr/bin"})) {
+			t.Errorf("expected the project toolchain removed from PATH, got:\n%s", got)
+		}
+		if !strings.Contains(got, shell.Bash.Unset(autoBinVar)) {
+			t.Errorf("expected %s to be cleared, got:\n%s", autoBinVar, got)
+		}
+	})
+
+	t.Run("outside projects nothing changes", func(t *testing.T) {
+		if got := HookEnv(shell.Bash, b, other, env(map[string]string{"PATH": "/usr/bin"}), false); got != "" {
+			t.Errorf("expected no output, got:\n%s", got)
+		}
+	})
+
+	t.Run("active version satisfying the project is kept", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(other, "go.mod"), []byte("module example.com/other\n\ngo 1.22\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(filepath.Join(other, "go.mod"))
+
+		if got := HookEnv(shell.Bash, b, other, env(map[string]string{"PATH": "/usr/bin"}), false); got != "" {
+			t.Errorf("expected no output, got:\n%s", got)
+		}
+	})
+
+	t.Run("missing version warns once", func(t *testing.T) {
+		missing := filepath.Join(home, "missing")
+		if err := os.MkdirAll(missing, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(missing, ".go-version"), []byte("1.20.1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		stderr := captureStderr(t)
+		got := HookEnv(shell.Fish, b, missing, env(map[string]string{"PATH": "/usr/bin"}), false)
+		warning := stderr()
+		if !strings.Contains(warning, "gos install 1.20.1") {
+			t.Errorf("expected an install hint, got %q", warning)
+		}
+		if strings.Count(warning, "\n") != 1 {
+			t.Errorf("expected a one-line warning, got %q", warning)
+		}
+
+		warned := strings.TrimPrefix(got, "set -gx "+autoWarnedVar+" ")
+		if warned == got {
+			t.Fatalf("expected the warning to be recorded, got:\n%s", got)
+		}
+
+		stderr = captureStderr(t)
+		HookEnv(shell.Fish, b, missing, env(map[string]string{
+			"PATH":        "/usr/bin",
+			autoWarnedVar: strings.Trim(strings.TrimSpace(warned), "'"),
+		}), false)
+		if warning := stderr(); warning != "" {
+			t.Errorf("expected no repeated warning, got %q", warning)
+		}
+	})
+}
+
+// captureStderr redirects os.Stderr to a file and returns a reader for it
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() {
+		os.Stderr = saved
+		f.Close()
+	})
+	return func() string {
+		data, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
+
+func TestHookScript(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+	b := backend.NewNative()
+
+	hooks := map[shell.Shell]string{
+		shell.Bash: "PROMPT_COMMAND",
+		shell.Zsh:  "add-zsh-hook chpwd __gos_hook",
+		shell.Fish: "--on-variable PWD",
+		shell.Pwsh: "function global:prompt",
+	}
+	for sh, hook := range hooks {
+		t.Run(string(sh), func(t *testing.T) {
+			if !strings.Contains(Script(sh, b, "", true), hook) {
+				t.Errorf("expected %q in the auto-switch script", hook)
+			}
+			if strings.Contains(Script(sh, b, "", false), hook) {
+				t.Errorf("expected no hook unless auto-switch is enabled")
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package initcmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/session"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/spf13/cobra"
+)
+
+// commandsChangingPath are the gos subcommands after which the shell's
+// command lookup cache has to be dropped
+var commandsChangingPath = []string{"install", "use", "remove", "latest", "default", "rehash", "setup", "reload"}
+
+// NewInitCmd creates the init command
+func NewInitCmd() *cobra.Command {
+	var noCompletion, autoSwitch bool
+
+	cmd := &cobra.Command{
+		Use:       "init <shell>",
+		Short:     "Print shell integration code to eval in your shell profile",
+		ValidArgs: shell.Names,
+		Long: `Print the code that integrates gos with your shell: PATH entries for the
+active backend and the gos shims, GOPATH, completions and a gos wrapper
+function. Add the single line for your shell to its startup file:
+
+  bash  (~/.bashrc)       eval "$(gos init bash)"
+  zsh   (~/.zshrc)        eval "$(gos init zsh)"
+  fish  (config.fish)     gos init fish | source
+  pwsh  ($PROFILE)        (& gos init pwsh) -join "` + "`" + `n" | Invoke-Expression
+
+With --auto-switch (or auto_switch=true in ~/.gos/config) the code also
+installs a hook that runs on every directory change: it resolves the project
+Go version (see 'gos current --explain') and puts that toolchain first in the
+current session's PATH only. If the version is not installed it prints a
+one-line warning, or installs it when auto_install=true is configured.`,
+		Example: `  gos init zsh                  # Show the zsh integration code
+  eval "$(gos init bash)"       # Load the integration into the current bash
+  eval "$(gos init zsh --auto-switch)"  # Also switch Go per project directory`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sh, err := shell.Parse(args[0])
+			if err != nil {
+				return &errs.UsageError{Err: err}
+			}
+
+			b, err := backend.Detect()
+			if err != nil {
+				return &errs.UsageError{Err: err}
+			}
+
+			var completion string
+			if !noCompletion {
+				completion, err = completionScript(cmd.Root(), sh)
+				if err != nil {
+					return fmt.Errorf("generating completions: %w", err)
+				}
+			}
+
+			fmt.Print(Script(sh, b, completion, autoSwitch || config.Enabled(AutoSwitchKey)))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noCompletion, "no-completion", false, "Leave out shell completions")
+	cmd.Flags().BoolVar(&autoSwitch, "auto-switch", false, "Switch Go per project when changing directories")
+
+	return cmd
+}
+
+// Script returns the integration code for a shell and backend, with the
+// auto-switch hook when autoSwitch is set
+func Script(sh shell.Shell, b backend.Backend, completion string, autoSwitch bool) string {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "# gos shell integration for %s (backend: %s), generated by 'gos init %s'\n", sh, b.Name(), sh)
+
+	// Shims first so per-directory versions win, then the backend's own bins
+	dirs := []string{common.GetShimsDir()}
+	for _, dir := range b.BinDirs() {
+		if dir != common.GetShimsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	switch sh {
+	case shell.Fish:
+		out.WriteString("set -q GOPATH; or set -gx GOPATH $HOME/go\n")
+		out.WriteString("for dir in $GOPATH/bin")
+		for i := len(dirs) - 1; i >= 0; i-- {
+			out.WriteString(" " + sh.Quote(dirs[i]))
+		}
+		out.WriteString("\n    contains -- $dir $PATH; or set -gx PATH $dir $PATH\nend\n")
+		out.WriteString(fishWrapper())
+
+	case shell.Pwsh:
+		out.WriteString("if (-not $env:GOPATH) { $env:GOPATH = Join-Path $HOME 'go' }\n")
+		out.WriteString("foreach ($dir in @((Join-Path $env:GOPATH 'bin')")
+		for i := len(dirs) - 1; i >= 0; i-- {
+			out.WriteString(", " + sh.Quote(dirs[i]))
+		}
+		out.WriteString(")) {\n")
+		out.WriteString("    if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains $dir) {\n")
+		out.WriteString("        $env:PATH = $dir + [IO.Path]::PathSeparator + $env:PATH\n    }\n}\n")
+		out.WriteString(pwshWrapper())
+
+	default:
+		out.WriteString("export GOPATH=\"${GOPATH:-$HOME/go}\"\n")
+		out.WriteString("for __gos_dir in \"$GOPATH/bin\"")
+		for i := len(dirs) - 1; i >= 0; i-- {
+			out.WriteString(" " + sh.Quote(dirs[i]))
+		}
+		out.WriteString("; do\n")
+		out.WriteString("    case \":$PATH:\" in *\":$__gos_dir:\"*) ;; *) PATH=\"$__gos_dir:$PATH\" ;; esac\n")
+		out.WriteString("done\nunset __gos_dir\nexport PATH\n")
+		out.WriteString(posixWrapper(sh))
+	}
+
+	if autoSwitch {
+		out.WriteString(hookScript(sh))
+	}
+
+	if completion != "" {
+		out.WriteString("\n# Completions\n")
+		out.WriteString(completion)
+		if !strings.HasSuffix(completion, "\n") {
+			out.WriteString("\n")
+		}
+	}
+
+	return out.String()
+}
+
+// posixWrapper returns the bash/zsh gos function. It evaluates the output of
+// `gos reload` and `gos use --shell` so they change the calling shell, and
+// after commands that change which go binary PATH resolves to it drops the
+// shell's command hash and re-runs the auto-switch hook when one is installed.
+func posixWrapper(sh shell.Shell) string {
+	return fmt.Sprintf(`
+gos() {
+    local __gos_status __gos_code
+    case "$1 $*" in
+        "reload "*|"use "*" --shell"*)
+            __gos_code=$(%s=%s command gos "$@")
+            __gos_status=$?
+            [ $__gos_status -eq 0 ] && eval "$__gos_code"
+            ;;
+        *)
+            command gos "$@"
+            __gos_status=$?
+            ;;
+    esac
+    case "$1" in
+        %s)
+            hash -r 2>/dev/null
+            if typeset -f __gos_hook >/dev/null 2>&1; then
+                __GOS_LAST_PWD=
+                __gos_hook
+            fi
+            ;;
+    esac
+    return $__gos_status
+}
+`, session.EvalVar, sh, strings.Join(commandsChangingPath, "|"))
+}
+
+// fishWrapper returns the fish gos function. It evaluates the output of
+// `gos reload` and `gos use --shell`; fish does not cache command paths, so
+// otherwise it only re-runs the auto-switch hook when one is installed.
+func fishWrapper() string {
+	return fmt.Sprintf(`
+function gos --wraps gos --description 'Go version manager'
+    set -l gos_status 0
+    if test "$argv[1]" = reload; or begin; test "$argv[1]" = use; and string match -q -- '--shell*' $argv; end
+        set -l gos_code (env %s=fish gos $argv)
+        set gos_status $status
+        test $gos_status -eq 0; and string join \n $gos_code | source
+    else
+        command gos $argv
+        set gos_status $status
+    end
+    if contains -- "$argv[1]" %s; and functions -q __gos_hook
+        __gos_hook
+    end
+    return $gos_status
+end
+`, session.EvalVar, strings.Join(commandsChangingPath, " "))
+}
+
+// pwshWrapper returns the PowerShell gos function, calling the real binary
+// found on PATH so the function does not recurse into itself. It evaluates
+// the output of `gos reload` and `gos use --shell`.
+func pwshWrapper() string {
+	return fmt.Sprintf(`
+function gos {
+    $gosBinary = Get-Command gos -CommandType Application | Select-Object -First 1
+    if ($args[0] -eq 'reload' -or ($args[0] -eq 'use' -and ($args -like '--shell*'))) {
+        $env:%[1]s = 'pwsh'
+        try { $gosCode = & $gosBinary.Source @args } finally { Remove-Item Env:%[1]s -ErrorAction SilentlyContinue }
+        if ($LASTEXITCODE -eq 0) { ($gosCode -join "`+"`"+`n") | Invoke-Expression }
+    } else {
+        & $gosBinary.Source @args
+    }
+    # Let the auto-switch hook run again at the next prompt
+    $global:__gosLastPwd = $null
+}
+`, session.EvalVar)
+}
+
+// completionScript generates cobra completions for a shell
+func completionScript(root *cobra.Command, sh shell.Shell) (string, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch sh {
+	case shell.Bash:
+		err = root.GenBashCompletionV2(&buf, true)
+	case shell.Zsh:
+		// compdef only exists once compinit ran; skip completions otherwise
+		buf.WriteString("if (( $+functions[compdef] )); then\n")
+		err = root.GenZshCompletion(&buf)
+		buf.WriteString("\nfi\n")
+	case shell.Fish:
+		err = root.GenFishCompletion(&buf, true)
+	case shell.Pwsh:
+		err = root.GenPowerShellCompletionWithDesc(&buf)
+	}
+	return buf.String(), err
+}
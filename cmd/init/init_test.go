@@ -0,0 +1,56 @@
+package initcmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+)
+
+func TestScript(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+	b := backend.NewNative()
+	shims := filepath.Join(home, ".gos", "shims")
+
+	for _, sh := range []shell.Shell{shell.Bash, shell.Zsh, shell.Fish, shell.Pwsh} {
+		t.Run(string(sh), func(t *testing.T) {
+			script := Script(sh, b, "", false)
+
+			if !strings.Contains(script, shims) {
+				t.Error("expected the shims directory in PATH")
+			}
+			if !strings.Contains(script, filepath.Join(home, ".gos", "current", "bin")) {
+				t.Error("expected the backend bin directory in PATH")
+			}
+			if !strings.Contains(script, "function gos") && !strings.Contains(script, "gos() {") {
+				t.Error("expected a gos wrapper function")
+			}
+		})
+	}
+
+	t.Run("bash output is valid and idempotent", func(t *testing.T) {
+		bash, err := exec.LookPath("bash")
+		if err != nil {
+			t.Skip("bash not available")
+		}
+
+		file := filepath.Join(t.TempDir(), "init.bash")
+		if err := os.WriteFile(file, []byte(Script(shell.Bash, b, "", false)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := exec.Command(bash, "-c", `PATH=/usr/bin; . "$1"; . "$1"; echo "$PATH"`, "bash", file).CombinedOutput()
+		if err != nil {
+			t.Fatalf("sourcing failed: %v\n%s", err, out)
+		}
+		if got := strings.Count(string(out), shims); got != 1 {
+			t.Errorf("expected shims once in PATH, found %d times: %s", got, out)
+		}
+	})
+}
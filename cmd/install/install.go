@@ -1,25 +1,56 @@
 package install
 
 import (
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"fmt"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/errs"
 	"github.com/spf13/cobra"
 )
 
 // NewInstallCmd creates the install command
 func NewInstallCmd() *cobra.Command {
+	var source SourceOptions
+
 	cmd := &cobra.Command{
 		Use:   "install [version]",
 		Short: "Install a specific Go version",
-		Long: `Install a specific Go version using gobrew.
-If no version is specified, installs the latest stable version.`,
-		Example: `  gos install 1.21.5    # Install Go 1.21.5
-  gos install latest     # Install latest version
-  gos install            # Install latest version (default)`,
+		Long: `Install a specific Go version through the active backend.
+By default gos downloads the official go.dev archive into ~/.gos/versions;
+set GOS_BACKEND (native, gobrew or g) to pick a different backend.
+If no version is specified, installs the latest stable version.
+
+Versions may be exact (1.21.5, go1.21.5, 1.21rc2), partial (1.21 picks the
+newest 1.21.x), ranges (~1.22, ^1.21, '>=1.21 <1.23') or the channels
+latest, stable and oldstable.
+
+With --from-source, gos builds a Go source checkout or source tarball with
+make.bash instead, bootstrapped from an installed version (the newest
+stable one, or --bootstrap), and installs it under --name. The build log
+streams to the terminal and is kept in ~/.gos/logs.`,
+		Example: `  gos install 1.21.5          # Install Go 1.21.5
+  gos install 1.21            # Install the newest 1.21.x
+  gos install '>=1.21 <1.23'  # Install the newest version in a range
+  gos install oldstable       # Install the previous stable line
+  gos install latest          # Install latest version
+  gos install                 # Install latest version (default)
+  gos install --from-source ~/src/go --name gotip
+                              # Build a Go checkout and install it as gotip`,
 		Args: cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			if !checkVersionManagerAvailable() {
-				return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if source.Source != "" {
+				if len(args) > 0 {
+					return &errs.UsageError{Err: fmt.Errorf("--from-source does not take a version; name the build with --name")}
+				}
+				return InstallFromSource(backend.NewNative(), source)
+			}
+			if source.Name != "" || source.Bootstrap != "" {
+				return &errs.UsageError{Err: fmt.Errorf("--name and --bootstrap only apply with --from-source")}
+			}
+
+			b, err := backend.Active()
+			if err != nil {
+				return err
 			}
 
 			version := "latest"
@@ -27,20 +58,13 @@ If no version is specified, installs the latest stable version.`,
 				version = args[0]
 			}
 
-			InstallVersion(version)
+			return InstallVersionWith(b, version)
 		},
 	}
 
-	return cmd
-}
-
-// checkVersionManagerAvailable verifies if gobrew is available
-func checkVersionManagerAvailable() bool {
-	if common.IsCommandAvailable("gobrew") {
-		return true
-	}
+	cmd.Flags().StringVar(&source.Source, "from-source", "", "Build Go from a source checkout or tarball")
+	cmd.Flags().StringVar(&source.Name, "name", "", "Name to install the source build under")
+	cmd.Flags().StringVar(&source.Bootstrap, "bootstrap", "", "Installed version to build with (default: newest stable)")
 
-	color.Red("❌ Error: gobrew is not installed.")
-	color.Yellow("💡 Run first: gos setup")
-	return false
+	return cmd
 }
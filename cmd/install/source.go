@@ -0,0 +1,97 @@
+package install
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+// SourceOptions describes a toolchain to build with gos install --from-source
+type SourceOptions struct {
+	Source    string // a Go source checkout or source tarball
+	Name      string // the name to install it under
+	Bootstrap string // the installed version to build with; the newest stable one when empty
+}
+
+// InstallFromSource builds a Go checkout or source tarball with make.bash,
+// using an installed version as GOROOT_BOOTSTRAP, and registers the result
+// as a native version under opts.Name
+func InstallFromSource(native *backend.Native, opts SourceOptions) error {
+	if opts.Name == "" {
+		return &errs.UsageError{Err: fmt.Errorf("--from-source needs --name, e.g. --name corp-dev")}
+	}
+	if err := toolchain.CheckName(opts.Name); err != nil {
+		return &errs.UsageError{Err: err}
+	}
+	source, err := filepath.Abs(opts.Source)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(source); err != nil {
+		return &errs.UsageError{Err: fmt.Errorf("source not found: %s", opts.Source)}
+	}
+
+	spec := opts.Bootstrap
+	if spec == "" {
+		spec = "stable"
+	}
+	bootstrap, err := backend.ResolveInstalled(native, spec)
+	if err != nil {
+		ui.Hint.Printf("💡 Building Go needs an installed Go to bootstrap from; install one with: gos install stable\n")
+		return err
+	}
+
+	logFile, err := createBuildLog(opts.Name)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	ui.Info.Printf("🛠️  Building %s from %s with Go %s...\n", opts.Name, source, bootstrap)
+	ui.Note.Printf("  Log: %s\n", logFile.Name())
+
+	stop := spinner("Preparing sources")
+	info, err := native.Installer.Build(toolchain.BuildOptions{
+		Name:             opts.Name,
+		Source:           source,
+		Bootstrap:        native.GOROOT(bootstrap),
+		BootstrapVersion: bootstrap,
+		Prepared:         stop,
+		Log:              io.MultiWriter(logFile, ui.Progress()),
+	})
+	stop()
+	if err != nil {
+		ui.Hint.Printf("💡 The full build output is in %s\n", logFile.Name())
+		return fmt.Errorf("building %s: %w", opts.Name, err)
+	}
+
+	ui.Success.Printf("✅ Built %s\n", opts.Name)
+	if info.GoVersion != "" {
+		ui.Note.Printf("  %s\n", info.GoVersion)
+	}
+	if info.Commit != "" {
+		ui.Note.Printf("  Commit:   %s\n", info.Commit)
+	}
+	ui.Note.Printf("  Location: %s\n", native.GOROOT(opts.Name))
+
+	shim.Refresh(native)
+	ui.Hint.Printf("🎯 Switch with: gos use %s\n", opts.Name)
+	return nil
+}
+
+// createBuildLog opens the log make.bash writes to, in ~/.gos/logs
+func createBuildLog(name string) (*os.File, error) {
+	dir := filepath.Join(common.GetGosHome(), "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+	return os.Create(filepath.Join(dir, "build-"+name+".log"))
+}
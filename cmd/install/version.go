@@ -1,25 +1,122 @@
 package install
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
+	"io"
+	"sync"
 	"time"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/schollz/progressbar/v3"
 )
 
-// InstallVersion installs a specific Go version
-func InstallVersion(version string) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
+// InstallVersion installs a specific Go version with the active backend
+func InstallVersion(version string) error {
+	b, err := backend.Active()
+	if err != nil {
+		return err
+	}
+	return InstallVersionWith(b, version)
+}
+
+// InstallVersionWith installs a specific Go version with the given backend.
+// version may be any spec understood by goversion, e.g. 1.21, ~1.22 or stable.
+func InstallVersionWith(b backend.Backend, version string) error {
+	resolved, err := backend.ResolveRemote(b, version)
+	if err != nil {
+		ui.Hint.Printf("💡 See available versions with: gos list --remote\n")
+		return err
+	}
+	if resolved != goversion.Normalize(version) && resolved != goversion.Latest {
+		ui.Info.Printf("🔎 %s resolved to Go %s\n", version, resolved)
+	}
+	version = resolved
+
+	if native, ok := b.(*backend.Native); ok {
+		err = installNative(native, version)
+	} else {
+		err = installWithTool(b, version)
+	}
+	if err != nil {
+		return err
+	}
+
+	shim.Refresh(b)
+	return nil
+}
+
+// installNative downloads and extracts the official archive into the gos versions directory
+func installNative(native *backend.Native, version string) error {
+	installer := native.Installer
+
+	if version == "latest" {
+		latest, err := installer.LatestVersion()
+		if err != nil {
+			return fmt.Errorf("determining the latest Go version: %w", err)
+		}
+		version = latest
+	}
+	version = toolchain.NormalizeVersion(version)
+
+	if installer.IsInstalled(version) {
+		ui.Hint.Printf("ℹ️  Go %s is already installed\n", version)
+		return nil
+	}
 
-	blue.Printf("📦 Installing Go %s...\n", version)
+	ui.Info.Printf("📦 Installing Go %s...\n", version)
+	ui.Note.Printf("  Downloading %s\n", installer.ArchiveURL(version))
 
-	// Create progress bar for installation
+	installer.Progress = func(total int64, r io.Reader) io.Reader {
+		if ui.Current().Quiet {
+			return r
+		}
+		bar := progressbar.DefaultBytes(total, fmt.Sprintf("Downloading Go %s", version))
+		reader := progressbar.NewReader(r, bar)
+		return &reader
+	}
+
+	if err := native.Install(version); err != nil {
+		var checksumErr *toolchain.ChecksumError
+		if errors.As(err, &checksumErr) {
+			ui.Error.Printf("❌ Refusing to install Go %s: the download failed SHA-256 verification\n", version)
+			ui.Error.Printf("  File:     %s\n", checksumErr.File)
+			ui.Error.Printf("  Expected: %s\n", checksumErr.Expected)
+			ui.Error.Printf("  Actual:   %s\n", checksumErr.Actual)
+		}
+		return fmt.Errorf("installing Go %s: %w", version, err)
+	}
+
+	ui.Success.Printf("✅ Go %s installed successfully (SHA-256 verified)\n", version)
+	ui.Note.Printf("  Location: %s\n", native.GOROOT(version))
+	return nil
+}
+
+// installWithTool installs a version through an external version manager
+func installWithTool(b backend.Backend, version string) error {
+	ui.Info.Printf("📦 Installing Go %s with %s...\n", version, b.Name())
+
+	stop := spinner(fmt.Sprintf("Installing Go %s", version))
+	err := b.Install(version)
+	stop()
+	if err != nil {
+		return fmt.Errorf("installing Go %s with %s: %w", version, b.Name(), err)
+	}
+
+	ui.Success.Printf("✅ Go %s installed successfully\n", version)
+	return nil
+}
+
+// spinner shows an animated progress bar until stop is called; stop may be
+// called more than once
+func spinner(description string) (stop func()) {
 	bar := progressbar.NewOptions(-1,
-		progressbar.OptionSetDescription(fmt.Sprintf("Installing Go %s", version)),
+		progressbar.OptionSetDescription(description),
+		progressbar.OptionSetWriter(ui.Progress()),
 		progressbar.OptionSetPredictTime(false),
 		progressbar.OptionSpinnerType(14),
 		progressbar.OptionShowCount(),
@@ -28,7 +125,6 @@ func InstallVersion(version string) {
 		}),
 	)
 
-	// Start progress bar in goroutine
 	done := make(chan bool)
 	go func() {
 		for {
@@ -42,16 +138,12 @@ func InstallVersion(version string) {
 		}
 	}()
 
-	cmd := exec.Command("gobrew", "install", version)
-
-	if err := cmd.Run(); err != nil {
-		done <- true
-		bar.Finish()
-		red.Printf("❌ Error installing Go %s\n", version)
-		return
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			done <- true
+			bar.Finish()
+			fmt.Fprintln(ui.Progress())
+		})
 	}
-
-	done <- true
-	bar.Finish()
-	green.Printf("✅ Go %s installed successfully\n", version)
 }
@@ -1,13 +1,13 @@
 package latest
 
 import (
-	"os"
-	"os/exec"
-	"time"
+	"fmt"
 
+	"github.com/cristobalcontreras/gos/cmd/backend"
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
-	"github.com/schollz/progressbar/v3"
+	"github.com/cristobalcontreras/gos/cmd/install"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -17,108 +17,68 @@ func NewLatestCmd() *cobra.Command {
 		Use:   "latest",
 		Short: "Install and use the latest Go version",
 		Long:  `Install the latest stable Go version and automatically switch to it.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			if !common.CheckVersionManagerAvailable() {
-				return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := backend.Active()
+			if err != nil {
+				return err
 			}
-			installLatest()
+			return installLatest(b)
 		},
 	}
 }
 
 // installLatest installs and switches to the latest Go version
-func installLatest() {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-
-	blue.Println("🚀 Installing latest Go version...")
+func installLatest(b backend.Backend) error {
+	ui.Info.Println("🚀 Installing latest Go version...")
 
 	// Install latest version
-	if !executeInstallLatest() {
-		return
+	version, err := executeInstallLatest(b)
+	if err != nil {
+		return err
 	}
 
-	green.Println("✅ Latest version installed")
+	ui.Success.Println("✅ Latest version installed")
 
 	// Switch to latest version
-	blue.Println("� Switching to latest version...")
-	switchToLatest()
+	ui.Info.Println("🔄 Switching to latest version...")
+	if err := switchToLatest(b, version); err != nil {
+		return err
+	}
 
 	// Show current version
-	showCurrentVersion(blue)
+	showCurrentVersion()
+	return nil
 }
 
-// executeInstallLatest performs the installation with progress bar
-func executeInstallLatest() bool {
-	red := color.New(color.FgRed)
-
-	// Create progress bar for installation
-	bar := progressbar.NewOptions(-1,
-		progressbar.OptionSetDescription("Installing latest Go"),
-		progressbar.OptionSetPredictTime(false),
-		progressbar.OptionSpinnerType(14),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer: "=", SaucerHead: ">", SaucerPadding: " ", BarStart: "[", BarEnd: "]",
-		}),
-	)
-
-	// Start progress bar in goroutine
-	done := make(chan bool)
-	go func() {
-		for {
-			select {
-			case <-done:
-				return
-			default:
-				bar.Add(1)
-				time.Sleep(300 * time.Millisecond)
-			}
-		}
-	}()
-
-	installCmd := getInstallCommand()
-	if installCmd == nil {
-		done <- true
-		bar.Finish()
-		red.Println("❌ No version manager available")
-		return false
+// executeInstallLatest resolves and installs the latest version, returning it
+func executeInstallLatest(b backend.Backend) (string, error) {
+	native, ok := b.(*backend.Native)
+	if !ok {
+		// External managers resolve "latest" themselves
+		return "latest", install.InstallVersionWith(b, "latest")
 	}
 
-	if err := installCmd.Run(); err != nil {
-		done <- true
-		bar.Finish()
-		red.Println("❌ Error installing latest version")
-		return false
+	version, err := native.Installer.LatestVersion()
+	if err != nil {
+		return "", fmt.Errorf("determining the latest Go version: %w", err)
 	}
 
-	done <- true
-	bar.Finish()
-	return true
+	return version, install.InstallVersionWith(b, version)
 }
 
-// getInstallCommand returns the appropriate install command
-func getInstallCommand() *exec.Cmd {
-	if common.IsCommandAvailable("gobrew") {
-		return exec.Command("gobrew", "install", "latest")
+// switchToLatest switches to the freshly installed version
+func switchToLatest(b backend.Backend, version string) error {
+	if err := b.Use(version); err != nil {
+		return fmt.Errorf("switching to Go %s: %w", version, err)
 	}
-
+	common.UpdatePathForVersionManagerClean(b.BinDirs())
 	return nil
 }
 
-// switchToLatest switches to the latest installed version
-func switchToLatest() {
-	if common.IsCommandAvailable("gobrew") {
-		useCmd := exec.Command("gobrew", "use", "latest")
-		useCmd.Run()
-	}
-}
-
 // showCurrentVersion displays the current Go version
-func showCurrentVersion(blue *color.Color) {
-	if goCmd := exec.Command("go", "version"); goCmd.Run() == nil {
-		blue.Print("📋 Current version: ")
-		goCmd.Stdout = os.Stdout
-		goCmd.Run()
+func showCurrentVersion() {
+	if output, err := runner.Output("go", "version"); err == nil {
+		ui.Info.Print("📋 Current version: ")
+		ui.Note.Print(output)
 	}
 }
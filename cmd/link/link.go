@@ -0,0 +1,107 @@
+package link
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewLinkCmd creates the link command
+func NewLinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "link <name> <goroot>",
+		Short: "Register a custom or locally built Go toolchain under a name",
+		Long: `Register an existing GOROOT, such as a patched toolchain or a gotip
+checkout, as a version of the native backend. The files stay where they
+are; gos links to them from ~/.gos/versions.
+
+The name works wherever a version does: gos use, exec, project and default,
+.go-version files and GOS_VERSION. It must not read as a Go version, so
+that it never shadows a release.`,
+		Example: `  gos link corp-1.22-fips /opt/go-fips   # Register a patched toolchain
+  gos link gotip ~/src/go               # Register a gotip checkout
+  gos use corp-1.22-fips                # Switch to it
+  gos unlink corp-1.22-fips             # Forget it, keeping the files`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return LinkToolchain(backend.NewNative(), args[0], args[1])
+		},
+	}
+}
+
+// NewUnlinkCmd creates the unlink command
+func NewUnlinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unlink <name>",
+		Short:   "Remove a toolchain registered with gos link, keeping its files",
+		Example: `  gos unlink corp-1.22-fips`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return UnlinkToolchain(backend.NewNative(), args[0])
+		},
+	}
+}
+
+// LinkToolchain checks that goroot holds a working go and registers it as name
+func LinkToolchain(native *backend.Native, name, goroot string) error {
+	if err := toolchain.CheckName(name); err != nil {
+		return &errs.UsageError{Err: err}
+	}
+	goroot, err := filepath.Abs(goroot)
+	if err != nil {
+		return err
+	}
+
+	version, err := GoVersion(goroot)
+	if err != nil {
+		return &errs.UsageError{Err: fmt.Errorf("%s is not a working GOROOT: %w", goroot, err)}
+	}
+
+	if err := native.Installer.Link(name, goroot); err != nil {
+		return err
+	}
+	ui.Success.Printf("✅ Linked %s → %s\n", name, goroot)
+	ui.Note.Printf("  %s\n", version)
+
+	shim.Refresh(native)
+	ui.Hint.Printf("🎯 Switch with: gos use %s\n", name)
+	if b := config.Get("backend"); b != "" && b != "native" && b != "gos" {
+		ui.Hint.Printf("💡 The %s backend is selected; linked toolchains belong to the native backend\n", b)
+	}
+	return nil
+}
+
+// UnlinkToolchain removes a linked toolchain's registration
+func UnlinkToolchain(native *backend.Native, name string) error {
+	goroot, _ := native.Installer.LinkTarget(name)
+	if err := native.Installer.Unlink(name); err != nil {
+		return err
+	}
+	ui.Success.Printf("✅ Unlinked %s; %s was left in place\n", name, goroot)
+
+	shim.Refresh(native)
+	return nil
+}
+
+// GoVersion runs the go of a GOROOT and returns its `go version` output
+func GoVersion(goroot string) (string, error) {
+	goBinary := filepath.Join(goroot, "bin", "go")
+	if !fsys.Exists(goBinary) && fsys.Exists(goBinary+".exe") {
+		goBinary += ".exe"
+	}
+	output, err := runner.Output(goBinary, "version")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
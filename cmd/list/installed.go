@@ -2,81 +2,148 @@ package list
 
 import (
 	"fmt"
-	"os/exec"
-	"strings"
 
+	"github.com/cristobalcontreras/gos/cmd/backend"
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/link"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// ListVersions lists all installed Go versions
-func ListVersions() {
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
-
-	blue.Println("📋 Installed Go versions:")
-
-	// Use gobrew to list versions
-	if _, err := exec.LookPath("gobrew"); err == nil {
-		listVersionsWithGobrew()
-	} else {
-		yellow.Println("gobrew not detected.")
-		fmt.Println("")
-		yellow.Println("💡 To install gobrew:")
-		fmt.Println("   gos setup               # Install gobrew")
-		fmt.Println("")
-		yellow.Println("💡 If Go is installed manually, check with:")
-		fmt.Println("   go version              # Show current Go version")
-	}
+// Installed is the result of gos list
+type Installed struct {
+	Backend  string             `json:"backend"`
+	Current  string             `json:"current,omitempty"`
+	Versions []InstalledVersion `json:"versions"`
+	// System is a go binary in PATH that no backend manages, reported when
+	// the backend has no versions installed
+	System *common.GoInfo `json:"system,omitempty"`
 }
 
-// listVersionsWithGobrew lists versions using gobrew
-func listVersionsWithGobrew() {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
+// InstalledVersion is one installed Go version
+type InstalledVersion struct {
+	Version string `json:"version"`
+	Current bool   `json:"current"`
+	// Linked is the GOROOT of a toolchain registered with gos link
+	Linked string `json:"linked,omitempty"`
+	// GoVersion is what the go version of a toolchain built from source prints
+	GoVersion string `json:"go_version,omitempty"`
+	// Build tells how a toolchain built with gos install --from-source was made
+	Build *toolchain.BuildInfo `json:"build,omitempty"`
+}
 
-	fmt.Println("  Using gobrew...")
+// GetInstalled collects the versions installed through the backend
+func GetInstalled(b backend.Backend) (Installed, error) {
+	result := Installed{Backend: b.Name(), Versions: []InstalledVersion{}}
 
-	cmd := exec.Command("gobrew", "ls")
-	output, err := cmd.Output()
+	versions, err := b.ListInstalled()
 	if err != nil {
-		yellow.Println("  No versions installed via gobrew")
-		return
+		return result, fmt.Errorf("could not list versions via %s: %w", b.Name(), err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			if strings.Contains(line, "*") || strings.Contains(line, "current") {
-				green.Printf("  ✅ %s (current)\n", strings.ReplaceAll(line, "*", ""))
-			} else {
-				fmt.Printf("     %s\n", line)
+	result.Current, _ = b.Current()
+	native, _ := b.(*backend.Native)
+	for _, version := range versions {
+		installed := InstalledVersion{Version: version, Current: version == result.Current}
+		if native != nil {
+			installed.Linked, _ = native.Installer.LinkTarget(version)
+			if build, ok := native.Installer.ReadBuildInfo(version); ok {
+				installed.Build = &build
+				installed.GoVersion = build.GoVersion
 			}
 		}
+		result.Versions = append(result.Versions, installed)
 	}
+
+	if len(result.Versions) == 0 {
+		if info := common.CurrentGo(); info.Available {
+			result.System = &info
+		}
+	}
+	return result, nil
 }
 
-// listVersionsManually checks for manual Go installations
-func listVersionsManually() bool {
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
+// ListVersions lists all installed Go versions
+func ListVersions() error {
+	b, err := backend.Active()
+	if err != nil {
+		return err
+	}
+
+	result, err := GetInstalled(b)
+	if err != nil {
+		return err
+	}
+	if output.Structured() {
+		return output.Print(result)
+	}
+
+	ui.Title.Println("📋 Installed Go versions:")
+	if !listVersionsWithBackend(result) && !listVersionsManually(result.System) {
+		ui.Out.Println("  No Go versions installed")
+		ui.Out.Println("")
+		ui.Hint.Println("💡 To install one:")
+		ui.Hint.Println("   gos install latest      # Install latest Go version")
+	}
+	return nil
+}
 
-	// Use common helper to get system Go info
-	if version, goroot, found := common.GetSystemGoInfo(); found {
-		green.Printf("  ✅ %s (system installation)\n", version)
+// listVersionsWithBackend lists versions installed through the backend
+func listVersionsWithBackend(result Installed) bool {
+	ui.Note.Printf("  Using %s...\n", result.Backend)
+	if len(result.Versions) == 0 {
+		return false
+	}
 
-		if goroot != "" {
-			fmt.Printf("     Location: %s\n", goroot)
+	for _, version := range result.Versions {
+		if version.Current {
+			ui.Success.Printf("  ✅ %s (current)\n", version.Version)
+		} else {
+			ui.Out.Printf("     %s\n", version.Version)
 		}
+		// Only the text listing runs the linked toolchains, to show what they are
+		if version.Linked != "" {
+			goVersion, err := link.GoVersion(version.Linked)
+			if err != nil {
+				goVersion = "go version failed"
+			}
+			ui.Note.Printf("       🔗 %s (%s)\n", version.Linked, goVersion)
+		}
+		if build := version.Build; build != nil {
+			ui.Note.Printf("       🛠️  built from %s%s with Go %s on %s\n",
+				build.Source, shortCommit(build.Commit), build.Bootstrap, build.BuiltAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+	return true
+}
 
-		fmt.Println("")
-		yellow.Println("💡 This appears to be a manual Go installation.")
-		yellow.Println("   To manage multiple versions, consider installing a version manager:")
-		fmt.Println("   gos setup               # Install version manager")
+// shortCommit renders a commit as " @ abcdef123456", or nothing when unknown
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if commit == "" {
+		return ""
+	}
+	return " @ " + commit
+}
 
-		return true
+// listVersionsManually shows a manual Go installation found in PATH
+func listVersionsManually(system *common.GoInfo) bool {
+	if system == nil {
+		return false
 	}
 
-	return false
+	ui.Success.Printf("  ✅ go%s %s (system installation)\n", system.Version, system.Platform)
+	if system.GOROOT != "" {
+		ui.Out.Printf("     Location: %s\n", system.GOROOT)
+	}
+
+	ui.Out.Println("")
+	ui.Hint.Println("💡 This appears to be a manual Go installation.")
+	ui.Hint.Println("   To manage multiple versions with gos:")
+	ui.Hint.Println("   gos install latest      # Install a managed Go version")
+
+	return true
 }
@@ -0,0 +1,74 @@
+package list
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+func TestGetInstalled(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+
+	versions := filepath.Join(home, ".gos", "versions")
+	for _, version := range []string{"1.21.5", "1.22.3"} {
+		if err := os.MkdirAll(filepath.Join(versions, version, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(versions, "1.22.3"), filepath.Join(home, ".gos", "current")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := GetInstalled(backend.NewNative())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Backend != "native" || result.Current != "1.22.3" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Versions) != 2 {
+		t.Fatalf("expected 2 versions, got %+v", result.Versions)
+	}
+	for _, version := range result.Versions {
+		if version.Current != (version.Version == "1.22.3") {
+			t.Errorf("wrong current flag: %+v", version)
+		}
+	}
+	if result.System != nil {
+		t.Errorf("expected no system Go when versions are installed, got %+v", result.System)
+	}
+}
+
+func TestGetInstalledLinked(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+	fake := runner.NewFake()
+	t.Cleanup(runner.Replace(fake))
+
+	goroot := filepath.Join(t.TempDir(), "go-fips")
+	if err := os.MkdirAll(filepath.Join(goroot, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	native := backend.NewNative()
+	if err := native.Installer.Link("corp-1.22-fips", goroot); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := GetInstalled(native)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := InstalledVersion{Version: "corp-1.22-fips", Linked: goroot}
+	if len(result.Versions) != 1 || result.Versions[0] != want {
+		t.Errorf("Versions = %+v, want [%+v]", result.Versions, want)
+	}
+	if calls := fake.Lines(); len(calls) != 0 {
+		t.Errorf("GetInstalled() ran %v", calls)
+	}
+}
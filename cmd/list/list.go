@@ -1,38 +1,30 @@
 package list
 
 import (
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/output"
 	"github.com/spf13/cobra"
 )
 
 // NewListCmd creates the list command
 func NewListCmd() *cobra.Command {
-	cmd := &cobra.Command{
+	cmd := output.Enable(&cobra.Command{
 		Use:   "list",
 		Short: "List installed Go versions",
-		Long:  `List all Go versions that have been installed via gobrew.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Long:  `List all Go versions that have been installed through the active backend.`,
+		Example: `  gos list                 # List installed versions
+  gos list --remote        # List the newest versions available for install
+  gos list -r -o json      # The same as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			remote, _ := cmd.Flags().GetBool("remote")
+			all, _ := cmd.Flags().GetBool("all")
 			if remote {
-				ListRemoteVersions()
-			} else {
-				ListVersions()
+				return ListRemoteVersions(all)
 			}
+			return ListVersions()
 		},
-	}
+	})
 
 	cmd.Flags().BoolP("remote", "r", false, "List available remote versions")
+	cmd.Flags().BoolP("all", "a", false, "With --remote, list every version instead of the newest ones")
 	return cmd
 }
-
-// checkVersionManagerAvailable verifies if gobrew is available
-func checkVersionManagerAvailable() bool {
-	if common.IsCommandAvailable("gobrew") {
-		return true
-	}
-
-	color.Red("❌ Error: gobrew is not installed.")
-	color.Yellow("💡 Run first: gos setup")
-	return false
-}
@@ -2,111 +2,119 @@ package list
 
 import (
 	"fmt"
-	"os/exec"
-	"strings"
 	"time"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/schollz/progressbar/v3"
 )
 
-// ListRemoteVersions lists available remote Go versions
-func ListRemoteVersions() {
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
+// remoteLimit caps how many remote versions are shown unless --all is given
+const remoteLimit = 20
 
-	blue.Println("🌐 Available versions:")
+// Remote is the result of gos list --remote
+type Remote struct {
+	Backend  string          `json:"backend"`
+	Platform string          `json:"platform,omitempty"` // set when versions are filtered for this platform
+	Versions []RemoteVersion `json:"versions"`
+	Total    int             `json:"total"` // versions available; more than listed unless --all is given
+}
 
-	// Try different version managers based on availability
-	if common.IsCommandAvailable("gobrew") {
-		listRemoteVersionsWithGobrew()
-	} else if common.IsCommandAvailable("g") {
-		listRemoteVersionsWithG()
-	} else {
-		yellow.Println("No version manager detected.")
-		fmt.Println("")
-		yellow.Println("💡 To install a version manager and browse remote versions:")
-		fmt.Println("   gos setup               # Install version manager")
-		fmt.Println("")
-		yellow.Println("💡 You can also check manually at:")
-		fmt.Println("   https://golang.org/dl/")
-	}
+// RemoteVersion is one version available for install
+type RemoteVersion struct {
+	Version   string `json:"version"`
+	Stable    bool   `json:"stable"`
+	Installed bool   `json:"installed"`
 }
 
-// listRemoteVersionsWithGobrew lists remote versions using gobrew
-func listRemoteVersionsWithGobrew() {
-	yellow := color.New(color.FgYellow)
+// ListRemoteVersions lists available remote Go versions
+func ListRemoteVersions(all bool) error {
+	b, err := backend.Active()
+	if err != nil {
+		return err
+	}
 
-	// Create progress bar for fetching remote versions
-	bar := progressbar.NewOptions(-1,
-		progressbar.OptionSetDescription("Fetching remote versions with gobrew"),
-		progressbar.OptionSetPredictTime(false),
-		progressbar.OptionSpinnerType(14),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer: "=", SaucerHead: ">", SaucerPadding: " ", BarStart: "[", BarEnd: "]",
-		}),
-	)
-	
-	// Start progress bar in goroutine
-	done := make(chan bool)
-	go func() {
-		for {
-			select {
-			case <-done:
-				return
-			default:
-				bar.Add(1)
-				time.Sleep(100 * time.Millisecond)
-			}
+	result, err := GetRemote(b, all)
+	if err != nil {
+		if !output.Structured() {
+			showManualCheckHint()
 		}
-	}()
+		return err
+	}
+	if output.Structured() {
+		return output.Print(result)
+	}
+
+	ui.Title.Println("🌐 Available versions:")
+	showRemote(result)
+	return nil
+}
 
-	cmd := exec.Command("gobrew", "ls-remote")
-	output, err := cmd.Output()
+// GetRemote collects the versions available for install, newest first,
+// limited to remoteLimit unless all is set
+func GetRemote(b backend.Backend, all bool) (Remote, error) {
+	var result Remote
+	var err error
+	if native, ok := b.(*backend.Native); ok {
+		result, err = remoteReleases(native)
+	} else {
+		result, err = remoteVersionsWithBackend(b)
+	}
 	if err != nil {
-		done <- true
-		bar.Finish()
-		yellow.Println("  Could not get remote versions via gobrew")
-		return
+		return result, err
 	}
 
-	done <- true
-	bar.Finish()
+	result.Total = len(result.Versions)
+	if !all && len(result.Versions) > remoteLimit {
+		result.Versions = result.Versions[:remoteLimit]
+	}
+	return result, nil
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	fmt.Println("  Available versions from gobrew:")
-	
-	count := 0
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "Available") {
-			fmt.Printf("     %s\n", line)
-			count++
-			if count > 20 { // Limit output to first 20 versions
-				fmt.Printf("     ... and %d more versions\n", len(lines)-count-1)
-				fmt.Println("")
-				fmt.Println("💡 Run 'gobrew ls-remote' to see all available versions")
-				break
-			}
+// remoteReleases lists versions from the release index with their stability and install state
+func remoteReleases(native *backend.Native) (Remote, error) {
+	installer := native.Installer
+	result := Remote{
+		Backend:  native.Name(),
+		Platform: installer.GOOS + "/" + installer.GOARCH,
+		Versions: []RemoteVersion{},
+	}
+
+	index, err := installer.Index.Releases()
+	if err != nil {
+		return result, fmt.Errorf("could not get the release index: %w", err)
+	}
+
+	for _, release := range index {
+		if _, ok := release.Archive(installer.GOOS, installer.GOARCH); !ok {
+			continue
 		}
+		result.Versions = append(result.Versions, RemoteVersion{
+			Version:   release.Number(),
+			Stable:    release.Stable,
+			Installed: installer.IsInstalled(release.Number()),
+		})
 	}
+	return result, nil
 }
 
-// listRemoteVersionsWithG lists remote versions using g
-func listRemoteVersionsWithG() {
-	yellow := color.New(color.FgYellow)
+// remoteVersionsWithBackend lists remote versions using the backend
+func remoteVersionsWithBackend(b backend.Backend) (Remote, error) {
+	result := Remote{Backend: b.Name(), Versions: []RemoteVersion{}}
 
 	// Create progress bar for fetching remote versions
 	bar := progressbar.NewOptions(-1,
-		progressbar.OptionSetDescription("Fetching remote versions with g"),
+		progressbar.OptionSetDescription(fmt.Sprintf("Fetching remote versions with %s", b.Name())),
 		progressbar.OptionSetPredictTime(false),
+		progressbar.OptionSetWriter(ui.Progress()),
 		progressbar.OptionSpinnerType(14),
 		progressbar.OptionSetTheme(progressbar.Theme{
 			Saucer: "=", SaucerHead: ">", SaucerPadding: " ", BarStart: "[", BarEnd: "]",
 		}),
 	)
-	
+
 	// Start progress bar in goroutine
 	done := make(chan bool)
 	go func() {
@@ -121,33 +129,61 @@ func listRemoteVersionsWithG() {
 		}
 	}()
 
-	cmd := exec.Command("g", "list-all")
-	output, err := cmd.Output()
+	versions, err := b.ListRemote()
+	done <- true
+	bar.Finish()
+	fmt.Fprintln(ui.Progress())
+
 	if err != nil {
-		done <- true
-		bar.Finish()
-		yellow.Println("  Could not get remote versions via g")
-		return
+		return result, fmt.Errorf("could not get remote versions via %s: %w", b.Name(), err)
 	}
 
-	done <- true
-	bar.Finish()
+	installed := map[string]bool{}
+	if list, err := b.ListInstalled(); err == nil {
+		for _, version := range list {
+			installed[version] = true
+		}
+	}
+	for _, version := range versions {
+		parsed, err := goversion.Parse(version)
+		result.Versions = append(result.Versions, RemoteVersion{
+			Version:   version,
+			Stable:    err == nil && parsed.Stable(),
+			Installed: installed[version],
+		})
+	}
+	return result, nil
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	fmt.Println("  Available versions from g:")
-	
-	count := 0
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "Available") {
-			fmt.Printf("     %s\n", line)
-			count++
-			if count > 20 { // Limit output to first 20 versions
-				fmt.Printf("     ... and %d more versions\n", len(lines)-count-1)
-				fmt.Println("")
-				fmt.Println("💡 Run 'g list-all' to see all available versions")
-				break
-			}
+// showRemote prints the available versions, marking installed and unstable ones
+func showRemote(result Remote) {
+	if result.Platform != "" {
+		ui.Out.Printf("  Available versions for %s:\n", result.Platform)
+	} else {
+		ui.Out.Printf("  Available versions from %s:\n", result.Backend)
+	}
+
+	for _, version := range result.Versions {
+		label := version.Version
+		if !version.Stable {
+			label += " (unstable)"
+		}
+		if version.Installed {
+			ui.Success.Printf("  ✅ %s (installed)\n", label)
+		} else {
+			ui.Out.Printf("     %s\n", label)
 		}
 	}
+
+	if hidden := result.Total - len(result.Versions); hidden > 0 {
+		ui.Out.Printf("     ... and %d more versions\n", hidden)
+		ui.Out.Println("")
+		ui.Hint.Println("💡 Run 'gos list --remote --all' to see all available versions")
+	}
+}
+
+// showManualCheckHint points to the download page when the remote list is unavailable
+func showManualCheckHint() {
+	ui.Hint.Println("💡 You can also check manually at:")
+	ui.Hint.Println("   https://go.dev/dl/")
 }
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Format is a rendering of a command's result
+type Format string
+
+// Supported formats
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// Names lists the supported formats
+var Names = []string{string(Text), string(JSON), string(YAML)}
+
+// annotation marks commands that render their result with Print
+const annotation = "gos/output"
+
+// current is the format chosen with the root --output flag
+var current = Text
+
+// stdout is where Print writes the result
+var stdout = os.Stdout
+
+// Parse returns the format with the given name
+func Parse(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case Text, "":
+		return Text, nil
+	case JSON:
+		return JSON, nil
+	case YAML, "yml":
+		return YAML, nil
+	}
+	return "", fmt.Errorf("unsupported output format %q (supported: %s)", name, strings.Join(Names, ", "))
+}
+
+// Set selects the format for Print. For a structured format everything else
+// gos prints (messages, progress bars) goes to stderr, so stdout carries only
+// the result.
+func Set(f Format) {
+	current = f
+	if f != Text {
+		stdout = os.Stdout
+		os.Stdout, color.Output = os.Stderr, os.Stderr
+	}
+}
+
+// Current returns the selected format
+func Current() Format { return current }
+
+// Structured reports whether a machine-readable format was selected, in which
+// case commands print their result with Print instead of decorated text
+func Structured() bool { return current != Text }
+
+// Enable marks cmd as able to render its result in every format
+func Enable(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[annotation] = "true"
+	return cmd
+}
+
+// Supported reports whether cmd was marked with Enable
+func Supported(cmd *cobra.Command) bool {
+	return cmd.Annotations[annotation] == "true"
+}
+
+// Print writes v to stdout in the selected structured format
+func Print(v any) error {
+	return Write(stdout, current, v)
+}
+
+// Write writes v to w in format f. Field names come from the json struct tags
+// of the result models, so both formats share them.
+func Write(w io.Writer, f Format, v any) error {
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	case YAML:
+		out, err := MarshalYAML(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	return fmt.Errorf("format %q has no structured rendering", f)
+}
@@ -0,0 +1,145 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type result struct {
+	Origin
+	Backend  string    `json:"backend"`
+	Current  string    `json:"current,omitempty"`
+	Versions []version `json:"versions"`
+	Tags     []string  `json:"tags"`
+	Source   *Origin   `json:"source"`
+	Counts   map[string]int
+	Checked  time.Time `json:"checked,omitzero"`
+}
+
+type version struct {
+	Version string `json:"version"`
+	Current bool   `json:"current"`
+}
+
+type Origin struct {
+	Path string `json:"path"`
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]Format{"": Text, "text": Text, "JSON": JSON, "yaml": YAML, "yml": YAML}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got, err := Parse(name); err != nil || got != want {
+				t.Errorf("Parse(%q) = %q, %v; want %q", name, got, err, want)
+			}
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := Parse("xml"); err == nil {
+			t.Error("expected an error for an unsupported format")
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	v := result{
+		Origin:  Origin{Path: "/home/me/.gos"},
+		Backend: "native",
+		Versions: []version{
+			{Version: "1.22", Current: true},
+			{Version: "1.21.5"},
+		},
+		Counts: map[string]int{"b": 2, "a": 1},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, JSON, v); err != nil {
+			t.Fatal(err)
+		}
+		var decoded result
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if decoded.Versions[0].Version != "1.22" || !decoded.Versions[0].Current {
+			t.Errorf("unexpected round trip: %+v", decoded)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, YAML, v); err != nil {
+			t.Fatal(err)
+		}
+		want := `path: /home/me/.gos
+backend: native
+versions:
+- version: "1.22"
+  current: true
+- version: 1.21.5
+  current: false
+tags: null
+source: null
+Counts:
+  a: 1
+  b: 2
+`
+		if buf.String() != want {
+			t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+		}
+	})
+
+	t.Run("yaml honours omitzero", func(t *testing.T) {
+		checked := v
+		checked.Checked = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		var buf bytes.Buffer
+		if err := Write(&buf, YAML, checked); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(buf.String(), "checked: 2024-05-01T10:00:00Z\n") {
+			t.Errorf("got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("yaml writes empty slices like json", func(t *testing.T) {
+		tagged := v
+		tagged.Tags = []string{}
+		var buf bytes.Buffer
+		if err := Write(&buf, YAML, tagged); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "\ntags: []\n") {
+			t.Errorf("got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("text has no structured rendering", func(t *testing.T) {
+		if err := Write(&bytes.Buffer{}, Text, v); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestQuoteYAML(t *testing.T) {
+	tests := map[string]string{
+		"1.21.5":           "1.21.5",
+		"1.22":             `"1.22"`,
+		"":                 `""`,
+		"yes":              `"yes"`,
+		"/home/me/go/bin":  "/home/me/go/bin",
+		"GOROOT: not set":  `"GOROOT: not set"`,
+		"- item":           `"- item"`,
+		"go1.22 linux/arm": "go1.22 linux/arm",
+	}
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got := quoteYAML(in); got != want {
+				t.Errorf("quoteYAML(%q) = %s, want %s", in, got, want)
+			}
+		})
+	}
+}
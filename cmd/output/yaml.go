@@ -0,0 +1,257 @@
+package output
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalYAML renders v as YAML. It covers what the result models use:
+// structs (by their json tags), slices, string-keyed maps, pointers and
+// scalars, with struct fields kept in declaration order.
+func MarshalYAML(v any) ([]byte, error) {
+	var out strings.Builder
+	if err := writeYAML(&out, reflect.ValueOf(v), 0); err != nil {
+		return nil, err
+	}
+	return []byte(out.String()), nil
+}
+
+// writeYAML writes v as a block at the given indentation
+func writeYAML(out *strings.Builder, v reflect.Value, indent int) error {
+	v = deref(v)
+	if scalar, ok, err := yamlScalar(v); ok || err != nil {
+		if err == nil {
+			out.WriteString(strings.Repeat("  ", indent) + scalar + "\n")
+		}
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		keys, values := fields(v)
+		if len(keys) == 0 {
+			out.WriteString(strings.Repeat("  ", indent) + "{}\n")
+			return nil
+		}
+		for i, key := range keys {
+			if err := writeEntry(out, strings.Repeat("  ", indent)+quoteYAML(key)+":", values[i], indent); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			out.WriteString(strings.Repeat("  ", indent) + "[]\n")
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := writeItem(out, v.Index(i), indent); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cannot render %s as YAML", v.Type())
+	}
+	return nil
+}
+
+// writeEntry writes a mapping entry whose key and colon are in prefix
+func writeEntry(out *strings.Builder, prefix string, v reflect.Value, indent int) error {
+	v = deref(v)
+	if scalar, ok, err := yamlScalar(v); ok || err != nil {
+		if err == nil {
+			out.WriteString(prefix + " " + scalar + "\n")
+		}
+		return err
+	}
+	if empty := emptyCollection(v); empty != "" {
+		out.WriteString(prefix + " " + empty + "\n")
+		return nil
+	}
+
+	out.WriteString(prefix + "\n")
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		// Sequences in a mapping sit at the key's indentation
+		return writeYAML(out, v, indent)
+	}
+	return writeYAML(out, v, indent+1)
+}
+
+// writeItem writes one sequence item; a mapping starts on the dash line
+func writeItem(out *strings.Builder, v reflect.Value, indent int) error {
+	v = deref(v)
+	dash := strings.Repeat("  ", indent) + "- "
+	if scalar, ok, err := yamlScalar(v); ok || err != nil {
+		if err == nil {
+			out.WriteString(dash + scalar + "\n")
+		}
+		return err
+	}
+	if empty := emptyCollection(v); empty != "" {
+		out.WriteString(dash + empty + "\n")
+		return nil
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+		out.WriteString(strings.TrimRight(dash, " ") + "\n")
+		return writeYAML(out, v, indent+1)
+	}
+
+	var item strings.Builder
+	if err := writeYAML(&item, v, indent+1); err != nil {
+		return err
+	}
+	out.WriteString(dash + strings.TrimPrefix(item.String(), strings.Repeat("  ", indent+1)))
+	return nil
+}
+
+// fields returns the keys and values of a struct (honouring json tags) or map
+func fields(v reflect.Value) ([]string, []reflect.Value) {
+	var keys []string
+	var values []reflect.Value
+
+	if v.Kind() == reflect.Map {
+		for _, key := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(key.Interface()))
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			values = append(values, v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())))
+		}
+		return keys, values
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		// Embedded structs are flattened like encoding/json does
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embeddedKeys, embeddedValues := fields(v.Field(i))
+			keys = append(keys, embeddedKeys...)
+			values = append(values, embeddedValues...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if omitted(v.Field(i), strings.Split(opts, ",")) {
+			continue
+		}
+		keys = append(keys, name)
+		values = append(values, v.Field(i))
+	}
+	return keys, values
+}
+
+// omitted reports whether a field with the given json tag options is left
+// out: omitempty drops zero values and omitzero, like encoding/json, asks
+// an IsZero method first
+func omitted(v reflect.Value, opts []string) bool {
+	if slices.Contains(opts, "omitzero") {
+		if zeroer, ok := v.Interface().(interface{ IsZero() bool }); ok {
+			return zeroer.IsZero()
+		}
+		return v.IsZero()
+	}
+	return slices.Contains(opts, "omitempty") && v.IsZero()
+}
+
+// deref follows pointers and interfaces to the value they hold
+func deref(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// emptyCollection returns the flow notation of an empty slice, map or struct
+func emptyCollection(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return "[]"
+		}
+	case reflect.Map:
+		if v.Len() == 0 {
+			return "{}"
+		}
+	case reflect.Struct:
+		if keys, _ := fields(v); len(keys) == 0 {
+			return "{}"
+		}
+	}
+	return ""
+}
+
+// yamlScalar renders v when it is a scalar; ok is false for collections
+func yamlScalar(v reflect.Value) (scalar string, ok bool, err error) {
+	if !v.IsValid() {
+		return "null", true, nil
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return "null", true, nil
+	}
+	if !v.CanInterface() {
+		return "", false, fmt.Errorf("cannot render unexported %s as YAML", v.Type())
+	}
+	if marshaler, isText := v.Interface().(encoding.TextMarshaler); isText {
+		text, err := marshaler.MarshalText()
+		return quoteYAML(string(text)), true, err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return quoteYAML(v.String()), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true, nil
+	case reflect.Slice, reflect.Map:
+		// nil like encoding/json; empty ones are written as [] and {}
+		if v.IsNil() {
+			return "null", true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// yamlKeywords are plain scalars YAML would read as something other than a string
+var yamlKeywords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+// quoteYAML returns s as a plain scalar when YAML reads it back as the same
+// string, double-quoted otherwise (so "1.22" stays a string, not a number)
+func quoteYAML(s string) string {
+	if s == "" || yamlKeywords[strings.ToLower(s)] ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@` \t") ||
+		strings.ContainsAny(s, "\n\r\t") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		strings.HasSuffix(s, ":") || strings.HasSuffix(s, " ") {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f || r > 0x7e {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
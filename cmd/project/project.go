@@ -1,11 +1,13 @@
 package project
 
 import (
-	"os"
+	"fmt"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/cristobalcontreras/gos/cmd/usage"
 	"github.com/cristobalcontreras/gos/cmd/use"
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -16,34 +18,48 @@ func NewProjectCmd() *cobra.Command {
 		Short: "Configure Go version for current project",
 		Long: `Configure a specific Go version for the current project by creating a .go-version file
 and switching to that version.`,
-		Example: `  gos project 1.21.5    # Configure project to use Go 1.21.5`,
-		Args:    cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			if !common.CheckVersionManagerAvailable() {
-				return
+		Example: `  gos project 1.21.5    # Configure project to use Go 1.21.5
+  gos project 1.22      # Pin the newest installed 1.22.x`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := backend.Active()
+			if err != nil {
+				return err
 			}
-			setupProjectVersion(args[0])
+			return setupProjectVersion(b, args[0])
 		},
 	}
 }
 
 // setupProjectVersion configures a specific Go version for the current project
-func setupProjectVersion(version string) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
+func setupProjectVersion(b backend.Backend, version string) error {
+	// Pin the concrete version so the file means the same thing on every machine
+	resolved, err := backend.ResolveInstalled(b, version)
+	if err != nil {
+		ui.Hint.Printf("💡 Install it first with: gos install %s\n", version)
+		return err
+	}
+	version = resolved
 
-	blue.Printf("📁 Configuring version %s for this project...\n", version)
+	ui.Info.Printf("📁 Configuring version %s for this project...\n", version)
 
 	// Create .go-version file
 	goVersionFile := ".go-version"
-	if err := os.WriteFile(goVersionFile, []byte(version), 0644); err != nil {
-		color.Red("❌ Error creating .go-version file: %v", err)
-		return
+	if err := fsys.WriteFile(goVersionFile, []byte(version), 0644); err != nil {
+		return fmt.Errorf("creating .go-version file: %w", err)
+	}
+
+	// Remember the project so gos prune keeps its version
+	if err := usage.RegisterProject("."); err != nil {
+		ui.Warn.Printf("⚠️  Could not register the project: %v\n", err)
 	}
 
 	// Switch to that version
-	use.UseVersion(version)
+	if err := use.UseVersionWith(b, version); err != nil {
+		return err
+	}
 
-	green.Printf("✅ Project configured to use Go %s\n", version)
-	blue.Printf("📄 File created: %s\n", goVersionFile)
+	ui.Success.Printf("✅ Project configured to use Go %s\n", version)
+	ui.Info.Printf("📄 File created: %s\n", goVersionFile)
+	return nil
 }
@@ -0,0 +1,298 @@
+package prune
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	defaultcmd "github.com/cristobalcontreras/gos/cmd/default"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/usage"
+)
+
+// Policy decides which installed versions gos prune removes. The current
+// version, the default and versions pinned by registered projects are
+// always kept.
+type Policy struct {
+	// KeepPatches is how many of the newest patches of each minor line are
+	// kept; older patches are removed. 0 keeps every patch.
+	KeepPatches int
+	// UnusedFor removes versions not used for longer; 0 disables it
+	UnusedFor time.Duration
+	// MaxDisk evicts the least recently used versions until the rest fit;
+	// 0 means no budget
+	MaxDisk int64
+}
+
+// Version is the verdict on one installed version
+type Version struct {
+	Version string    `json:"version"`
+	Size    int64     `json:"size"`
+	Used    time.Time `json:"last_used,omitzero"` // zero when gos never saw it used
+	Idle    time.Time `json:"-"`                  // last use, or the install time
+	Remove  bool      `json:"remove"`
+	Reasons []string  `json:"reasons"`
+	Error   string    `json:"error,omitempty"`
+
+	// protected versions are never evicted to meet the budget
+	protected bool
+}
+
+// Plan is what gos prune removes and keeps
+type Plan struct {
+	Backend   string    `json:"backend"`
+	Versions  []Version `json:"versions"`
+	Reclaimed int64     `json:"reclaimed"`
+	Kept      int64     `json:"kept"`
+	Budget    int64     `json:"budget,omitempty"`
+	// StaleProjects are registered projects whose directory is gone
+	StaleProjects []string `json:"stale_projects,omitempty"`
+}
+
+// Removals returns the versions the plan removes
+func (p Plan) Removals() []Version {
+	var removals []Version
+	for _, v := range p.Versions {
+		if v.Remove {
+			removals = append(removals, v)
+		}
+	}
+	return removals
+}
+
+// MakePlan applies policy to the versions installed through b, as of now
+func MakePlan(b backend.Backend, policy Policy, now time.Time) (Plan, error) {
+	plan := Plan{Backend: b.Name(), Versions: []Version{}, Budget: policy.MaxDisk}
+
+	installed, err := b.ListInstalled()
+	if err != nil {
+		return plan, fmt.Errorf("listing installed versions: %w", err)
+	}
+	goversion.Sort(installed)
+
+	keep := map[string][]string{}
+	if current, err := b.Current(); err == nil && current != "" {
+		keep[current] = append(keep[current], "current")
+	}
+	if def := defaultcmd.GetDefaultVersion(b); def.Source == defaultcmd.SourceSaved {
+		if version := match(def.Version, installed); version != "" {
+			keep[version] = append(keep[version], "default")
+		}
+	}
+	for _, dir := range usage.Projects() {
+		if !fsys.Exists(dir) {
+			plan.StaleProjects = append(plan.StaleProjects, dir)
+			continue
+		}
+		if spec, path := projectPin(dir); spec != "" {
+			if version := match(spec, installed); version != "" {
+				keep[version] = append(keep[version], "pinned by "+path)
+			}
+		}
+	}
+	if native, ok := b.(*backend.Native); ok {
+		for _, version := range installed {
+			if _, linked := native.Installer.LinkTarget(version); linked {
+				keep[version] = append(keep[version], "linked; remove it with gos unlink")
+			}
+		}
+	}
+
+	newer := newerPatches(installed)
+	for i := len(installed) - 1; i >= 0; i-- {
+		version := installed[i]
+		goroot := b.GOROOT(version)
+		v := Version{Version: version, Size: dirSize(goroot)}
+		v.Used, _ = usage.LastUsed(version)
+		v.Idle = v.Used
+		if v.Idle.IsZero() {
+			if info, err := fsys.Stat(goroot); err == nil {
+				v.Idle = info.ModTime()
+			}
+		}
+
+		if reasons := keep[version]; len(reasons) > 0 {
+			v.Reasons, v.protected = reasons, true
+			plan.Versions = append(plan.Versions, v)
+			continue
+		}
+
+		idle := now.Sub(v.Idle)
+		superseded := newer[version]
+		if policy.KeepPatches > 0 && len(superseded) >= policy.KeepPatches {
+			v.Remove = true
+			v.Reasons = append(v.Reasons, "superseded by "+superseded[len(superseded)-1])
+		}
+		if policy.UnusedFor > 0 && !v.Idle.IsZero() && idle > policy.UnusedFor {
+			v.Remove = true
+			v.Reasons = append(v.Reasons, "unused for "+days(idle))
+		}
+		if !v.Remove {
+			switch {
+			case policy.KeepPatches > 0 && len(superseded) == 0 && goversion.IsValid(version):
+				v.Reasons = append(v.Reasons, "newest "+minor(version)+" patch")
+			case policy.KeepPatches > 0 && goversion.IsValid(version):
+				v.Reasons = append(v.Reasons, fmt.Sprintf("one of the %d newest %s patches", policy.KeepPatches, minor(version)))
+			}
+			switch {
+			case policy.UnusedFor > 0 && !v.Used.IsZero():
+				v.Reasons = append(v.Reasons, "used "+ago(idle))
+			case policy.UnusedFor > 0 && !v.Idle.IsZero():
+				v.Reasons = append(v.Reasons, "installed "+ago(idle))
+			}
+			if len(v.Reasons) == 0 {
+				v.Reasons = []string{"no policy removes it"}
+			}
+		}
+		plan.Versions = append(plan.Versions, v)
+	}
+
+	plan.evict()
+	for _, v := range plan.Versions {
+		if v.Remove {
+			plan.Reclaimed += v.Size
+		} else {
+			plan.Kept += v.Size
+		}
+	}
+	return plan, nil
+}
+
+// evict removes the least recently used unprotected versions until the
+// kept ones fit the budget
+func (p *Plan) evict() {
+	if p.Budget <= 0 {
+		return
+	}
+	var kept int64
+	var candidates []*Version
+	for i := range p.Versions {
+		v := &p.Versions[i]
+		if v.Remove {
+			continue
+		}
+		kept += v.Size
+		if !v.protected {
+			candidates = append(candidates, v)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Idle.Before(candidates[j].Idle)
+	})
+	for _, v := range candidates {
+		if kept <= p.Budget {
+			return
+		}
+		v.Remove = true
+		v.Reasons = []string{"least recently used; over the " + common.FormatBytes(p.Budget) + " budget"}
+		kept -= v.Size
+	}
+}
+
+// newerPatches maps each release to the newer installed versions of its
+// minor line, oldest first; installed must be sorted
+func newerPatches(installed []string) map[string][]string {
+	newer := map[string][]string{}
+	for i, version := range installed {
+		if !goversion.IsValid(version) {
+			continue
+		}
+		for _, later := range installed[i+1:] {
+			if goversion.IsValid(later) && minor(later) == minor(version) {
+				newer[version] = append(newer[version], later)
+			}
+		}
+	}
+	return newer
+}
+
+// minor returns the minor line of a version, e.g. 1.22 for 1.22.5
+func minor(version string) string {
+	v, _ := goversion.Parse(version)
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// match returns the installed version a spec selects, or "" if none does
+func match(spec string, installed []string) string {
+	for _, version := range installed {
+		if version == strings.TrimSpace(spec) {
+			return version
+		}
+	}
+	version, err := goversion.Resolve(spec, installed)
+	if err != nil {
+		return ""
+	}
+	for _, candidate := range installed {
+		if goversion.Equal(candidate, version) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// projectPin returns the version a project configures and the file that
+// does so. GOS_VERSION and the global default are not the project's.
+func projectPin(dir string) (spec, path string) {
+	result, err := resolver.Resolve(dir)
+	if err != nil {
+		return "", ""
+	}
+	for _, step := range result.Steps {
+		if step.Source != resolver.SourceEnv && step.Source != resolver.SourceDefault {
+			return step.Version, step.Path
+		}
+	}
+	return "", ""
+}
+
+// dirSize adds up the files below root; links are not followed
+func dirSize(root string) int64 {
+	var size int64
+	fsys.Walk(root, func(path string, info fs.FileInfo) error {
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// ParseAge reads a duration such as 90d, 12w or 720h
+func ParseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(age, suffix)); err == nil && strings.HasSuffix(age, suffix) && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	if d, err := time.ParseDuration(age); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid age %q: use days, weeks or hours, e.g. 90d, 12w or 720h", age)
+}
+
+// days renders a duration in whole days
+func days(d time.Duration) string {
+	n := int(d / (24 * time.Hour))
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// ago renders how long ago something happened
+func ago(d time.Duration) string {
+	if d < 24*time.Hour {
+		return "today"
+	}
+	return days(d) + " ago"
+}
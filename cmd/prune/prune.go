@@ -0,0 +1,205 @@
+package prune
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/cristobalcontreras/gos/cmd/usage"
+	"github.com/spf13/cobra"
+)
+
+// Options are the flags of gos prune
+type Options struct {
+	KeepPatches int
+	UnusedFor   string // e.g. 90d; empty disables the policy
+	MaxDisk     string // e.g. 5G; empty means no budget
+	DryRun      bool
+}
+
+// NewPruneCmd creates the prune command
+func NewPruneCmd() *cobra.Command {
+	var opts Options
+
+	cmd := output.Enable(&cobra.Command{
+		Use:   "prune",
+		Short: "Remove installed Go versions that are no longer needed",
+		Long: `Remove installed Go versions according to retention policies:
+
+  --keep-patches N   keep the N newest patches of each minor line (default 1;
+                     0 keeps every patch)
+  --unused-for AGE   remove versions not used for longer than AGE (90d, 12w)
+  --max-disk SIZE    then evict the least recently used versions until the
+                     rest fit in SIZE (10G, 512M)
+
+The current version, the default and any version pinned by a registered
+project are always kept; 'gos project' registers a project. Use is recorded
+whenever gos use, gos exec or a shim runs a version; versions never seen in
+use count from their install time.
+
+gos prune shows a table of what it keeps and removes and asks before
+removing anything; --dry-run only shows the table.`,
+		Example: `  gos prune --dry-run                  # Show what would be removed
+  gos prune                            # Keep only the newest patch of each minor
+  gos prune --unused-for 90d           # Also remove versions idle for 90 days
+  gos prune --keep-patches 2 --max-disk 5G`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := backend.Active()
+			if err != nil {
+				return err
+			}
+			return RunPrune(b, opts)
+		},
+	})
+
+	cmd.Flags().IntVar(&opts.KeepPatches, "keep-patches", 1, "Newest patches kept per minor line (0 keeps all)")
+	cmd.Flags().StringVar(&opts.UnusedFor, "unused-for", "", "Remove versions unused for longer than this, e.g. 90d")
+	cmd.Flags().StringVar(&opts.MaxDisk, "max-disk", "", "Evict least recently used versions until the rest fit, e.g. 10G")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be removed without removing anything")
+	return cmd
+}
+
+// ParsePolicy checks the flags and turns them into a Policy
+func ParsePolicy(opts Options) (Policy, error) {
+	policy := Policy{KeepPatches: opts.KeepPatches}
+	if opts.KeepPatches < 0 {
+		return policy, &errs.UsageError{Err: fmt.Errorf("--keep-patches must not be negative")}
+	}
+	if opts.UnusedFor != "" {
+		age, err := ParseAge(opts.UnusedFor)
+		if err != nil {
+			return policy, &errs.UsageError{Err: err}
+		}
+		policy.UnusedFor = age
+	}
+	if opts.MaxDisk != "" {
+		size, err := common.ParseBytes(opts.MaxDisk)
+		if err != nil {
+			return policy, &errs.UsageError{Err: err}
+		}
+		policy.MaxDisk = size
+	}
+	return policy, nil
+}
+
+// RunPrune plans what to remove, shows it, asks and removes the versions
+func RunPrune(b backend.Backend, opts Options) error {
+	policy, err := ParsePolicy(opts)
+	if err != nil {
+		return err
+	}
+	plan, err := MakePlan(b, policy, time.Now())
+	if err != nil {
+		return err
+	}
+	removals := plan.Removals()
+
+	if !output.Structured() {
+		showPlan(plan, time.Now())
+	}
+	if opts.DryRun || (len(removals) == 0 && len(plan.StaleProjects) == 0) {
+		if output.Structured() {
+			return output.Print(plan)
+		}
+		switch {
+		case len(removals) == 0:
+			ui.Success.Println("✅ Nothing to prune")
+		default:
+			ui.Hint.Println("💡 Dry run: nothing was removed. Run without --dry-run to remove them.")
+		}
+		return nil
+	}
+
+	question := fmt.Sprintf("Remove %d version(s) and reclaim %s?", len(removals), common.FormatBytes(plan.Reclaimed))
+	if len(removals) == 0 {
+		question = fmt.Sprintf("Forget %d registered project(s) that no longer exist?", len(plan.StaleProjects))
+	}
+	apply, err := ui.Confirm(question, false)
+	if err != nil {
+		return err
+	}
+	if !apply {
+		ui.Hint.Println("Nothing was removed.")
+		return nil
+	}
+
+	for _, dir := range plan.StaleProjects {
+		usage.UnregisterProject(dir)
+	}
+	failed := remove(b, &plan)
+	shim.Refresh(b)
+	if output.Structured() {
+		if err := output.Print(plan); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d version(s) could not be removed", failed)
+	}
+	if len(removals) > 0 {
+		ui.Success.Printf("✅ Removed %d version(s), reclaiming %s\n", len(removals), common.FormatBytes(plan.Reclaimed))
+	} else {
+		ui.Success.Printf("✅ Forgot %d registered project(s)\n", len(plan.StaleProjects))
+	}
+	return nil
+}
+
+// remove uninstalls the versions the plan removes, recording failures in
+// the plan, and returns how many failed
+func remove(b backend.Backend, plan *Plan) int {
+	failed := 0
+	for i := range plan.Versions {
+		v := &plan.Versions[i]
+		if !v.Remove {
+			continue
+		}
+		if err := b.Uninstall(v.Version); err != nil {
+			ui.Error.Printf("  ❌ Go %s: %v\n", v.Version, err)
+			v.Error = err.Error()
+			plan.Reclaimed -= v.Size
+			failed++
+			continue
+		}
+		usage.Forget(v.Version)
+		ui.Success.Printf("  🗑️  Removed Go %s\n", v.Version)
+	}
+	return failed
+}
+
+// showPlan prints the table of kept and removed versions
+func showPlan(plan Plan, now time.Time) {
+	ui.Title.Printf("🧹 Installed Go versions (%s):\n", plan.Backend)
+	if len(plan.Versions) == 0 {
+		ui.Out.Println("  No Go versions installed")
+		return
+	}
+
+	ui.Note.Printf("  %-16s %8s  %-14s %-7s %s\n", "VERSION", "SIZE", "LAST USED", "ACTION", "WHY")
+	for _, v := range plan.Versions {
+		used := "never"
+		if !v.Used.IsZero() {
+			used = ago(now.Sub(v.Used))
+		}
+		printer, action := ui.Out, "keep"
+		if v.Remove {
+			printer, action = ui.Caution, "remove"
+		}
+		printer.Printf("  %-16s %8s  %-14s %-7s %s\n", v.Version, common.FormatBytes(v.Size), used, action, strings.Join(v.Reasons, ", "))
+	}
+
+	ui.Out.Println("")
+	ui.Info.Printf("📦 Reclaims %s; %s stays installed\n", common.FormatBytes(plan.Reclaimed), common.FormatBytes(plan.Kept))
+	if plan.Budget > 0 && plan.Kept > plan.Budget {
+		ui.Warn.Printf("⚠️  The kept versions still exceed the %s budget: the rest are current, default or pinned\n", common.FormatBytes(plan.Budget))
+	}
+	for _, dir := range plan.StaleProjects {
+		ui.Hint.Printf("💡 Registered project %s no longer exists; pruning forgets it\n", dir)
+	}
+}
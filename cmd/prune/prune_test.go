@@ -0,0 +1,218 @@
+package prune
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/cristobalcontreras/gos/cmd/usage"
+)
+
+// withVersions installs versions of the given sizes in the testenv sandbox
+func withVersions(t *testing.T, current string, sizes map[string]int) (*testenv.Backend, *fsys.Mem) {
+	t.Helper()
+	_, mem, _ := testenv.Sandbox(t)
+
+	b := testenv.NewBackend()
+	b.Version = current
+	for version, size := range sizes {
+		bin := filepath.Join(b.GOROOT(version), "bin")
+		if err := mem.MkdirAll(bin, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := mem.WriteFile(filepath.Join(bin, "go"), make([]byte, size), 0755); err != nil {
+			t.Fatal(err)
+		}
+		b.Installed = append(b.Installed, version)
+	}
+	return b, mem
+}
+
+// used records a use of version at when
+func used(t *testing.T, mem *fsys.Mem, version string, when time.Time) {
+	t.Helper()
+	if err := mem.MkdirAll(usage.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteFile(filepath.Join(usage.Dir(), version), []byte(when.Format(time.RFC3339)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// verdicts maps each version of a plan to "keep" or "remove"
+func verdicts(plan Plan) map[string]string {
+	got := map[string]string{}
+	for _, v := range plan.Versions {
+		got[v.Version] = "keep"
+		if v.Remove {
+			got[v.Version] = "remove"
+		}
+	}
+	return got
+}
+
+func checkVerdicts(t *testing.T, plan Plan, want map[string]string) {
+	t.Helper()
+	got := verdicts(plan)
+	for version, verdict := range want {
+		if got[version] != verdict {
+			t.Errorf("%s: %s, want %s (plan %+v)", version, got[version], verdict, plan.Versions)
+		}
+	}
+}
+
+func TestMakePlan(t *testing.T) {
+	sizes := map[string]int{"1.21.1": 100, "1.21.5": 100, "1.22.0": 100, "1.22.3": 100, "1.23rc1": 100, "1.23.0": 100}
+
+	t.Run("keeps the newest patches of each minor line", func(t *testing.T) {
+		b, _ := withVersions(t, "1.23.0", sizes)
+		plan, err := MakePlan(b, Policy{KeepPatches: 1}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVerdicts(t, plan, map[string]string{
+			"1.23.0": "keep", "1.23rc1": "remove", "1.22.3": "keep", "1.22.0": "remove", "1.21.5": "keep", "1.21.1": "remove",
+		})
+		if plan.Reclaimed != 300 || plan.Kept != 300 {
+			t.Errorf("Reclaimed, Kept = %d, %d", plan.Reclaimed, plan.Kept)
+		}
+		if plan.Versions[0].Version != "1.23.0" {
+			t.Errorf("versions are not listed newest first: %+v", plan.Versions)
+		}
+
+		plan, _ = MakePlan(b, Policy{KeepPatches: 2}, time.Now())
+		checkVerdicts(t, plan, map[string]string{"1.22.0": "keep", "1.21.1": "keep", "1.23rc1": "keep"})
+	})
+
+	t.Run("current, default and pinned versions are kept", func(t *testing.T) {
+		b, mem := withVersions(t, "1.21.1", sizes)
+		if err := mem.WriteFile(resolver.DefaultFile(), []byte("1.22.0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		project := filepath.Join(common.GetHomeDir(), "src", "app")
+		mem.MkdirAll(project, 0755)
+		mem.WriteFile(filepath.Join(project, ".go-version"), []byte("1.23rc1\n"), 0644)
+		if err := usage.RegisterProject(project); err != nil {
+			t.Fatal(err)
+		}
+		gone := filepath.Join(common.GetHomeDir(), "src", "gone")
+		usage.RegisterProject(gone)
+
+		plan, err := MakePlan(b, Policy{KeepPatches: 1, MaxDisk: 1}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVerdicts(t, plan, map[string]string{"1.21.1": "keep", "1.22.0": "keep", "1.23rc1": "keep"})
+		for _, v := range plan.Versions {
+			if v.Version == "1.23rc1" && !strings.HasPrefix(v.Reasons[0], "pinned by ") {
+				t.Errorf("1.23rc1 reasons = %v", v.Reasons)
+			}
+		}
+		if len(plan.StaleProjects) != 1 || plan.StaleProjects[0] != gone {
+			t.Errorf("StaleProjects = %v", plan.StaleProjects)
+		}
+	})
+
+	t.Run("versions unused for too long are removed", func(t *testing.T) {
+		b, mem := withVersions(t, "1.23.0", sizes)
+		now := time.Now().Add(100 * 24 * time.Hour)
+		used(t, mem, "1.21.5", now.Add(-24*time.Hour))
+		used(t, mem, "1.22.3", now.Add(-91*24*time.Hour))
+
+		plan, err := MakePlan(b, Policy{UnusedFor: 90 * 24 * time.Hour}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Never used versions count from their install time
+		checkVerdicts(t, plan, map[string]string{
+			"1.23.0": "keep", "1.21.5": "keep", "1.22.3": "remove", "1.22.0": "remove", "1.21.1": "remove",
+		})
+	})
+
+	t.Run("the budget evicts the least recently used first", func(t *testing.T) {
+		b, mem := withVersions(t, "1.23.0", map[string]int{"1.21.5": 300, "1.22.3": 200, "1.23.0": 100})
+		now := time.Now()
+		used(t, mem, "1.21.5", now.Add(-time.Hour))
+		used(t, mem, "1.22.3", now.Add(-48*time.Hour))
+
+		plan, err := MakePlan(b, Policy{MaxDisk: 450}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVerdicts(t, plan, map[string]string{"1.23.0": "keep", "1.21.5": "keep", "1.22.3": "remove"})
+
+		plan, _ = MakePlan(b, Policy{MaxDisk: 50}, now)
+		checkVerdicts(t, plan, map[string]string{"1.23.0": "keep", "1.21.5": "remove", "1.22.3": "remove"})
+		if plan.Kept != 100 {
+			t.Errorf("Kept = %d, want the current version only", plan.Kept)
+		}
+	})
+}
+
+func TestRunPrune(t *testing.T) {
+	sizes := map[string]int{"1.21.1": 100, "1.21.5": 100}
+	configure := func(t *testing.T, opts ui.Options) {
+		previous := ui.Current()
+		ui.Configure(opts)
+		t.Cleanup(func() { ui.Configure(previous) })
+	}
+	gone := func(t *testing.T) string {
+		dir := filepath.Join(common.GetHomeDir(), "src", "gone")
+		if err := usage.RegisterProject(dir); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	t.Run("a declined prompt changes nothing", func(t *testing.T) {
+		b, mem := withVersions(t, "1.21.5", sizes)
+		dir := gone(t)
+		configure(t, ui.Options{NoInput: true, Quiet: true})
+
+		if err := RunPrune(b, Options{KeepPatches: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if projects := usage.Projects(); len(projects) != 1 || projects[0] != dir {
+			t.Errorf("Projects() = %v, want the stale project kept", projects)
+		}
+		if _, err := mem.Stat(b.GOROOT("1.21.1")); err != nil {
+			t.Errorf("1.21.1 was removed: %v", err)
+		}
+	})
+
+	t.Run("stale projects are forgotten once confirmed", func(t *testing.T) {
+		b, _ := withVersions(t, "1.21.5", sizes)
+		gone(t)
+		configure(t, ui.Options{AssumeYes: true, Quiet: true})
+
+		if err := RunPrune(b, Options{KeepPatches: 0}); err != nil {
+			t.Fatal(err)
+		}
+		if projects := usage.Projects(); len(projects) != 0 {
+			t.Errorf("Projects() = %v, want none", projects)
+		}
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy(Options{KeepPatches: 2, UnusedFor: "12w", MaxDisk: "1.5G"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Policy{KeepPatches: 2, UnusedFor: 84 * 24 * time.Hour, MaxDisk: 1536 << 20}
+	if policy != want {
+		t.Errorf("ParsePolicy() = %+v, want %+v", policy, want)
+	}
+
+	for _, opts := range []Options{{KeepPatches: -1}, {UnusedFor: "soon"}, {UnusedFor: "-3d"}, {MaxDisk: "lots"}} {
+		if _, err := ParsePolicy(opts); err == nil {
+			t.Errorf("ParsePolicy(%+v) accepted bad options", opts)
+		}
+	}
+}
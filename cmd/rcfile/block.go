@@ -0,0 +1,208 @@
+package rcfile
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Markers delimiting the block gos owns in a shell startup file
+const (
+	BeginMarker = "# >>> gos >>>"
+	EndMarker   = "# <<< gos <<<"
+)
+
+// notice is the first line inside the block
+const notice = "# Managed by gos; changes inside this block are overwritten."
+
+// legacyHeaders start snippets older gos versions appended without markers
+var legacyHeaders = []string{
+	"# gos (Go version manager)",
+	"# gobrew (Go version manager) configuration",
+	"# Go Version Manager PATH",
+}
+
+// Extract returns the body of the gos block, if the content has one
+func Extract(content string) (string, bool) {
+	lines := splitLines(content)
+	start, end := findBlock(lines)
+	if start < 0 {
+		return "", false
+	}
+
+	var body []string
+	for _, line := range lines[start+1 : end] {
+		if line != notice {
+			body = append(body, line)
+		}
+	}
+	return strings.Join(body, "\n"), true
+}
+
+// Set returns content with the gos block holding body. An existing block is
+// replaced in place; otherwise the block is appended. Lines outside the block
+// are never touched.
+func Set(content, body string) string {
+	block := []string{BeginMarker, notice}
+	block = append(block, splitLines(strings.TrimRight(body, "\n"))...)
+	block = append(block, EndMarker)
+
+	lines := splitLines(content)
+	start, end := findBlock(lines)
+	if start >= 0 {
+		result := append(append(append([]string{}, lines[:start]...), block...), lines[end+1:]...)
+		return joinLines(result, content)
+	}
+
+	if len(lines) > 0 && lines[len(lines)-1] != "" {
+		lines = append(lines, "")
+	}
+	return joinLines(append(lines, block...), "\n")
+}
+
+// Remove returns content without the gos block and the blank line left before it
+func Remove(content string) string {
+	lines := splitLines(content)
+	start, end := findBlock(lines)
+	if start < 0 {
+		return content
+	}
+
+	before := lines[:start]
+	if len(before) > 0 && before[len(before)-1] == "" && (end+1 >= len(lines) || lines[end+1] == "") {
+		before = before[:len(before)-1]
+	}
+	return joinLines(append(append([]string{}, before...), lines[end+1:]...), content)
+}
+
+// RemoveLegacy drops the unmarked snippets earlier gos versions appended: a
+// known header comment with the snippet lines below it, plus the
+// "# Go environment" paragraph that directly followed some of them. Only
+// lines shaped like those snippets are dropped, so a user line appended
+// right below one is kept.
+func RemoveLegacy(content string) string {
+	lines := splitLines(content)
+	var result []string
+
+	for i := 0; i < len(lines); i++ {
+		if !isLegacyHeader(lines[i]) {
+			result = append(result, lines[i])
+			continue
+		}
+
+		// Drop the snippet lines the header starts
+		for i+1 < len(lines) && isLegacyLine(lines[i+1]) {
+			i++
+		}
+		// ...and an adjacent "# Go environment" paragraph written with it
+		if i+2 < len(lines) && strings.TrimSpace(lines[i+1]) == "" && strings.TrimSpace(lines[i+2]) == "# Go environment" {
+			i += 2
+			for i+1 < len(lines) && isLegacyLine(lines[i+1]) {
+				i++
+			}
+		}
+		// Collapse the blank line that separated the snippet from the user's lines
+		if len(result) > 0 && result[len(result)-1] == "" && (i+1 >= len(lines) || lines[i+1] == "") {
+			result = result[:len(result)-1]
+		}
+	}
+	return joinLines(result, content)
+}
+
+// legacyLines are lines older gos snippets held verbatim
+var legacyLines = []string{
+	`export GOPATH="$HOME/go"`,
+	`export PATH="$GOPATH/bin:$PATH"`,
+	`set GOPATH=%USERPROFILE%\go`,
+	`set PATH=%GOPATH%\bin;%PATH%`,
+}
+
+var (
+	// legacyInit matches the gos init line of the unmarked snippet
+	legacyInit = regexp.MustCompile(`^eval "\$\(gos init [a-z]+\)"$`)
+	// legacyDir matches a directory of gobrew, g or gos, e.g. $HOME/.gobrew/bin
+	legacyDir = regexp.MustCompile(`^(\$HOME|%USERPROFILE%|([A-Za-z]:)?[^:;"]*)[/\\]\.(gobrew|g|gos)([/\\][^:;"]*)?$`)
+)
+
+// isLegacyLine reports whether a line is one older gos snippets wrote: the
+// GOPATH lines, the gos init line, a GOROOT export into a version manager,
+// or a PATH line adding only version manager directories and $HOME/go/bin.
+// Anything else is the user's and is kept.
+func isLegacyLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if slices.Contains(legacyLines, line) || legacyInit.MatchString(line) {
+		return true
+	}
+	if value, ok := strings.CutPrefix(line, "export GOROOT="); ok {
+		return legacyDir.MatchString(strings.Trim(value, `"`))
+	}
+
+	value, ok := strings.CutPrefix(line, "export PATH=")
+	separator, rest := ":", "$PATH"
+	if !ok {
+		value, ok = strings.CutPrefix(line, "set PATH=")
+		separator, rest = ";", "%PATH%"
+	}
+	if !ok {
+		return false
+	}
+	entries := strings.Split(strings.Trim(value, `"`), separator)
+	if len(entries) < 2 || entries[len(entries)-1] != rest {
+		return false
+	}
+	for _, entry := range entries[:len(entries)-1] {
+		if entry != "$HOME/go/bin" && !legacyDir.MatchString(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// isLegacyHeader reports whether a line starts a snippet from an older gos
+func isLegacyHeader(line string) bool {
+	line = strings.TrimSpace(line)
+	for _, header := range legacyHeaders {
+		if strings.HasPrefix(line, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// findBlock returns the marker line indexes, or -1 when there is no complete block
+func findBlock(lines []string) (start, end int) {
+	start = -1
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case BeginMarker:
+			if start < 0 {
+				start = i
+			}
+		case EndMarker:
+			if start >= 0 {
+				return start, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// splitLines splits content into lines without the trailing newline
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// joinLines joins lines, ending with a newline when the original did or was empty
+func joinLines(lines []string, original string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	joined := strings.Join(lines, "\n")
+	if original == "" || strings.HasSuffix(original, "\n") {
+		joined += "\n"
+	}
+	return joined
+}
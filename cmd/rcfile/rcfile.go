@@ -0,0 +1,163 @@
+package rcfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+// Target is a shell startup file and the shell that reads it
+type Target struct {
+	Path  string
+	Shell shell.Shell
+}
+
+// Targets returns the startup files gos manages: zsh and bash files on Unix,
+// the PowerShell profile on Windows, and fish's config when fish is set up
+func Targets() []Target {
+	homeDir := common.GetHomeDir()
+
+	var targets []Target
+	if runtime.GOOS == "windows" {
+		targets = append(targets, Target{filepath.Join(homeDir, common.PowerShellProfile), shell.Pwsh})
+	} else {
+		targets = append(targets,
+			Target{filepath.Join(homeDir, common.ZshrcFile), shell.Zsh},
+			Target{filepath.Join(homeDir, common.BashrcFile), shell.Bash},
+			Target{filepath.Join(homeDir, common.BashProfileFile), shell.Bash},
+		)
+	}
+	if fsys.Exists(filepath.Join(homeDir, ".config", "fish")) {
+		targets = append(targets, Target{filepath.Join(homeDir, shell.Fish.RCFile()), shell.Fish})
+	}
+	return targets
+}
+
+// CurrentTarget returns the startup file of the shell gos runs under,
+// defaulting to zsh
+func CurrentTarget() Target {
+	sh, err := shell.Detect()
+	if err != nil {
+		sh = shell.Zsh
+	}
+
+	targets := Targets()
+	for _, target := range targets {
+		if target.Shell != sh {
+			continue
+		}
+		// Prefer ~/.bashrc, falling back to ~/.bash_profile when only that exists
+		if fsys.Exists(target.Path) || sh != shell.Bash {
+			return target
+		}
+	}
+	for _, target := range targets {
+		if target.Shell == sh {
+			return target
+		}
+	}
+	return Target{Path: filepath.Join(common.GetHomeDir(), sh.RCFile()), Shell: sh}
+}
+
+// Edit is a pending change to one file
+type Edit struct {
+	Path   string
+	Before string
+	After  string
+}
+
+// Read loads a file for editing; a missing file reads as empty
+func Read(path string) (string, error) {
+	data, err := fsys.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PlanInit prepares an edit that puts the `gos init` line for the target's
+// shell into the gos block, dropping snippets left by older gos versions
+func PlanInit(target Target) (Edit, error) {
+	before, err := Read(target.Path)
+	if err != nil {
+		return Edit{}, err
+	}
+	after := Set(RemoveLegacy(before), target.Shell.InitLine())
+	return Edit{Path: target.Path, Before: before, After: after}, nil
+}
+
+// PlanRemove prepares an edit that removes the gos block and older snippets
+func PlanRemove(path string) (Edit, error) {
+	before, err := Read(path)
+	if err != nil {
+		return Edit{}, err
+	}
+	return Edit{Path: path, Before: before, After: Remove(RemoveLegacy(before))}, nil
+}
+
+// Changed reports whether the edit modifies the file
+func (e Edit) Changed() bool {
+	return e.Before != e.After
+}
+
+// Diff returns the unified diff of the edit
+func (e Edit) Diff() string {
+	return UnifiedDiff(e.Path, e.Before, e.After)
+}
+
+// Write saves the edit atomically, keeping the file's permissions. A
+// symlinked rc file (e.g. one kept in a dotfiles repository) is written
+// through, so the link stays in place.
+func (e Edit) Write() error {
+	if !e.Changed() {
+		return nil
+	}
+
+	path, err := fsys.FollowLinks(e.Path)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := fsys.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".gos-tmp"
+	if err := fsys.WriteFile(tmp, []byte(e.After), mode); err != nil {
+		return err
+	}
+	return fsys.Rename(tmp, path)
+}
+
+// PrintDiff shows the edit's diff with added and removed lines colored
+func (e Edit) PrintDiff() {
+	ui.Diff(e.Diff())
+}
+
+// Apply shows the diff of every changed edit and writes it. Unchanged files
+// are reported as up to date.
+func Apply(edits []Edit) error {
+	for _, edit := range edits {
+		if !edit.Changed() {
+			ui.Success.Printf("  ✅ %s is up to date\n", edit.Path)
+			continue
+		}
+
+		edit.PrintDiff()
+		if err := edit.Write(); err != nil {
+			return fmt.Errorf("writing %s: %w", edit.Path, err)
+		}
+		ui.Success.Printf("  ✅ Updated %s\n", edit.Path)
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package rcfile
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+)
+
+const userContent = `export EDITOR=vim
+export PATH="$HOME/mongo/bin:$PATH"
+alias ll='ls -la'
+`
+
+func TestBlock(t *testing.T) {
+	t.Run("set appends the block after a blank line", func(t *testing.T) {
+		got := Set(userContent, `eval "$(gos init zsh)"`)
+		want := userContent + "\n" + BeginMarker + "\n" + notice + "\n" + `eval "$(gos init zsh)"` + "\n" + EndMarker + "\n"
+		if got != want {
+			t.Errorf("Set() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("set is idempotent", func(t *testing.T) {
+		once := Set(userContent, `eval "$(gos init zsh)"`)
+		if twice := Set(once, `eval "$(gos init zsh)"`); twice != once {
+			t.Errorf("second Set() changed the content:\n%s", twice)
+		}
+	})
+
+	t.Run("set replaces the block in place", func(t *testing.T) {
+		content := "first\n" + BeginMarker + "\nold line\n" + EndMarker + "\nlast\n"
+		got := Set(content, "new line")
+		want := "first\n" + BeginMarker + "\n" + notice + "\nnew line\n" + EndMarker + "\nlast\n"
+		if got != want {
+			t.Errorf("Set() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("set into an empty file", func(t *testing.T) {
+		got := Set("", "body")
+		if want := BeginMarker + "\n" + notice + "\nbody\n" + EndMarker + "\n"; got != want {
+			t.Errorf("Set() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("extract returns the body", func(t *testing.T) {
+		body, ok := Extract(Set(userContent, "line one\nline two"))
+		if !ok || body != "line one\nline two" {
+			t.Errorf("Extract() = %q, %v", body, ok)
+		}
+		if _, ok := Extract(userContent); ok {
+			t.Error("Extract() found a block in content without one")
+		}
+	})
+
+	t.Run("remove restores the original", func(t *testing.T) {
+		if got := Remove(Set(userContent, "body")); got != userContent {
+			t.Errorf("Remove() =\n%s\nwant\n%s", got, userContent)
+		}
+		if got := Remove(userContent); got != userContent {
+			t.Errorf("Remove() without a block changed the content:\n%s", got)
+		}
+	})
+
+	t.Run("incomplete block is left alone", func(t *testing.T) {
+		content := userContent + BeginMarker + "\nbody\n"
+		if got := Remove(content); got != content {
+			t.Errorf("Remove() touched an unterminated block:\n%s", got)
+		}
+	})
+}
+
+func TestRemoveLegacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "gos init snippet",
+			content: "export EDITOR=vim\n\n# gos (Go version manager)\neval \"$(gos init zsh)\"\n\nalias ll='ls -la'\n",
+			want:    "export EDITOR=vim\n\nalias ll='ls -la'\n",
+		},
+		{
+			name:    "gobrew snippet with Go environment paragraph",
+			content: "export EDITOR=vim\n\n# gobrew (Go version manager) configuration\nexport PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$PATH\"\n\n# Go environment\nexport GOPATH=\"$HOME/go\"\nexport PATH=\"$GOPATH/bin:$PATH\"\n",
+			want:    "export EDITOR=vim\n",
+		},
+		{
+			name:    "user lines right below a snippet are kept",
+			content: "# gobrew (Go version manager) configuration\nexport PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$PATH\"\nalias gs='git status'\nsource ~/.aliases\n",
+			want:    "alias gs='git status'\nsource ~/.aliases\n",
+		},
+		{
+			name:    "user PATH lines right below a snippet are kept",
+			content: "# gobrew (Go version manager) configuration\nexport PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$PATH\"\nexport PATH=\"$HOME/.the go tool/bin:$PATH\"\nexport GOPATH=\"$HOME/work\"\n",
+			want:    "export PATH=\"$HOME/.the go tool/bin:$PATH\"\nexport GOPATH=\"$HOME/work\"\n",
+		},
+		{
+			name:    "absolute and Windows snippets",
+			content: "# gobrew (Go version manager) configuration\nexport PATH=\"/home/gopher/.gobrew/current/bin:/home/gopher/.gobrew/bin:$PATH\"\n\n# Go environment\nset GOPATH=%USERPROFILE%\\go\nset PATH=%GOPATH%\\bin;%PATH%\n\n# Go Version Manager PATH\nset PATH=C:\\Users\\gopher\\.gobrew\\current\\bin;%PATH%\n",
+			want:    "",
+		},
+		{
+			name:    "user Go lines are kept",
+			content: userContent,
+			want:    userContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemoveLegacy(tt.content); got != tt.want {
+				t.Errorf("RemoveLegacy() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("equal content has no diff", func(t *testing.T) {
+		if got := UnifiedDiff("f", "a\n", "a\n"); got != "" {
+			t.Errorf("UnifiedDiff() = %q, want empty", got)
+		}
+	})
+
+	t.Run("added block", func(t *testing.T) {
+		got := UnifiedDiff("~/.zshrc", "a\nb\n", "a\nb\n\n"+BeginMarker+"\n")
+		want := "--- ~/.zshrc\n+++ ~/.zshrc\n@@ -1,2 +1,4 @@\n a\n b\n+\n+" + BeginMarker + "\n"
+		if got != want {
+			t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("distant changes make separate hunks", func(t *testing.T) {
+		before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+		after := "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n"
+		got := UnifiedDiff("f", before, after)
+		if n := strings.Count(got, "@@ -"); n != 2 {
+			t.Errorf("UnifiedDiff() has %d hunks, want 2:\n%s", n, got)
+		}
+		if !strings.Contains(got, "@@ -1,4 +1,4 @@\n-1\n+one\n") || !strings.Contains(got, "@@ -9,4 +9,4 @@\n") {
+			t.Errorf("UnifiedDiff() hunk headers are wrong:\n%s", got)
+		}
+	})
+}
+
+func TestEdit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".zshrc")
+	if err := os.WriteFile(path, []byte(userContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	target := Target{Path: path, Shell: shell.Zsh}
+	edit, err := PlanInit(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !edit.Changed() {
+		t.Fatal("PlanInit() planned no change for a file without the block")
+	}
+	if err := edit.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Write() changed the file mode to %v", info.Mode().Perm())
+	}
+
+	again, err := PlanInit(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Changed() {
+		t.Errorf("PlanInit() is not idempotent:\n%s", again.Diff())
+	}
+
+	removal, err := PlanRemove(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removal.After != userContent {
+		t.Errorf("PlanRemove() =\n%s\nwant\n%s", removal.After, userContent)
+	}
+
+	missing, err := PlanInit(Target{Path: filepath.Join(dir, "missing"), Shell: shell.Bash})
+	if err != nil || missing.Before != "" {
+		t.Errorf("PlanInit() on a missing file = %+v, %v", missing, err)
+	}
+}
+
+func TestEditSymlink(t *testing.T) {
+	writeThroughLink := func(t *testing.T, dir string) {
+		t.Helper()
+		dotfiles := filepath.Join(dir, "dotfiles", "zshrc")
+		if err := fsys.MkdirAll(filepath.Dir(dotfiles), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(dotfiles, []byte(userContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, ".zshrc")
+		if err := fsys.Symlink(filepath.Join("dotfiles", "zshrc"), path); err != nil {
+			t.Fatal(err)
+		}
+
+		edit, err := PlanInit(Target{Path: path, Shell: shell.Zsh})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := edit.Write(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !fsys.IsLink(path) {
+			t.Fatal("Write() replaced the symlink")
+		}
+		data, err := fsys.ReadFile(dotfiles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != edit.After {
+			t.Errorf("link target =\n%s\nwant\n%s", data, edit.After)
+		}
+	}
+
+	t.Run("os", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks need privileges on Windows")
+		}
+		writeThroughLink(t, t.TempDir())
+	})
+
+	t.Run("mem", func(t *testing.T) {
+		home, _, _ := testenv.Sandbox(t)
+		writeThroughLink(t, home)
+	})
+}
+
+func TestEditInMemory(t *testing.T) {
+	home, mem, _ := testenv.Sandbox(t, filepath.Join(".config", "fish"))
+	zshrc := filepath.Join(home, ".zshrc")
+	if err := mem.WriteFile(zshrc, []byte(userContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	targets := Targets()
+	last := targets[len(targets)-1]
+	if last.Shell != shell.Fish || last.Path != filepath.Join(home, shell.Fish.RCFile()) {
+		t.Fatalf("Targets() = %v, want the fish config last", targets)
+	}
+
+	for _, target := range targets {
+		edit, err := PlanInit(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := edit.Write(); err != nil {
+			t.Fatalf("writing %s: %v", target.Path, err)
+		}
+	}
+
+	data, err := mem.ReadFile(zshrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), userContent) || !strings.Contains(string(data), shell.Zsh.InitLine()) {
+		t.Errorf(".zshrc =\n%s", data)
+	}
+	if info, _ := mem.Stat(zshrc); info.Mode().Perm() != 0600 {
+		t.Errorf("Write() changed the file mode to %v", info.Mode().Perm())
+	}
+	if _, err := mem.Stat(zshrc + ".gos-tmp"); err == nil {
+		t.Error("Write() left its temporary file behind")
+	}
+	if _, err := mem.Stat(last.Path); err != nil {
+		t.Errorf("fish config was not created: %v", err)
+	}
+}
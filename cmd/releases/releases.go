@@ -0,0 +1,229 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+)
+
+// DefaultBaseURL is where official Go releases and their index are published
+const DefaultBaseURL = "https://go.dev/dl/"
+
+// DefaultTTL is how long a cached release index is considered fresh
+const DefaultTTL = time.Hour
+
+// File is a single downloadable artifact of a release
+type File struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"` // "archive", "installer" or "source"
+}
+
+// Release is a Go release as published in the go.dev JSON index
+type Release struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	Files   []File `json:"files"`
+}
+
+// Client fetches the release index and caches it on disk
+type Client struct {
+	// BaseURL is where archives live. It may be a template containing
+	// {filename}, {version}, {os}, {arch} or {kind} placeholders.
+	BaseURL string
+	// IndexURL is the JSON index location; derived from BaseURL when empty
+	IndexURL string
+	// CacheFile stores the last fetched index; caching is disabled when empty
+	CacheFile string
+	// TTL is how long the cached index is used before refetching
+	TTL  time.Duration
+	HTTP *http.Client
+}
+
+// NewClient creates a client configured from GOS_DOWNLOAD_URL / download_url,
+// GOS_INDEX_URL / index_url and GOS_INDEX_TTL / index_ttl
+func NewClient() *Client {
+	client := &Client{
+		BaseURL:   DefaultBaseURL,
+		IndexURL:  config.Get("index_url"),
+		CacheFile: filepath.Join(common.GetGosHome(), "cache", "releases.json"),
+		TTL:       DefaultTTL,
+		HTTP:      &http.Client{Timeout: 30 * time.Minute},
+	}
+
+	if baseURL := config.Get("download_url"); baseURL != "" {
+		client.BaseURL = baseURL
+	}
+	if ttl, err := time.ParseDuration(config.Get("index_ttl")); err == nil {
+		client.TTL = ttl
+	}
+	return client
+}
+
+// indexURL returns the JSON index location
+func (c *Client) indexURL() string {
+	if c.IndexURL != "" {
+		return c.IndexURL
+	}
+	base := c.BaseURL
+	if i := strings.Index(base, "{"); i >= 0 {
+		// Templates: the index sits in the directory the template points into
+		base = base[:strings.LastIndex(base[:i], "/")+1]
+	}
+	return strings.TrimSuffix(base, "/") + "/?mode=json&include=all"
+}
+
+// DownloadURL returns where a release file can be downloaded from
+func (c *Client) DownloadURL(file File) string {
+	if strings.Contains(c.BaseURL, "{") {
+		return strings.NewReplacer(
+			"{filename}", file.Filename,
+			"{version}", file.Version,
+			"{os}", file.OS,
+			"{arch}", file.Arch,
+			"{kind}", file.Kind,
+		).Replace(c.BaseURL)
+	}
+	return strings.TrimSuffix(c.BaseURL, "/") + "/" + file.Filename
+}
+
+// Releases returns the release index, newest first, using the cache while it is fresh
+func (c *Client) Releases() ([]Release, error) {
+	if releases, fresh := c.readCache(); fresh {
+		return releases, nil
+	}
+
+	releases, err := c.Refresh()
+	if err != nil {
+		// Offline: fall back to a stale cache rather than failing
+		if stale, _ := c.readCache(); stale != nil {
+			return stale, nil
+		}
+		return nil, err
+	}
+	return releases, nil
+}
+
+// Refresh downloads the index, bypassing and then updating the cache
+func (c *Client) Refresh() ([]Release, error) {
+	url := c.indexURL()
+
+	resp, err := c.HTTP.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching release index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching release index %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching release index: %w", err)
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("decoding release index: %w", err)
+	}
+
+	c.writeCache(data)
+	return releases, nil
+}
+
+// readCache returns the cached index and whether it is still within the TTL
+func (c *Client) readCache() ([]Release, bool) {
+	if c.CacheFile == "" {
+		return nil, false
+	}
+
+	info, err := os.Stat(c.CacheFile)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.CacheFile)
+	if err != nil {
+		return nil, false
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, false
+	}
+	return releases, time.Since(info.ModTime()) < c.TTL
+}
+
+// writeCache stores the raw index; failures only cost a refetch next time
+func (c *Client) writeCache(data []byte) {
+	if c.CacheFile == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.CacheFile), 0755); err != nil {
+		return
+	}
+	tmp := c.CacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, c.CacheFile)
+}
+
+// Find returns the release with the given version ("go" prefix optional)
+func (c *Client) Find(version string) (Release, error) {
+	releases, err := c.Releases()
+	if err != nil {
+		return Release{}, err
+	}
+
+	want := "go" + strings.TrimPrefix(strings.TrimPrefix(version, "go"), "v")
+	for _, release := range releases {
+		if release.Version == want {
+			return release, nil
+		}
+	}
+	return Release{}, &errs.VersionNotFoundError{Spec: strings.TrimPrefix(want, "go")}
+}
+
+// Latest returns the newest stable release
+func (c *Client) Latest() (Release, error) {
+	releases, err := c.Releases()
+	if err != nil {
+		return Release{}, err
+	}
+
+	for _, release := range releases {
+		if release.Stable {
+			return release, nil
+		}
+	}
+	return Release{}, fmt.Errorf("no stable release found in index")
+}
+
+// Archive returns the archive file of a release for a platform
+func (r Release) Archive(goos, goarch string) (File, bool) {
+	for _, file := range r.Files {
+		if file.Kind == "archive" && file.OS == goos && file.Arch == goarch {
+			return file, true
+		}
+	}
+	return File{}, false
+}
+
+// Number returns the version without its "go" prefix
+func (r Release) Number() string {
+	return strings.TrimPrefix(r.Version, "go")
+}
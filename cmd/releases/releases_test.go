@@ -0,0 +1,125 @@
+package releases
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testIndex = `[
+  {"version": "go1.23rc1", "stable": false, "files": []},
+  {"version": "go1.22.4", "stable": true, "files": [
+    {"filename": "go1.22.4.linux-amd64.tar.gz", "os": "linux", "arch": "amd64", "version": "go1.22.4",
+     "sha256": "abc123", "size": 42, "kind": "archive"},
+    {"filename": "go1.22.4.src.tar.gz", "os": "", "arch": "", "version": "go1.22.4",
+     "sha256": "def456", "size": 21, "kind": "source"}
+  ]}
+]`
+
+// newTestClient serves testIndex and counts how often it was fetched
+func newTestClient(t *testing.T) (*Client, *httptest.Server, *int32) {
+	t.Helper()
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mode") != "json" || r.URL.Query().Get("include") != "all" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(testIndex))
+	}))
+	t.Cleanup(server.Close)
+
+	client := &Client{
+		BaseURL:   server.URL + "/dl/",
+		IndexURL:  server.URL + "/dl/?mode=json&include=all",
+		CacheFile: filepath.Join(t.TempDir(), "releases.json"),
+		TTL:       time.Hour,
+		HTTP:      server.Client(),
+	}
+	return client, server, &hits
+}
+
+func TestReleases(t *testing.T) {
+	t.Run("decodes typed releases", func(t *testing.T) {
+		client, _, _ := newTestClient(t)
+
+		release, err := client.Find("1.22.4")
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+
+		file, ok := release.Archive("linux", "amd64")
+		if !ok {
+			t.Fatal("expected a linux/amd64 archive")
+		}
+		if file.SHA256 != "abc123" || file.Size != 42 {
+			t.Errorf("unexpected file metadata: %+v", file)
+		}
+	})
+
+	t.Run("latest skips unstable releases", func(t *testing.T) {
+		client, _, _ := newTestClient(t)
+
+		release, err := client.Latest()
+		if err != nil {
+			t.Fatalf("Latest failed: %v", err)
+		}
+		if release.Number() != "1.22.4" {
+			t.Errorf("expected 1.22.4, got %s", release.Number())
+		}
+	})
+
+	t.Run("fresh cache avoids refetching", func(t *testing.T) {
+		client, _, hits := newTestClient(t)
+
+		client.Releases()
+		client.Releases()
+		if *hits != 1 {
+			t.Errorf("expected one fetch, got %d", *hits)
+		}
+	})
+
+	t.Run("stale cache is used when offline", func(t *testing.T) {
+		client, server, _ := newTestClient(t)
+
+		client.Releases()
+		server.Close()
+		client.TTL = 0
+
+		releases, err := client.Releases()
+		if err != nil {
+			t.Fatalf("expected stale cache to be used, got %v", err)
+		}
+		if len(releases) != 2 {
+			t.Errorf("expected 2 releases, got %d", len(releases))
+		}
+	})
+}
+
+func TestDownloadURL(t *testing.T) {
+	file := File{Filename: "go1.22.4.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Version: "go1.22.4"}
+
+	t.Run("base URL", func(t *testing.T) {
+		client := &Client{BaseURL: "https://mirror.example.com/golang"}
+		want := "https://mirror.example.com/golang/go1.22.4.linux-amd64.tar.gz"
+		if got := client.DownloadURL(file); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("URL template", func(t *testing.T) {
+		client := &Client{BaseURL: "https://mirror.example.com/go/{version}/{os}-{arch}/{filename}"}
+		want := "https://mirror.example.com/go/go1.22.4/linux-amd64/go1.22.4.linux-amd64.tar.gz"
+		if got := client.DownloadURL(file); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+		if got := client.indexURL(); got != "https://mirror.example.com/go/?mode=json&include=all" {
+			t.Errorf("unexpected index URL %s", got)
+		}
+	})
+}
@@ -1,67 +1,150 @@
 package reload
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/cristobalcontreras/gos/cmd/backend"
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/session"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewReloadCmd creates the reload command
 func NewReloadCmd() *cobra.Command {
-	return &cobra.Command{
+	var shellName string
+
+	cmd := &cobra.Command{
 		Use:   "reload",
 		Short: "Reload Go environment configuration",
-		Long: `Reload Go environment configuration and verify it's working correctly.
-	
-This command will:
-- Source shell configuration files
-- Verify Go is available in PATH
-- Check GOROOT and GOPATH settings
-- Show current configuration status`,
-		Run: func(cmd *cobra.Command, args []string) {
-			reloadEnvironment()
+		Long: `Reload the gos configuration into the current shell and verify it.
+
+Through the gos shell function from 'gos init', or with --shell, gos prints
+the code that puts the active backend's directories first in PATH, drops a
+session switch made with 'gos use --shell' and clears a stale GOROOT; the
+shell function evaluates it so the calling shell is updated immediately.
+
+Run directly, it checks that Go is available in PATH and shows the current
+GOROOT and GOPATH settings.`,
+		Example: `  gos reload                    # Reload through the gos shell function
+  eval "$(gos reload --shell)"  # Reload without the shell function`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sh, inShell, err := session.EvalShell(shellName, os.Getenv)
+			if err != nil {
+				return &errs.UsageError{Err: err}
+			}
+			if inShell {
+				return reloadShell(sh)
+			}
+			return reloadEnvironment()
 		},
 	}
+
+	cmd.Flags().StringVar(&shellName, "shell", "", "Print the code that reloads the current shell (bash, zsh, fish, pwsh)")
+	cmd.Flags().Lookup("shell").NoOptDefVal = "auto"
+
+	return cmd
 }
 
-// reloadEnvironment reloads and verifies the Go environment
-func reloadEnvironment() {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
+// reloadShell writes the reload code to stdout for the shell to eval; every
+// message goes to stderr
+func reloadShell(sh shell.Shell) error {
+	code, restore := session.DivertOutput()
+	defer restore()
+
+	b, err := backend.Active()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(code, shellCode(sh, b, os.Getenv))
 
-	blue.Println("🔄 Reloading Go environment...")
+	goBinary := filepath.Join(b.GOROOT(""), "bin", "go")
+	if output, err := runner.Output(goBinary, "version"); err == nil {
+		ui.Success.Printf("✅ Reloaded %s: %s\n", b.Name(), strings.TrimSpace(output))
+	} else {
+		ui.Warn.Printf("⚠️  Reloaded %s, but no Go version is active yet\n", b.Name())
+		ui.Hint.Printf("💡 Install one with: gos install latest\n")
+	}
+	return nil
+}
 
-	// Update environment variables and PATH
-	common.SetupGoEnvironment()
+// shellCode returns the code that restores the backend's PATH entries, GOPATH
+// and GOROOT in a shell whose environment getenv reads
+func shellCode(sh shell.Shell, b backend.Backend, getenv func(string) string) string {
+	var out strings.Builder
 
-	// Verify Go installation
-	fmt.Println("")
-	blue.Println("🔍 Verifying Go installation...")
+	gopath := getenv("GOPATH")
+	if gopath == "" {
+		gopath = filepath.Join(common.GetHomeDir(), "go")
+		out.WriteString(sh.Export("GOPATH", gopath) + "\n")
+	}
+
+	// Same order as `gos init`: shims, the backend's bins, then $GOPATH/bin
+	dirs := []string{common.GetShimsDir()}
+	for _, dir := range b.BinDirs() {
+		if dir != common.GetShimsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirs = append(dirs, filepath.Join(gopath, "bin"))
+	out.WriteString(session.Reset(sh, getenv, dirs))
+
+	// Go finds its own GOROOT; one left over from another installation breaks it
+	if goroot := getenv("GOROOT"); goroot != "" && goroot != b.GOROOT("") {
+		out.WriteString(sh.Unset("GOROOT") + "\n")
+	}
+
+	return out.String()
+}
+
+// reloadEnvironment verifies the Go environment of the calling shell
+func reloadEnvironment() error {
+	ui.Info.Println("🔍 Verifying Go environment...")
+
+	b, err := backend.Active()
+	if err != nil {
+		return err
+	}
 
 	if !common.VerifyGoInstallation() {
-		return
+		showReloadInstructions()
+		return errors.New("go was not found in PATH")
 	}
 
 	// Verify GOROOT and GOPATH
-	common.VerifyGoEnvironmentPaths()
+	common.VerifyGoEnvironmentPaths(b.GOROOT(""))
+
+	ui.Note.Println("")
+	ui.Success.Println("🎉 Environment check complete!")
+	showReloadInstructions()
 
-	fmt.Println("")
-	green.Println("🎉 Environment reload complete!")
-	
 	// Show helpful commands
 	showUsefulCommands()
+	return nil
+}
+
+// showReloadInstructions explains how to load the configuration into the shell
+func showReloadInstructions() {
+	ui.Note.Println("")
+	ui.Hint.Println("💡 gos cannot change this shell's environment on its own. To reload it:")
+	ui.Hint.Println("   with the gos shell function from 'gos init', run: gos reload")
+	ui.Hint.Println(`   otherwise run: eval "$(gos reload --shell)"`)
 }
 
 // showUsefulCommands displays helpful commands to the user
 func showUsefulCommands() {
-	blue := color.New(color.FgBlue)
-	
-	fmt.Println("")
-	blue.Println("💡 Useful commands:")
-	fmt.Println("  gos status        # Check overall status")
-	fmt.Println("  gos env           # Show detailed environment")
-	fmt.Println("  gos list          # List installed Go versions")
-	fmt.Println("  go version        # Verify active Go version")
+	ui.Note.Println("")
+	ui.Info.Println("💡 Useful commands:")
+	ui.Note.Println("  gos status        # Check overall status")
+	ui.Note.Println("  gos env           # Show detailed environment")
+	ui.Note.Println("  gos list          # List installed Go versions")
+	ui.Note.Println("  go version        # Verify active Go version")
 }
@@ -2,11 +2,14 @@ package remove
 
 import (
 	"fmt"
-	"os/exec"
+	"strings"
 	"time"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 )
@@ -16,29 +19,33 @@ func NewRemoveCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "remove [version]",
 		Short:   "Remove a specific Go version",
-		Long:    `Remove a specific Go version that has been installed via any available version manager.`,
+		Long:    `Remove a specific Go version that has been installed through the active backend.`,
 		Example: `  gos remove 1.20.10    # Remove Go 1.20.10`,
 		Args:    cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			if !common.CheckVersionManagerAvailable() {
-				return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := backend.Active()
+			if err != nil {
+				return err
 			}
-			removeVersion(args[0])
+			return removeVersion(b, args[0])
 		},
 	}
 }
 
 // removeVersion removes a specific Go version
-func removeVersion(version string) {
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
+func removeVersion(b backend.Backend, version string) error {
+	resolved, err := resolveRemoval(b, version)
+	if err != nil {
+		return err
+	}
+	version = resolved
 
-	yellow.Printf("🗑️  Removing Go %s...\n", version)
+	ui.Hint.Printf("🗑️  Removing Go %s...\n", version)
 
 	// Create progress bar for removal
 	bar := progressbar.NewOptions(-1,
 		progressbar.OptionSetDescription(fmt.Sprintf("Removing Go %s", version)),
+		progressbar.OptionSetWriter(ui.Progress()),
 		progressbar.OptionSetPredictTime(false),
 		progressbar.OptionSpinnerType(14),
 		progressbar.OptionSetTheme(progressbar.Theme{
@@ -60,25 +67,55 @@ func removeVersion(version string) {
 		}
 	}()
 
-	var cmd *exec.Cmd
-
-	if common.IsCommandAvailable("gobrew") {
-		cmd = exec.Command("gobrew", "uninstall", version)
-	} else {
+	if err := b.Uninstall(version); err != nil {
 		done <- true
 		bar.Finish()
-		red.Println("❌ No version manager available")
-		return
-	}
-
-	if err := cmd.Run(); err != nil {
-		done <- true
-		bar.Finish()
-		red.Printf("❌ Error removing Go %s\n", version)
-		return
+		return fmt.Errorf("removing Go %s: %w", version, err)
 	}
 
 	done <- true
 	bar.Finish()
-	green.Printf("✅ Go %s removed successfully\n", version)
+	ui.Success.Printf("✅ Go %s removed successfully\n", version)
+
+	shim.Refresh(b)
+	return nil
+}
+
+// resolveRemoval maps a version spec to exactly one installed version. Ranges
+// and partial versions are accepted only when they match a single version, so
+// "gos remove 1.21" never picks one of several 1.21.x installs by itself.
+func resolveRemoval(b backend.Backend, spec string) (string, error) {
+	if native, ok := b.(*backend.Native); ok {
+		if _, linked := native.Installer.LinkTarget(spec); linked {
+			return "", &errs.UsageError{Err: fmt.Errorf("%s is a linked toolchain; remove the registration with: gos unlink %s", spec, spec)}
+		}
+	}
+	constraint, err := goversion.ParseConstraint(spec)
+	if err != nil {
+		return "", &errs.UsageError{Err: err}
+	}
+	if constraint.IsExact() {
+		return backend.ResolveInstalled(b, spec)
+	}
+
+	installed, err := b.ListInstalled()
+	if err != nil {
+		return "", fmt.Errorf("listing installed versions: %w", err)
+	}
+
+	var matches []string
+	for _, version := range installed {
+		if constraint.Matches(version) {
+			matches = append(matches, version)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", &errs.VersionNotFoundError{Spec: spec, Installed: true}
+	case 1:
+		return matches[0], nil
+	}
+	goversion.Sort(matches)
+	return "", &errs.UsageError{Err: fmt.Errorf("%q matches several installed versions (%s); name one exactly", spec, strings.Join(matches, ", "))}
 }
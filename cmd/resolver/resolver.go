@@ -0,0 +1,210 @@
+package resolver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+)
+
+// Sources a version can come from, in precedence order. Within one directory
+// the files are checked in this order; nearer directories win over parents.
+const (
+	SourceEnv            = "GOS_VERSION"
+	SourceGoVersion      = ".go-version"
+	SourceToolVersions   = ".tool-versions"
+	SourceGoModToolchain = "go.mod toolchain"
+	SourceGoModGo        = "go.mod go"
+	SourceDefault        = "global default"
+)
+
+// EnvVar overrides every file-based setting when set
+const EnvVar = "GOS_VERSION"
+
+// Step is one place the resolver found a version setting
+type Step struct {
+	Source  string // one of the Source constants
+	Path    string // file the version was read from; empty for the environment
+	Version string // version spec as written in the source
+	Note    string // why the step was selected or skipped
+}
+
+// Result describes which version applies to a directory and why
+type Result struct {
+	Dir      string // directory the resolution started from
+	Version  string // winning version spec; empty when nothing is configured
+	Source   string // source of the winning spec
+	Path     string // file of the winning spec
+	Steps    []Step // every setting found, winner first
+	Searched int    // number of directories walked
+}
+
+// Found reports whether any source configured a version
+func (r Result) Found() bool {
+	return r.Version != ""
+}
+
+// DefaultFile returns the file holding the global default version
+func DefaultFile() string {
+	return filepath.Join(common.GetHomeDir(), common.DefaultVersionFile)
+}
+
+// Resolve determines the Go version for dir. It checks GOS_VERSION, then
+// walks from dir up to the filesystem root looking at .go-version,
+// .tool-versions (golang entry) and go.mod (toolchain, then go directive),
+// and finally falls back to the global default written by `gos default`.
+func Resolve(dir string) (Result, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Dir: dir}
+
+	if version := strings.TrimSpace(os.Getenv(EnvVar)); version != "" {
+		result.add(Step{Source: SourceEnv, Version: version})
+	}
+
+	for current := dir; ; current = filepath.Dir(current) {
+		result.Searched++
+		for _, step := range scanDir(current) {
+			result.add(step)
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	if version := readVersionFile(DefaultFile()); version != "" {
+		result.add(Step{Source: SourceDefault, Path: DefaultFile(), Version: version})
+	}
+
+	return result, nil
+}
+
+// add records a step; the first one becomes the winner, later ones are shadowed by it
+func (r *Result) add(step Step) {
+	if r.Version == "" {
+		r.Version, r.Source, r.Path = step.Version, step.Source, step.Path
+		step.Note = selectedNote(step)
+	} else {
+		step.Note = "shadowed by " + r.describeWinner()
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+// describeWinner names the winning source for shadowing notes
+func (r *Result) describeWinner() string {
+	if r.Path == "" {
+		return r.Source
+	}
+	return r.Path
+}
+
+// selectedNote explains why a step won
+func selectedNote(step Step) string {
+	switch step.Source {
+	case SourceEnv:
+		return "GOS_VERSION is set and overrides every file"
+	case SourceDefault:
+		return "no project setting found; using the global default"
+	default:
+		return fmt.Sprintf("nearest %s walking up from the current directory", step.Source)
+	}
+}
+
+// scanDir returns the version settings in one directory, in precedence order
+func scanDir(dir string) []Step {
+	var steps []Step
+
+	goVersion := filepath.Join(dir, ".go-version")
+	if version := readVersionFile(goVersion); version != "" {
+		steps = append(steps, Step{Source: SourceGoVersion, Path: goVersion, Version: version})
+	}
+
+	toolVersions := filepath.Join(dir, ".tool-versions")
+	if version := readToolVersions(toolVersions); version != "" {
+		steps = append(steps, Step{Source: SourceToolVersions, Path: toolVersions, Version: version})
+	}
+
+	goMod := filepath.Join(dir, "go.mod")
+	toolchain, goDirective := readGoMod(goMod)
+	if toolchain != "" {
+		steps = append(steps, Step{Source: SourceGoModToolchain, Path: goMod, Version: toolchain})
+	}
+	if goDirective != "" {
+		// The go directive is a minimum, so any newer toolchain satisfies it
+		steps = append(steps, Step{Source: SourceGoModGo, Path: goMod, Version: ">=" + goDirective})
+	}
+
+	return steps
+}
+
+// readVersionFile returns the first non-comment line of a version file
+func readVersionFile(path string) string {
+	content, err := fsys.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// readToolVersions returns the golang entry of an asdf/mise .tool-versions file
+func readToolVersions(path string) string {
+	content, err := fsys.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && (fields[0] == "golang" || fields[0] == "go") {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// readGoMod returns the toolchain and go directives of a go.mod file
+func readGoMod(path string) (toolchain, goDirective string) {
+	content, err := fsys.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "toolchain":
+			if fields[1] != "default" {
+				toolchain = strings.TrimPrefix(fields[1], "go")
+			}
+		case "go":
+			goDirective = fields[1]
+		}
+	}
+	return toolchain, goDirective
+}
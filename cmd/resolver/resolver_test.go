@@ -0,0 +1,83 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+)
+
+// writeFile creates a file with content, creating parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(common.HomeVar, home)
+	t.Setenv(EnvVar, "")
+
+	root := t.TempDir()
+	service := filepath.Join(root, "services", "api")
+	writeFile(t, filepath.Join(root, ".go-version"), "1.21.5\n")
+	writeFile(t, filepath.Join(service, "go.mod"), "module example.com/api\n\ngo 1.22.0\n\ntoolchain go1.22.3 // pinned\n")
+
+	t.Run("nearest directory wins", func(t *testing.T) {
+		result, err := Resolve(service)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if result.Version != "1.22.3" || result.Source != SourceGoModToolchain {
+			t.Errorf("expected go.mod toolchain 1.22.3, got %s from %s", result.Version, result.Source)
+		}
+		if len(result.Steps) != 3 {
+			t.Errorf("expected toolchain, go directive and .go-version steps, got %+v", result.Steps)
+		}
+	})
+
+	t.Run("walks up to parent directories", func(t *testing.T) {
+		result, _ := Resolve(filepath.Join(root, "services"))
+		if result.Version != "1.21.5" || result.Path != filepath.Join(root, ".go-version") {
+			t.Errorf("expected root .go-version, got %s from %s", result.Version, result.Path)
+		}
+	})
+
+	t.Run("go-version beats tool-versions in the same directory", func(t *testing.T) {
+		dir := filepath.Join(root, "tools")
+		writeFile(t, filepath.Join(dir, ".tool-versions"), "nodejs 20.1.0\ngolang 1.20.14\n")
+		writeFile(t, filepath.Join(dir, ".go-version"), "go1.20.13")
+
+		result, _ := Resolve(dir)
+		if result.Version != "go1.20.13" || result.Source != SourceGoVersion {
+			t.Errorf("expected .go-version, got %s from %s", result.Version, result.Source)
+		}
+		if result.Steps[1].Source != SourceToolVersions || result.Steps[1].Version != "1.20.14" {
+			t.Errorf("expected shadowed .tool-versions entry, got %+v", result.Steps[1])
+		}
+	})
+
+	t.Run("environment overrides files", func(t *testing.T) {
+		t.Setenv(EnvVar, "1.19")
+
+		result, _ := Resolve(service)
+		if result.Version != "1.19" || result.Source != SourceEnv {
+			t.Errorf("expected GOS_VERSION, got %s from %s", result.Version, result.Source)
+		}
+	})
+
+	t.Run("global default is the fallback", func(t *testing.T) {
+		writeFile(t, filepath.Join(home, ".gos-default"), "1.22.6")
+
+		result, _ := Resolve(t.TempDir())
+		if result.Version != "1.22.6" || result.Source != SourceDefault {
+			t.Errorf("expected global default, got %s from %s", result.Version, result.Source)
+		}
+	})
+}
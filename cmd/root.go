@@ -1,22 +1,38 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/cristobalcontreras/gos/cmd/clean"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/current"
 	defaultcmd "github.com/cristobalcontreras/gos/cmd/default"
+	"github.com/cristobalcontreras/gos/cmd/doctor"
 	"github.com/cristobalcontreras/gos/cmd/env"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	execcmd "github.com/cristobalcontreras/gos/cmd/exec"
+	importcmd "github.com/cristobalcontreras/gos/cmd/import"
+	initcmd "github.com/cristobalcontreras/gos/cmd/init"
 	"github.com/cristobalcontreras/gos/cmd/install"
 	"github.com/cristobalcontreras/gos/cmd/latest"
+	"github.com/cristobalcontreras/gos/cmd/link"
 	"github.com/cristobalcontreras/gos/cmd/list"
+	"github.com/cristobalcontreras/gos/cmd/output"
 	"github.com/cristobalcontreras/gos/cmd/project"
+	"github.com/cristobalcontreras/gos/cmd/prune"
 	"github.com/cristobalcontreras/gos/cmd/reload"
 	"github.com/cristobalcontreras/gos/cmd/remove"
+	"github.com/cristobalcontreras/gos/cmd/session"
 	"github.com/cristobalcontreras/gos/cmd/setup"
+	"github.com/cristobalcontreras/gos/cmd/shim"
 	"github.com/cristobalcontreras/gos/cmd/status"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/cristobalcontreras/gos/cmd/use"
 	versioncmd "github.com/cristobalcontreras/gos/cmd/version"
+	"github.com/cristobalcontreras/gos/cmd/which"
 	"github.com/spf13/cobra"
 )
 
@@ -31,21 +47,54 @@ var rootCmd = &cobra.Command{
 	Use:   "gos",
 	Short: "A comprehensive Go version manager CLI",
 	Long: `GOS is a powerful command-line tool for managing Go versions.
-It provides functionality to install, switch, and manage multiple Go versions
-using gobrew, along with comprehensive cleanup capabilities.
+It downloads official Go toolchains into ~/.gos and lets you install, switch,
+and manage multiple Go versions (gobrew remains available as an optional
+backend), along with comprehensive cleanup capabilities.
 
 Features:
 - Install and switch between Go versions
-- Setup gobrew version manager
+- Self-contained toolchain downloads (no external version manager needed)
 - Deep clean Go installations
 - Project-specific version management
 - System status and diagnostics`,
 	Example: `  gos install 1.21.5     # Install Go 1.21.5
   gos use 1.21.5          # Switch to Go 1.21.5
-  gos setup               # Setup gobrew version manager
+  gos setup               # Configure gos and install latest Go
   gos clean               # Deep clean Go installations
   gos status              # Show system status`,
 	Version: getVersionString(),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if display.Quiet && display.Verbose {
+			return &errs.UsageError{Err: errors.New("--quiet and --verbose cannot be used together")}
+		}
+		if display.AssumeYes && display.NoInput {
+			return &errs.UsageError{Err: errors.New("--yes and --no-input cannot be used together")}
+		}
+		ui.Configure(display)
+
+		if homeDir != "" {
+			abs, err := filepath.Abs(homeDir)
+			if err != nil {
+				return &errs.UsageError{Err: fmt.Errorf("--home: %w", err)}
+			}
+			// Exported so that every package and child process sees the same home
+			os.Setenv(common.HomeVar, abs)
+		}
+
+		format, err := output.Parse(outputFormat)
+		if err != nil {
+			return &errs.UsageError{Err: err}
+		}
+		if format != output.Text && !output.Supported(cmd) {
+			return &errs.UsageError{Err: fmt.Errorf("%s does not support --output %s", cmd.CommandPath(), format)}
+		}
+		output.Set(format)
+
+		started = true
+		return nil
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 // SetVersionInfo sets the version information
@@ -63,18 +112,61 @@ func getVersionString() string {
 	return fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
 }
 
+// started is set once a command passed argument validation and began to run
+var started bool
+
+// outputFormat is the value of the --output flag
+var outputFormat string
+
+// homeDir is the value of the --home flag
+var homeDir string
+
+// display holds the --no-color, --plain, --quiet, --verbose, --yes and --no-input flags
+var display ui.Options
+
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Errors are printed once here and mapped to the exit codes defined in errs.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	// The gos shell function evaluates stdout, so help and usage go to stderr
+	if os.Getenv(session.EvalVar) != "" {
+		rootCmd.SetOut(os.Stderr)
 	}
+
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	// Errors raised before the command ran come from cobra's flag and argument checks
+	if !started {
+		err = &errs.UsageError{Err: err}
+	}
+	ui.Error.Printf("❌ Error: %v\n", err)
+	if !started {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	os.Exit(errs.Code(err))
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(output.Text),
+		"Output format for status, list, env --check, default and version: text, json or yaml")
+	rootCmd.PersistentFlags().StringVar(&homeDir, "home", "", "Use this directory as the home directory (also set by GOS_HOME)")
+	rootCmd.PersistentFlags().BoolVar(&display.NoColor, "no-color", false, "Disable colors (also set by NO_COLOR or TERM=dumb)")
+	rootCmd.PersistentFlags().BoolVar(&display.Plain, "plain", false, "Print without emoji")
+	rootCmd.PersistentFlags().BoolVarP(&display.Quiet, "quiet", "q", false, "Only print results, warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&display.Verbose, "verbose", false, "Also print debug details to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&display.AssumeYes, "yes", "y", false, "Answer yes to every prompt (also set by GOS_ASSUME_YES=1)")
+	rootCmd.PersistentFlags().BoolVar(&display.NoInput, "no-input", false, "Never prompt; take each prompt's default answer")
+
 	rootCmd.AddCommand(install.NewInstallCmd())
+	rootCmd.AddCommand(importcmd.NewImportCmd())
+	rootCmd.AddCommand(link.NewLinkCmd())
+	rootCmd.AddCommand(link.NewUnlinkCmd())
 	rootCmd.AddCommand(use.NewUseCmd())
 	rootCmd.AddCommand(list.NewListCmd())
 	rootCmd.AddCommand(remove.NewRemoveCmd())
+	rootCmd.AddCommand(prune.NewPruneCmd())
 	rootCmd.AddCommand(clean.NewCleanCmd())
 	rootCmd.AddCommand(setup.NewSetupCmd())
 	rootCmd.AddCommand(status.CreateStatusCommand())
@@ -83,5 +175,12 @@ func init() {
 	rootCmd.AddCommand(reload.NewReloadCmd())
 	rootCmd.AddCommand(defaultcmd.CreateDefaultCommand())
 	rootCmd.AddCommand(env.CreateEnvCommand())
+	rootCmd.AddCommand(doctor.NewDoctorCmd())
+	rootCmd.AddCommand(which.NewWhichCmd())
 	rootCmd.AddCommand(versioncmd.NewVersionCmd())
+	rootCmd.AddCommand(current.NewCurrentCmd())
+	rootCmd.AddCommand(shim.NewRehashCmd())
+	rootCmd.AddCommand(execcmd.NewExecCmd())
+	rootCmd.AddCommand(initcmd.NewInitCmd())
+	rootCmd.AddCommand(initcmd.NewHookEnvCmd())
 }
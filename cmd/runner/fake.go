@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Fake is a Runner for tests: it records every command and answers with the
+// responses scripted for its command line
+type Fake struct {
+	mu        sync.Mutex
+	calls     []Command
+	responses map[string]Response
+	paths     map[string]string
+}
+
+// Response is what a Fake returns for a scripted command
+type Response struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int   // non-zero makes Run fail with *Error
+	Err      error // fails Run as if the command could not be started
+}
+
+// NewFake creates a Fake that knows no commands
+func NewFake() *Fake {
+	return &Fake{responses: map[string]Response{}, paths: map[string]string{}}
+}
+
+// On scripts the response to a command line such as "gobrew ls"; the
+// program is then also found by LookPath
+func (f *Fake) On(line string, response Response) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.responses[line] = response
+	name, _, _ := strings.Cut(line, " ")
+	if _, ok := f.paths[name]; !ok {
+		f.paths[name] = path.Join("/fake/bin", name)
+	}
+	return f
+}
+
+// Path makes LookPath find name at p without scripting a command
+func (f *Fake) Path(name, p string) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths[name] = p
+	return f
+}
+
+// Run implements Runner. Commands that were not scripted fail as if the
+// program did not exist.
+func (f *Fake) Run(ctx context.Context, cmd Command) (Result, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, cmd)
+	response, ok := f.responses[cmd.String()]
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return Result{ExitCode: -1}, newError(cmd, Result{}, err)
+	}
+	if !ok {
+		return Result{ExitCode: -1}, newError(cmd, Result{}, exec.ErrNotFound)
+	}
+	if response.Err != nil {
+		return Result{ExitCode: -1}, newError(cmd, Result{}, response.Err)
+	}
+
+	if cmd.Stdout != nil {
+		io.WriteString(cmd.Stdout, response.Stdout)
+	}
+	if cmd.Stderr != nil {
+		io.WriteString(cmd.Stderr, response.Stderr)
+	}
+	result := Result{Stdout: response.Stdout, Stderr: response.Stderr, ExitCode: response.ExitCode}
+	if response.ExitCode != 0 {
+		return result, newError(cmd, result, fmt.Errorf("exit status %d", response.ExitCode))
+	}
+	return result, nil
+}
+
+// LookPath implements Runner
+func (f *Fake) LookPath(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, ok := f.paths[name]; ok {
+		return p, nil
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// Calls returns the commands run so far
+func (f *Fake) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Command(nil), f.calls...)
+}
+
+// Lines returns the command lines run so far, e.g. "gobrew use 1.22.1"
+func (f *Fake) Lines() []string {
+	var lines []string
+	for _, call := range f.Calls() {
+		lines = append(lines, call.String())
+	}
+	return lines
+}
@@ -0,0 +1,166 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Runner starts external programs such as go, gobrew, brew and the shell
+type Runner interface {
+	// Run executes cmd, capturing its output, and fails with *Error when it
+	// cannot be started or exits non-zero
+	Run(ctx context.Context, cmd Command) (Result, error)
+	// LookPath finds an executable in PATH like exec.LookPath
+	LookPath(name string) (string, error)
+}
+
+// Command is one invocation of an external program
+type Command struct {
+	Name  string
+	Args  []string
+	Env   []string  // KEY=VALUE entries added to the inherited environment
+	Dir   string    // working directory; empty for the current one
+	Stdin io.Reader // nil for no input
+
+	// Stdout and Stderr, when set, also receive the output as it is produced
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// String renders the command line, e.g. "gobrew install 1.22.1"
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Result is the captured output of a command that ran
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Error reports a command that could not be started or exited non-zero
+type Error struct {
+	Command  string
+	ExitCode int    // -1 when the command did not start
+	Output   string // trimmed stderr, or stdout when the command wrote no errors
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.Output != "" {
+		return fmt.Sprintf("%s: %v: %s", e.Command, e.Err, e.Output)
+	}
+	return fmt.Sprintf("%s: %v", e.Command, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Default is the runner gos uses; tests swap it for a Fake with Replace
+var Default Runner = Exec{}
+
+// Replace makes r the Default runner until restore is called
+func Replace(r Runner) (restore func()) {
+	previous := Default
+	Default = r
+	return func() { Default = previous }
+}
+
+// Exec runs commands as child processes
+type Exec struct{}
+
+// Run implements Runner
+func (Exec) Run(ctx context.Context, cmd Command) (Result, error) {
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	if env := append(sandboxEnv(), cmd.Env...); len(env) > 0 {
+		c.Env = append(os.Environ(), env...)
+	}
+	c.Dir = cmd.Dir
+	c.Stdin = cmd.Stdin
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = tee(&stdout, cmd.Stdout)
+	c.Stderr = tee(&stderr, cmd.Stderr)
+
+	err := c.Run()
+	result := Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: -1}
+	if c.ProcessState != nil {
+		result.ExitCode = c.ProcessState.ExitCode()
+	}
+	if err != nil {
+		return result, newError(cmd, result, err)
+	}
+	return result, nil
+}
+
+// sandboxEnv points the home directory of child processes at GOS_HOME when
+// it is set, so go clean, gobrew and g stay inside the sandbox as well. The
+// Go module and build caches follow, since go clean -modcache would
+// otherwise empty the real ones.
+func sandboxEnv() []string {
+	home := os.Getenv("GOS_HOME") // common.HomeVar; common imports runner
+	if home == "" {
+		return nil
+	}
+	env := []string{"GOPATH=" + filepath.Join(home, "go"), "GOMODCACHE=", "GOCACHE="}
+	if runtime.GOOS == "windows" {
+		return append(env,
+			"USERPROFILE="+home,
+			"LOCALAPPDATA="+filepath.Join(home, "AppData", "Local"),
+			"APPDATA="+filepath.Join(home, "AppData", "Roaming"))
+	}
+	return append(env, "HOME="+home, "XDG_CACHE_HOME=", "XDG_CONFIG_HOME=")
+}
+
+// newError describes a failed command with the output that explains it
+func newError(cmd Command, result Result, err error) *Error {
+	output := strings.TrimSpace(result.Stderr)
+	if output == "" {
+		output = strings.TrimSpace(result.Stdout)
+	}
+	return &Error{Command: cmd.String(), ExitCode: result.ExitCode, Output: output, Err: err}
+}
+
+// LookPath implements Runner
+func (Exec) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// tee writes to the capture buffer and, when set, to w as well
+func tee(capture *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return capture
+	}
+	return io.MultiWriter(capture, w)
+}
+
+// Output runs a command with the Default runner and returns its stdout
+func Output(name string, args ...string) (string, error) {
+	result, err := Default.Run(context.Background(), Command{Name: name, Args: args})
+	return result.Stdout, err
+}
+
+// Run runs a command with the Default runner, discarding its output; the
+// error includes what the command wrote to stderr
+func Run(name string, args ...string) error {
+	_, err := Default.Run(context.Background(), Command{Name: name, Args: args})
+	return err
+}
+
+// LookPath finds an executable with the Default runner
+func LookPath(name string) (string, error) {
+	return Default.LookPath(name)
+}
+
+// Available reports whether an executable is in PATH
+func Available(name string) bool {
+	_, err := Default.LookPath(name)
+	return err == nil
+}
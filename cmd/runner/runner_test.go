@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not in PATH")
+	}
+
+	t.Run("captures stdout", func(t *testing.T) {
+		var streamed strings.Builder
+		result, err := Exec{}.Run(context.Background(), Command{
+			Name:   "go",
+			Args:   []string{"env", "GOOS"},
+			Stdout: &streamed,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(result.Stdout); got != runtime.GOOS {
+			t.Errorf("Stdout = %q, want %q", got, runtime.GOOS)
+		}
+		if streamed.String() != result.Stdout {
+			t.Errorf("streamed %q, captured %q", streamed.String(), result.Stdout)
+		}
+	})
+
+	t.Run("environment is added", func(t *testing.T) {
+		result, err := Exec{}.Run(context.Background(), Command{
+			Name: "go",
+			Args: []string{"env", "GOFLAGS"},
+			Env:  []string{"GOFLAGS=-mod=mod"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(result.Stdout); got != "-mod=mod" {
+			t.Errorf("GOFLAGS = %q", got)
+		}
+	})
+
+	t.Run("GOS_HOME is the home of child processes", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("GOS_HOME", home)
+		t.Setenv("GOPATH", filepath.Join(t.TempDir(), "real"))
+
+		result, err := Exec{}.Run(context.Background(), Command{Name: "go", Args: []string{"env", "GOPATH", "GOMODCACHE"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := filepath.Join(home, "go") + "\n" + filepath.Join(home, "go", "pkg", "mod")
+		if got := strings.TrimSpace(result.Stdout); got != want {
+			t.Errorf("go env = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("failure carries the output", func(t *testing.T) {
+		result, err := Exec{}.Run(context.Background(), Command{Name: "go", Args: []string{"no-such-command"}})
+
+		var runErr *Error
+		if !errors.As(err, &runErr) {
+			t.Fatalf("Run() error = %v, want *Error", err)
+		}
+		if result.ExitCode == 0 || runErr.ExitCode != result.ExitCode {
+			t.Errorf("exit codes: result %d, error %d", result.ExitCode, runErr.ExitCode)
+		}
+		if !strings.Contains(err.Error(), "go no-such-command") || runErr.Output == "" {
+			t.Errorf("Error() = %q", err)
+		}
+	})
+
+	t.Run("missing program", func(t *testing.T) {
+		_, err := Exec{}.Run(context.Background(), Command{Name: "gos-no-such-program"})
+		if !errors.Is(err, exec.ErrNotFound) {
+			t.Errorf("Run() error = %v, want exec.ErrNotFound", err)
+		}
+	})
+}
+
+func TestFake(t *testing.T) {
+	t.Run("scripted output", func(t *testing.T) {
+		fake := NewFake().On("gobrew ls", Response{Stdout: "1.22.1*\n"})
+		defer Replace(fake)()
+
+		got, err := Output("gobrew", "ls")
+		if err != nil || got != "1.22.1*\n" {
+			t.Errorf("Output() = %q, %v", got, err)
+		}
+		if !Available("gobrew") {
+			t.Error("scripted program should be found in PATH")
+		}
+		if Available("brew") {
+			t.Error("unscripted program should not be found in PATH")
+		}
+	})
+
+	t.Run("non-zero exit", func(t *testing.T) {
+		fake := NewFake().On("gobrew install 9.9.9", Response{Stderr: "version not found\n", ExitCode: 1})
+		defer Replace(fake)()
+
+		err := Run("gobrew", "install", "9.9.9")
+		want := "gobrew install 9.9.9: exit status 1: version not found"
+		if err == nil || err.Error() != want {
+			t.Errorf("Run() error = %v, want %q", err, want)
+		}
+	})
+
+	t.Run("unscripted command", func(t *testing.T) {
+		defer Replace(NewFake())()
+
+		if err := Run("go", "version"); !errors.Is(err, exec.ErrNotFound) {
+			t.Errorf("Run() error = %v, want exec.ErrNotFound", err)
+		}
+	})
+
+	t.Run("records calls", func(t *testing.T) {
+		fake := NewFake().On("go version", Response{})
+		defer Replace(fake)()
+
+		Run("go", "version")
+		Run("gobrew", "use", "1.22.1")
+
+		want := []string{"go version", "gobrew use 1.22.1"}
+		if got := fake.Lines(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Lines() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("replace restores the previous runner", func(t *testing.T) {
+		previous := Default
+		restore := Replace(NewFake())
+		restore()
+		if Default != previous {
+			t.Error("Default was not restored")
+		}
+	})
+}
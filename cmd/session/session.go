@@ -0,0 +1,92 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/fatih/color"
+)
+
+// BinVar records the bin directory `gos use --shell` put first in PATH, so a
+// later switch or reload can take it out again
+const BinVar = "__GOS_SESSION_BIN"
+
+// EvalVar is set by the gos shell function when it evaluates gos's output; it
+// names the shell the code has to be written for
+const EvalVar = "GOS_SHELL"
+
+// EvalShell returns the shell to write code for: the --shell flag value when
+// one was given ("auto" detects it), else the shell named by GOS_SHELL. ok is
+// false when gos is not being evaluated.
+func EvalShell(flag string, getenv func(string) string) (sh shell.Shell, ok bool, err error) {
+	switch {
+	case flag != "" && flag != "auto":
+		sh, err = shell.Parse(flag)
+	case getenv(EvalVar) != "":
+		sh, err = shell.Parse(getenv(EvalVar))
+	case flag == "auto":
+		sh, err = shell.Detect()
+	default:
+		return "", false, nil
+	}
+	return sh, err == nil, err
+}
+
+// Use returns the code that makes version the session's Go: its bin directory
+// first in PATH in place of any earlier session switch, and GOS_VERSION set so
+// shims and the auto-switch hook pick the same version
+func Use(sh shell.Shell, getenv func(string) string, version, bin string) string {
+	paths := RemoveEntry(filepath.SplitList(getenv("PATH")), getenv(BinVar))
+	paths = append([]string{bin}, RemoveEntry(paths, bin)...)
+
+	return strings.Join([]string{
+		sh.SetPath(paths),
+		sh.Export(BinVar, bin),
+		sh.Export(resolver.EnvVar, version),
+	}, "\n") + "\n"
+}
+
+// Reset returns the code that drops a session switch and puts dirs (the
+// active backend's PATH entries) first in PATH, each once
+func Reset(sh shell.Shell, getenv func(string) string, dirs []string) string {
+	var out strings.Builder
+
+	paths := RemoveEntry(filepath.SplitList(getenv("PATH")), getenv(BinVar))
+	for i := len(dirs) - 1; i >= 0; i-- {
+		paths = append([]string{dirs[i]}, RemoveEntry(paths, dirs[i])...)
+	}
+	if strings.Join(paths, string(os.PathListSeparator)) != getenv("PATH") {
+		out.WriteString(sh.SetPath(paths) + "\n")
+	}
+
+	for _, key := range []string{BinVar, resolver.EnvVar} {
+		if getenv(key) != "" {
+			out.WriteString(sh.Unset(key) + "\n")
+		}
+	}
+	return out.String()
+}
+
+// RemoveEntry returns paths with the first occurrence of entry removed
+func RemoveEntry(paths []string, entry string) []string {
+	if entry == "" {
+		return paths
+	}
+	for i, dir := range paths {
+		if dir == entry {
+			return append(append([]string{}, paths[:i]...), paths[i+1:]...)
+		}
+	}
+	return paths
+}
+
+// DivertOutput sends everything gos prints to stderr, so that only shell code
+// written to the returned file reaches the evaluating shell. restore undoes it.
+func DivertOutput() (code *os.File, restore func()) {
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = os.Stderr, os.Stderr
+	return stdout, func() { os.Stdout, color.Output = stdout, colorOutput }
+}
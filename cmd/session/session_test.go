@@ -0,0 +1,95 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/shell"
+)
+
+func TestEvalShell(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	tests := []struct {
+		name   string
+		flag   string
+		vars   map[string]string
+		want   shell.Shell
+		wantOK bool
+	}{
+		{name: "explicit flag", flag: "fish", want: shell.Fish, wantOK: true},
+		{name: "flag wins over the shell function", flag: "zsh", vars: map[string]string{EvalVar: "bash"}, want: shell.Zsh, wantOK: true},
+		{name: "shell function", flag: "auto", vars: map[string]string{EvalVar: "pwsh"}, want: shell.Pwsh, wantOK: true},
+		{name: "shell function without flag", vars: map[string]string{EvalVar: "bash"}, want: shell.Bash, wantOK: true},
+		{name: "not evaluated", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := EvalShell(tt.flag, env(tt.vars))
+			if err != nil || ok != tt.wantOK || got != tt.want {
+				t.Errorf("EvalShell(%q) = %q, %v, %v; want %q, %v", tt.flag, got, ok, err, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("unknown shell", func(t *testing.T) {
+		if _, ok, err := EvalShell("tcsh", env(nil)); ok || err == nil {
+			t.Error("expected an error for an unsupported shell")
+		}
+	})
+}
+
+func TestUseAndReset(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	vars := map[string]string{"PATH": strings.Join([]string{"/shims", "/usr/bin"}, sep)}
+	getenv := func(key string) string { return vars[key] }
+
+	t.Run("use puts the version first", func(t *testing.T) {
+		got := Use(shell.Bash, getenv, "1.21.5", "/versions/1.21.5/bin")
+		for _, want := range []string{
+			shell.Bash.SetPath([]string{"/versions/1.21.5/bin", "/shims", "/usr/bin"}),
+			shell.Bash.Export(BinVar, "/versions/1.21.5/bin"),
+			shell.Bash.Export("GOS_VERSION", "1.21.5"),
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in:\n%s", want, got)
+			}
+		}
+	})
+
+	// The shell after evaluating the switch above
+	vars["PATH"] = strings.Join([]string{"/versions/1.21.5/bin", "/shims", "/usr/bin"}, sep)
+	vars[BinVar] = "/versions/1.21.5/bin"
+	vars["GOS_VERSION"] = "1.21.5"
+
+	t.Run("switching again replaces the earlier version", func(t *testing.T) {
+		got := Use(shell.Fish, getenv, "1.22.3", "/versions/1.22.3/bin")
+		if want := shell.Fish.SetPath([]string{"/versions/1.22.3/bin", "/shims", "/usr/bin"}); !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	})
+
+	t.Run("reset drops the session switch", func(t *testing.T) {
+		got := Reset(shell.Bash, getenv, []string{"/shims", "/home/go/bin"})
+		for _, want := range []string{
+			shell.Bash.SetPath([]string{"/shims", "/home/go/bin", "/usr/bin"}),
+			shell.Bash.Unset(BinVar),
+			shell.Bash.Unset("GOS_VERSION"),
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("reset of a clean session changes nothing", func(t *testing.T) {
+		clean := map[string]string{"PATH": strings.Join([]string{"/shims", "/usr/bin"}, sep)}
+		if got := Reset(shell.Zsh, func(key string) string { return clean[key] }, []string{"/shims"}); got != "" {
+			t.Errorf("expected no output, got:\n%s", got)
+		}
+	})
+}
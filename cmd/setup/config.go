@@ -1,83 +1,33 @@
 package setup
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
 
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/rcfile"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// configureEnvironment sets up the environment variables for gobrew
+// configureEnvironment adds the gos block loading `gos init` to each shell
+// startup file, showing the diff of every file it changes
 func configureEnvironment() {
-	homeDir := common.GetHomeDir()
-
-	// Set environment variables
-	setEnvironmentVariables(homeDir)
-
-	yellow := color.New(color.FgYellow)
-	yellow.Printf("  📝 Configuration added\n")
-}
-
-// generateGobrewConfig generates the configuration script for gobrew
-func generateGConfig(homeDir, osName string) string {
-	gobrewPath := filepath.Join(homeDir, ".gobrew")
-	gobrewBinPath := filepath.Join(gobrewPath, "bin")
-	gobrewCurrentBinPath := filepath.Join(gobrewPath, "current", "bin")
-
-	config := fmt.Sprintf(`
-# gobrew (Go version manager) configuration
-export PATH="%s:%s:$PATH"
-
-# Go environment
-export GOPATH="$HOME/go"
-export PATH="$GOPATH/bin:$PATH"
-`, gobrewCurrentBinPath, gobrewBinPath)
-
-	if osName == "Windows" {
-		config = fmt.Sprintf(`
-# gobrew (Go version manager) configuration for Windows
-set PATH=%s;%s;%%PATH%%
-
-# Go environment
-set GOPATH=%%USERPROFILE%%\go
-set PATH=%%GOPATH%%\bin;%%PATH%%
-`, gobrewCurrentBinPath, gobrewBinPath)
-	}
-
-	return config
-}
-
-// setEnvironmentVariables adds gobrew configuration to shell files
-func setEnvironmentVariables(homeDir string) {
-	config := generateGConfig(homeDir, runtime.GOOS)
-
-	switch runtime.GOOS {
-	case "darwin", "linux":
-		// Unix-like systems
-		shellFiles := []string{
-			filepath.Join(homeDir, common.ZshrcFile),
-			filepath.Join(homeDir, common.BashrcFile),
-			filepath.Join(homeDir, common.BashProfileFile),
-		}
-
-		for _, file := range shellFiles {
-			if !common.HasConfigContent(file, "gobrew") {
-				common.AppendToFile(file, config)
-			}
+	var edits []rcfile.Edit
+	for _, target := range rcfile.Targets() {
+		edit, err := rcfile.PlanInit(target)
+		if err != nil {
+			ui.Error.Printf("  ❌ Error reading %s: %v\n", target.Path, err)
+			continue
 		}
+		edits = append(edits, edit)
+	}
 
-	case "windows":
-		// Windows - add to PowerShell profile
-		profilePath := filepath.Join(homeDir, common.PowerShellProfile)
-		if !common.HasConfigContent(profilePath, "gobrew") {
-			// Create directory if it doesn't exist
-			os.MkdirAll(filepath.Dir(profilePath), 0755)
-			common.AppendToFile(profilePath, config)
-		}
+	if err := rcfile.Apply(edits); err != nil {
+		ui.Error.Printf("  ❌ %v\n", err)
+		return
 	}
+	ui.Hint.Printf("  📝 Configuration added\n")
 }
 
 // createHelpScript creates a helper script with common commands
@@ -117,43 +67,39 @@ echo ""
 `
 
 	// Create directory if it doesn't exist
-	os.MkdirAll(filepath.Join(homeDir, ".gobrew"), 0755)
+	fsys.MkdirAll(filepath.Join(homeDir, ".gobrew"), 0755)
 	common.WriteToFile(scriptPath, helpContent)
 
 	// Make executable on Unix-like systems
 	if runtime.GOOS != "windows" {
-		os.Chmod(scriptPath, 0755)
+		fsys.Chmod(scriptPath, 0755)
 	}
 }
 
 // verifyInstallation checks if the installation was successful
 func verifyInstallation() {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	red := color.New(color.FgRed)
-
 	homeDir := common.GetHomeDir()
 	gobrewBin := filepath.Join(homeDir, ".gobrew", "bin", "gobrew")
 
 	// Check if gobrew binary exists
-	if _, err := os.Stat(gobrewBin); err == nil {
-		green.Println("  ✅ 'gobrew' binary found")
+	if fsys.Exists(gobrewBin) {
+		ui.Success.Println("  ✅ 'gobrew' binary found")
 	} else {
-		red.Println("  ❌ 'gobrew' binary not found")
+		ui.Error.Println("  ❌ 'gobrew' binary not found")
 		return
 	}
 
 	// Check if gobrew is working
 	if common.IsCommandAvailable("gobrew") {
-		green.Println("  ✅ 'gobrew' is available in PATH")
+		ui.Success.Println("  ✅ 'gobrew' is available in PATH")
 	} else {
-		yellow.Println("  ⚠️  'gobrew' not found in PATH (restart shell required)")
+		ui.Caution.Println("  ⚠️  'gobrew' not found in PATH (restart shell required)")
 	}
 
 	// Check if Go is installed
 	if common.IsCommandAvailable("go") {
-		green.Println("  ✅ Go is available")
+		ui.Success.Println("  ✅ Go is available")
 	} else {
-		yellow.Println("  ⚠️  Go not found (may need to install and use a version)")
+		ui.Caution.Println("  ⚠️  Go not found (may need to install and use a version)")
 	}
 }
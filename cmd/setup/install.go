@@ -1,25 +1,22 @@
 package setup
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
 	"runtime"
 	"time"
 
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/schollz/progressbar/v3"
 )
 
 // installGWithScript attempts to install g using the official install script
 func installGWithScript() bool {
-	blue := color.New(color.FgBlue)
-	blue.Println("  📥 Downloading g installer...")
+	ui.Info.Println("  📥 Downloading g installer...")
 	
 	// Create progress bar for installation
 	bar := progressbar.NewOptions(-1,
 		progressbar.OptionSetDescription("Installing g"),
+		progressbar.OptionSetWriter(ui.Progress()),
 		progressbar.OptionSetPredictTime(false),
 		progressbar.OptionSpinnerType(14),
 		progressbar.OptionShowCount(),
@@ -53,78 +50,22 @@ func installGWithScript() bool {
 	return result
 }
 
-// installGManually attempts manual installation of g
-func installGManually() bool {
-	homeDir := common.GetHomeDir()
-
-	if runtime.GOOS == "windows" {
-		return installGForWindows(homeDir)
-	}
-
-	// Unix-like systems
-	return installGForUnix(homeDir)
-}
-
-// installGForUnix installs g for Unix-like systems
-func installGForUnix(homeDir string) bool {
-	red := color.New(color.FgRed)
-	blue := color.New(color.FgBlue)
-	
-	blue.Println("  🔄 Manual installation for Unix-like system...")
-	
-	// Create .g directory structure
-	gDir := filepath.Join(homeDir, ".g")
-	binDir := filepath.Join(gDir, "bin")
-	
-	if err := os.MkdirAll(binDir, 0755); err != nil {
-		red.Printf("❌ Error creating directories: %v\n", err)
-		return false
-	}
-	
-	// Try to download g binary directly
-	if common.IsCommandAvailable("curl") {
-		downloadURL := "https://github.com/stefanmaric/g/releases/latest/download/g-$(uname -s)-$(uname -m)"
-		gBinary := filepath.Join(binDir, "g")
-		
-		downloadCmd := fmt.Sprintf("curl -L %s -o %s && chmod +x %s", downloadURL, gBinary, gBinary)
-		if common.ExecuteWithShell(downloadCmd) == nil {
-			return true
-		}
-	}
-	
-	// Try wget as fallback
-	if common.IsCommandAvailable("wget") {
-		downloadURL := "https://github.com/stefanmaric/g/releases/latest/download/g-$(uname -s)-$(uname -m)"
-		gBinary := filepath.Join(binDir, "g")
-		
-		downloadCmd := fmt.Sprintf("wget %s -O %s && chmod +x %s", downloadURL, gBinary, gBinary)
-		if common.ExecuteWithShell(downloadCmd) == nil {
-			return true
-		}
-	}
-	
-	return false
-}
-
 // checkExistingInstallations checks if version managers are already installed
 func checkExistingInstallations() bool {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	
 	hasG := common.IsCommandAvailable("g")
 	hasGobrew := common.IsCommandAvailable("gobrew")
 	
 	if hasG || hasGobrew {
-		green.Println("✅ Version manager already detected:")
+		ui.Success.Println("✅ Version manager already detected:")
 		if hasG {
-			fmt.Println("  • 'g' is available")
+			ui.Note.Println("  • 'g' is available")
 		}
 		if hasGobrew {
-			fmt.Println("  • 'gobrew' is available")
+			ui.Note.Println("  • 'gobrew' is available")
 		}
-		fmt.Println("")
-		yellow.Println("💡 Use --force to reinstall anyway")
-		fmt.Println("   Example: gos setup --force")
+		ui.Note.Println("")
+		ui.Hint.Println("💡 Use --force to reinstall anyway")
+		ui.Note.Println("   Example: gos setup --force")
 		return true
 	}
 	
@@ -1,11 +1,18 @@
 package setup
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
+	"os"
 	"runtime"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/config"
+	"github.com/cristobalcontreras/gos/cmd/install"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -13,34 +20,43 @@ import (
 func NewSetupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "setup",
-		Short: "Setup Go version manager (gobrew)",
-		Long: `Install and configure the gobrew Go version manager.
-This will install gobrew, configure environment variables,
-and install the latest stable Go version.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Short: "Setup Go version management",
+		Long: `Configure gos to manage Go toolchains on its own.
+This will create ~/.gos, configure environment variables,
+and install the latest stable Go version from go.dev.
+
+Use --gobrew to install and configure the gobrew version manager instead
+(the choice is saved as "backend=gobrew" in ~/.gos/config).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			force, _ := cmd.Flags().GetBool("force")
-			setupGoVersionManager(force)
+			gobrew, _ := cmd.Flags().GetBool("gobrew")
+			return setupGoVersionManager(force, gobrew)
 		},
 	}
 
 	cmd.Flags().BoolP("force", "f", false, "Force reinstallation even if version managers are already installed")
+	cmd.Flags().Bool("gobrew", false, "Install gobrew and use it instead of the native installer")
 	return cmd
 }
 
-func setupGoVersionManager(force bool) {
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
-	red := color.New(color.FgRed)
+func setupGoVersionManager(force, gobrew bool) error {
+	ui.Info.Println("🔧 Setting up Go version manager...")
 
-	blue.Println("🔧 Setting up Go version manager...")
+	// Native toolchains need no external tool (Windows still relies on gobrew)
+	if !gobrew && runtime.GOOS != "windows" {
+		return setupNative(force)
+	}
+	if err := config.Set("backend", "gobrew"); err != nil {
+		ui.Warn.Printf("⚠️  Could not save backend choice: %v\n", err)
+	}
 
 	// Check if any version manager is already installed (unless force is used)
 	if !force && checkExistingInstallations() {
-		return
+		return nil
 	}
 
 	if force {
-		yellow.Println("⚡ Force flag detected - proceeding with installation...")
+		ui.Hint.Println("⚡ Force flag detected - proceeding with installation...")
 	}
 
 	// Detect and display system information
@@ -48,18 +64,64 @@ func setupGoVersionManager(force bool) {
 
 	// Handle Windows separately
 	if runtime.GOOS == "windows" {
-		handleWindowsSetup()
-		return
+		return handleWindowsSetup()
 	}
 
 	// Unix-like systems setup
 	if !performUnixSetup() {
-		red.Println("❌ Setup failed")
-		return
+		return errors.New("setup failed: gobrew could not be installed")
 	}
 
 	// Complete setup
 	completeSetup()
+	return nil
+}
+
+// setupNative configures gos to download and manage toolchains itself
+func setupNative(force bool) error {
+	installer := toolchain.NewInstaller()
+	if versions, _ := installer.ListInstalled(); len(versions) > 0 && !force {
+		ui.Success.Printf("✅ gos already manages %d Go version(s) in %s\n", len(versions), installer.VersionsDir())
+		ui.Hint.Println("💡 Use --force to reconfigure anyway")
+		return nil
+	}
+
+	displaySystemInfo()
+
+	ui.Info.Println("\n▸ Creating gos directories...")
+	if err := os.MkdirAll(installer.VersionsDir(), 0755); err != nil {
+		return fmt.Errorf("setup failed: %w", err)
+	}
+	ui.Note.Printf("  📂 %s\n", installer.VersionsDir())
+
+	ui.Info.Println("\n▸ Configuring PATH and environment variables...")
+	configureEnvironment()
+
+	ui.Info.Println("\n▸ Installing latest stable Go version...")
+	version, err := installer.LatestVersion()
+	if err != nil {
+		return fmt.Errorf("could not determine latest Go version: %w", err)
+	}
+	if err := install.InstallVersion(version); err != nil {
+		return err
+	}
+
+	ui.Info.Println("\n▸ Activating installed Go version...")
+	if err := installer.Use(version); err != nil {
+		return fmt.Errorf("could not activate Go %s: %w", version, err)
+	}
+	ui.Success.Printf("  ✅ Go %s is active\n", version)
+
+	ui.Info.Println("\n▸ Creating per-directory version shims...")
+	if err := shim.RehashBackend(backend.NewNative()); err != nil {
+		ui.Warn.Printf("  ⚠️  Could not create shims: %v (run: gos rehash)\n", err)
+	} else {
+		ui.Note.Printf("  📂 %s\n", shim.Dir())
+	}
+
+	ui.Success.Println("\n✅ Installation completed!")
+	displayNextSteps()
+	return nil
 }
 
 // displaySystemInfo shows detected OS and architecture
@@ -78,123 +140,117 @@ func displaySystemInfo() {
 
 	if arch == "arm64" {
 		if osName == "macOS" {
-			fmt.Println("  Detected: Apple Silicon (M1/M2/M3)")
+			ui.Note.Println("  Detected: Apple Silicon (M1/M2/M3)")
 		} else {
-			fmt.Println("  Detected: ARM64")
+			ui.Note.Println("  Detected: ARM64")
 		}
 	} else if arch == "amd64" {
-		fmt.Println("  Detected: Intel x86_64")
+		ui.Note.Println("  Detected: Intel x86_64")
 	} else {
-		fmt.Printf("  Detected: %s on %s\n", arch, osName)
+		ui.Note.Printf("  Detected: %s on %s\n", arch, osName)
 	}
 }
 
 // handleWindowsSetup manages Windows-specific setup
-func handleWindowsSetup() {
-	yellow := color.New(color.FgYellow)
-
-	yellow.Println("\n⚠️  Windows detected.")
-	yellow.Println("   The original 'g' version manager doesn't support Windows.")
-	yellow.Println("   🚀 Using Windows-compatible alternatives...")
-
-	fmt.Print("\n   Continue with Windows setup? (Y/n): ")
-	var response string
-	fmt.Scanln(&response)
-	if response == "n" || response == "N" {
-		yellow.Println("Installation cancelled.")
-		return
+func handleWindowsSetup() error {
+	ui.Warn.Println("\n⚠️  Windows detected.")
+	ui.Hint.Println("   The original 'g' version manager doesn't support Windows.")
+	ui.Hint.Println("   🚀 Using Windows-compatible alternatives...")
+	ui.Prompt.Println()
+
+	confirmed, err := ui.Confirm("   Continue with Windows setup?", true)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		ui.Hint.Println("Installation cancelled.")
+		return nil
 	}
 
 	setupGoForWindows()
+	return nil
 }
 
 // performUnixSetup handles Unix-like systems setup
 func performUnixSetup() bool {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
-
 	// Install gobrew
-	blue.Println("\n▸ Installing 'gobrew'...")
+	ui.Info.Println("\n▸ Installing 'gobrew'...")
 	if installGobrew() {
-		green.Println("  ✅ 'gobrew' installed successfully")
+		ui.Success.Println("  ✅ 'gobrew' installed successfully")
 		return true
 	}
 
-	red.Println("  ❌ Failed to install 'gobrew'")
+	ui.Error.Println("  ❌ Failed to install 'gobrew'")
 	return false
 }
 
 // completeSetup finishes the setup process
 func completeSetup() {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-
-	blue.Println("\n▸ Configuring PATH and environment variables...")
+	ui.Info.Println("\n▸ Configuring PATH and environment variables...")
 	configureEnvironment()
 
-	blue.Println("\n▸ Installing latest stable Go version...")
+	ui.Info.Println("\n▸ Installing latest stable Go version...")
 	installLatestGo()
 
-	blue.Println("\n▸ Activating installed Go version...")
+	ui.Info.Println("\n▸ Activating installed Go version...")
 	activateLatestGo()
 
-	blue.Println("\n▸ Verifying installation...")
+	ui.Info.Println("\n▸ Verifying installation...")
 	verifyInstallation()
 
 	createHelpScript()
 
-	green.Println("\n✅ Installation completed!")
+	ui.Success.Println("\n✅ Installation completed!")
 	displayNextSteps()
 }
 
 // installLatestGo installs the latest Go version
 func installLatestGo() {
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-
-	installCmd := exec.Command("gobrew", "install", "latest")
-	if err := installCmd.Run(); err != nil {
-		yellow.Println("  ℹ️  Installing known specific version...")
-		fallbackCmd := exec.Command("gobrew", "install", "1.21.5")
-		fallbackCmd.Run()
+	if err := runner.Run("gobrew", "install", "latest"); err != nil {
+		ui.Hint.Println("  ℹ️  Installing known specific version...")
+		runner.Run("gobrew", "install", "1.21.5")
 	} else {
-		green.Println("  ✅ Go latest installed successfully")
+		ui.Success.Println("  ✅ Go latest installed successfully")
 	}
 }
 
 // activateLatestGo activates the installed Go version
 func activateLatestGo() {
-	useCmd := exec.Command("gobrew", "use", "latest")
-	if err := useCmd.Run(); err != nil {
+	if err := runner.Run("gobrew", "use", "latest"); err != nil {
 		// Try with specific version
-		fallbackUseCmd := exec.Command("gobrew", "use", "1.21.5")
-		fallbackUseCmd.Run()
+		runner.Run("gobrew", "use", "1.21.5")
 	}
 }
 
 // displayNextSteps shows the user what to do next
 func displayNextSteps() {
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-
-	fmt.Println("")
-	yellow.Println("📋 Next steps:")
+	ui.Note.Println("")
+	ui.Hint.Println("📋 Next steps:")
 
 	if runtime.GOOS == "windows" {
-		fmt.Println("1. Run: source ~/.bashrc  (or restart Git Bash/WSL)")
+		ui.Note.Println("1. Run: source ~/.bashrc  (or restart Git Bash/WSL)")
+	} else {
+		ui.Note.Println("1. Run: source ~/.zshrc  (or open a new terminal)")
+	}
+
+	if usingGobrew() {
+		ui.Note.Println("2. Verify: gobrew --version")
+		ui.Note.Println("3. Use: gos list  (to see installed versions)")
+		ui.Note.Println("")
+		ui.Hint.Println("💡 To see all available gobrew commands:")
+		ui.Note.Println("   gobrew help")
 	} else {
-		fmt.Println("1. Run: source ~/.zshrc  (or open a new terminal)")
-	}
-
-	fmt.Println("2. Verify: gobrew --version")
-	fmt.Println("3. Use: gos list  (to see installed versions)")
-	fmt.Println("")
-	yellow.Println("💡 To see all available gobrew commands:")
-	fmt.Println("   gobrew help")
-	fmt.Println("")
-	blue.Println("🚀 Quick examples:")
-	fmt.Println("   gos install 1.21.5     # Install Go 1.21.5")
-	fmt.Println("   gos use 1.21.5         # Switch to Go 1.21.5")
-	fmt.Println("   gos list               # View installed versions")
+		ui.Note.Println("2. Verify: go version")
+		ui.Note.Println("3. Use: gos list  (to see installed versions)")
+	}
+	ui.Note.Println("")
+	ui.Info.Println("🚀 Quick examples:")
+	ui.Note.Println("   gos install 1.21.5     # Install Go 1.21.5")
+	ui.Note.Println("   gos use 1.21.5         # Switch to Go 1.21.5")
+	ui.Note.Println("   gos list               # View installed versions")
+}
+
+// usingGobrew reports whether gobrew is the configured backend
+func usingGobrew() bool {
+	return config.Get("backend") == "gobrew"
 }
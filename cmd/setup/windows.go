@@ -1,12 +1,12 @@
 package setup
 
 import (
-	"fmt"
-	"os/exec"
 	"time"
 
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/toolchain"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/schollz/progressbar/v3"
 )
 
@@ -17,65 +17,60 @@ const (
 
 // installGForWindows handles Windows-specific installation
 func installGForWindows(homeDir string) bool {
-	red := color.New(color.FgRed)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	blue := color.New(color.FgBlue)
-
-	blue.Println("  💡 Windows detected - using alternative Go version managers...")
-	fmt.Println("")
+	ui.Info.Println("  💡 Windows detected - using alternative Go version managers...")
+	ui.Note.Println("")
 
 	// Option 1: Try to install gobrew (best option for Windows)
-	blue.Println("  🔄 Attempting to install 'gobrew' (recommended for Windows)...")
+	ui.Info.Println("  🔄 Attempting to install 'gobrew' (recommended for Windows)...")
 	if installGobrew() {
-		green.Println("  ✅ gobrew installed successfully!")
-		fmt.Println("  📋 You can now use: gobrew use latest")
+		ui.Success.Println("  ✅ gobrew installed successfully!")
+		ui.Note.Println("  📋 You can now use: gobrew use latest")
 		return true
 	}
 
 	// Option 2: Try to install voidint/g (supports Windows)
-	blue.Println("  🔄 Attempting to install 'voidint/g' (Windows compatible)...")
+	ui.Info.Println("  🔄 Attempting to install 'voidint/g' (Windows compatible)...")
 	if installVoidintG() {
-		green.Println("  ✅ voidint/g installed successfully!")
-		fmt.Println("  📋 You can now use: g install latest")
+		ui.Success.Println("  ✅ voidint/g installed successfully!")
+		ui.Note.Println("  📋 You can now use: g install latest")
 		return true
 	}
 
 	// Option 3: Manual Go installation
-	blue.Println("  🔄 Attempting direct Go installation...")
+	ui.Info.Println("  🔄 Attempting direct Go installation...")
 	if installGoDirectly(homeDir) {
-		green.Println("  ✅ Go installed directly!")
+		ui.Success.Println("  ✅ Go installed directly!")
 		return true
 	}
 
 	// If all fail, show manual options
-	red.Println("  ❌ Automatic installation failed.")
-	yellow.Println("  💡 Manual installation options:")
-	fmt.Println("")
-	fmt.Println("     🍺 Option 1 - Chocolatey:")
-	fmt.Println("       choco install golang")
-	fmt.Println("")
-	fmt.Println("     📦 Option 2 - Scoop:")
-	fmt.Println("       scoop install go")
-	fmt.Println("")
-	fmt.Println("     🌐 Option 3 - Official installer:")
-	fmt.Println("       Download from: https://golang.org/dl/")
-	fmt.Println("")
-	fmt.Println("     🐧 Option 4 - WSL (Windows Subsystem for Linux):")
-	fmt.Println("       Install WSL and use the Linux version of gos")
-	fmt.Println("")
+	ui.Error.Println("  ❌ Automatic installation failed.")
+	ui.Hint.Println("  💡 Manual installation options:")
+	ui.Note.Println("")
+	ui.Note.Println("     🍺 Option 1 - Chocolatey:")
+	ui.Note.Println("       choco install golang")
+	ui.Note.Println("")
+	ui.Note.Println("     📦 Option 2 - Scoop:")
+	ui.Note.Println("       scoop install go")
+	ui.Note.Println("")
+	ui.Note.Println("     🌐 Option 3 - Official installer:")
+	ui.Note.Println("       Download from: https://golang.org/dl/")
+	ui.Note.Println("")
+	ui.Note.Println("     🐧 Option 4 - WSL (Windows Subsystem for Linux):")
+	ui.Note.Println("       Install WSL and use the Linux version of gos")
+	ui.Note.Println("")
 
 	return false
 }
 
 // installGobrew installs gobrew - best option for Windows
 func installGobrew() bool {
-	blue := color.New(color.FgBlue)
-	blue.Println("  📥 Installing gobrew...")
+	ui.Info.Println("  📥 Installing gobrew...")
 	
 	// Create progress bar
 	bar := progressbar.NewOptions(-1,
 		progressbar.OptionSetDescription("Installing gobrew"),
+		progressbar.OptionSetWriter(ui.Progress()),
 		progressbar.OptionSetPredictTime(false),
 		progressbar.OptionSpinnerType(14),
 		progressbar.OptionShowCount(),
@@ -96,8 +91,7 @@ func installGobrew() bool {
 	if common.IsCommandAvailable("powershell") {
 		installScript := "Set-ExecutionPolicy Bypass -Scope Process -Force; [System.Net.ServicePointManager]::SecurityProtocol = [System.Net.ServicePointManager]::SecurityProtocol -bor 3072; iex ((New-Object System.Net.WebClient).DownloadString('https://raw.githubusercontent.com/kevincobain2000/gobrew/master/git.io.ps1'))"
 
-		cmd := exec.Command("powershell", powerShellCommand, installScript)
-		result = cmd.Run() == nil
+		result = runner.Run("powershell", powerShellCommand, installScript) == nil
 		if result {
 			bar.Finish()
 			return true
@@ -124,61 +118,65 @@ func installVoidintG() bool {
 	return false
 }
 
-// installGoDirectly installs Go directly as fallback
+// installGoDirectly installs Go directly as fallback using the native
+// installer, which verifies the archive's SHA-256 against the release index
 func installGoDirectly(homeDir string) bool {
-	version := "1.21.6"
-	goURL := fmt.Sprintf("https://golang.org/dl/go%s.windows-amd64.zip", version)
-
-	blue := color.New(color.FgBlue)
-	blue.Printf("  📥 Downloading Go %s for Windows...\n", version)
+	installer := toolchain.NewInstaller()
+	version, err := installer.LatestVersion()
+	if err != nil {
+		ui.Error.Printf("  ❌ Error resolving latest Go version: %v\n", err)
+		return false
+	}
 
-	// This would need implementation for downloading and extracting
-	// For now, return false as it's complex to implement properly
-	_ = goURL
-	return false
+	ui.Info.Printf("  📥 Downloading Go %s for Windows...\n", version)
+	if err := installer.Install(version); err != nil {
+		ui.Error.Printf("  ❌ Error installing Go %s: %v\n", version, err)
+		return false
+	}
+	if err := installer.Use(version); err != nil {
+		ui.Error.Printf("  ❌ Error activating Go %s: %v\n", version, err)
+		return false
+	}
+	return true
 }
 
 // setupGoForWindows provides Windows-specific setup instructions
 func setupGoForWindows() {
-	blue := color.New(color.FgBlue)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-
-	blue.Println("🪟 Windows Go Setup")
-	fmt.Println("")
+	ui.Info.Println("🪟 Windows Go Setup")
+	ui.Note.Println("")
 
-	yellow.Println("💡 Recommended options for Windows:")
-	fmt.Println("")
+	ui.Hint.Println("💡 Recommended options for Windows:")
+	ui.Note.Println("")
 
-	fmt.Println("1️⃣  Gobrew (Recommended):")
-	fmt.Println("   • Best option for Windows")
-	fmt.Println("   • Similar to 'g' but Windows-compatible")
-	fmt.Println("   • Commands: gobrew install latest, gobrew use latest")
-	fmt.Println("")
+	ui.Note.Println("1️⃣  Gobrew (Recommended):")
+	ui.Note.Println("   • Best option for Windows")
+	ui.Note.Println("   • Similar to 'g' but Windows-compatible")
+	ui.Note.Println("   • Commands: gobrew install latest, gobrew use latest")
+	ui.Note.Println("")
 
-	fmt.Println("2️⃣  Package Managers:")
-	fmt.Println("   🍺 Chocolatey: choco install golang")
-	fmt.Println("   📦 Scoop: scoop install go")
-	fmt.Println("   🍃 Winget: winget install GoLang.Go")
-	fmt.Println("")
+	ui.Note.Println("2️⃣  Package Managers:")
+	ui.Note.Println("   🍺 Chocolatey: choco install golang")
+	ui.Note.Println("   📦 Scoop: scoop install go")
+	ui.Note.Println("   🍃 Winget: winget install GoLang.Go")
+	ui.Note.Println("")
 
-	fmt.Println("3️⃣  Official Installer:")
-	fmt.Println("   🌐 Download from: https://golang.org/dl/")
-	fmt.Println("")
+	ui.Note.Println("3️⃣  Official Installer:")
+	ui.Note.Println("   🌐 Download from: https://golang.org/dl/")
+	ui.Note.Println("")
 
-	fmt.Println("4️⃣  WSL (Windows Subsystem for Linux):")
-	fmt.Println("   🐧 Install WSL and use the Linux version of gos")
-	fmt.Println("")
+	ui.Note.Println("4️⃣  WSL (Windows Subsystem for Linux):")
+	ui.Note.Println("   🐧 Install WSL and use the Linux version of gos")
+	ui.Note.Println("")
 
 	// Try to install gobrew automatically
-	blue.Println("🚀 Attempting automatic gobrew installation...")
+	ui.Info.Println("🚀 Attempting automatic gobrew installation...")
 	if installGobrew() {
-		green.Println("✅ gobrew installed successfully!")
-		fmt.Println("")
-		yellow.Println("📋 Next steps:")
-		fmt.Println("   gobrew install latest")
-		fmt.Println("   gobrew use latest")
+		ui.Success.Println("✅ gobrew installed successfully!")
+		ui.Note.Println("")
+		ui.Hint.Println("📋 Next steps:")
+		ui.Note.Println("   gobrew install latest")
+		ui.Note.Println("   gobrew use latest")
 	} else {
-		yellow.Println("❌ Automatic installation failed. Please use manual options above.")
+		ui.Error.Println("❌ Automatic installation failed. Please use manual options above.")
 	}
 }
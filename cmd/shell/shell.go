@@ -0,0 +1,119 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+)
+
+// Shell is a shell gos can emit code for
+type Shell string
+
+// Supported shells
+const (
+	Bash Shell = "bash"
+	Zsh  Shell = "zsh"
+	Fish Shell = "fish"
+	Pwsh Shell = "pwsh"
+)
+
+// Names lists the supported shells
+var Names = []string{string(Bash), string(Zsh), string(Fish), string(Pwsh)}
+
+// Parse returns the shell with the given name; "powershell" is accepted for pwsh
+func Parse(name string) (Shell, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "bash":
+		return Bash, nil
+	case "zsh":
+		return Zsh, nil
+	case "fish":
+		return Fish, nil
+	case "pwsh", "powershell":
+		return Pwsh, nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (supported: %s)", name, strings.Join(Names, ", "))
+}
+
+// Detect returns the shell gos is running under, if it is a supported one
+func Detect() (Shell, error) {
+	return Parse(strings.TrimSuffix(common.DetectCurrentShell(), ".exe"))
+}
+
+// Export returns a statement setting an environment variable
+func (s Shell) Export(key, value string) string {
+	switch s {
+	case Fish:
+		return fmt.Sprintf("set -gx %s %s", key, s.Quote(value))
+	case Pwsh:
+		return fmt.Sprintf("$env:%s = %s", key, s.Quote(value))
+	}
+	return fmt.Sprintf("export %s=%s", key, s.Quote(value))
+}
+
+// Unset returns a statement removing an environment variable
+func (s Shell) Unset(key string) string {
+	switch s {
+	case Fish:
+		return fmt.Sprintf("set -e %s", key)
+	case Pwsh:
+		return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", key)
+	}
+	return fmt.Sprintf("unset %s", key)
+}
+
+// SetPath returns a statement replacing PATH with the given entries
+func (s Shell) SetPath(entries []string) string {
+	switch s {
+	case Fish:
+		quoted := make([]string, len(entries))
+		for i, entry := range entries {
+			quoted[i] = s.Quote(entry)
+		}
+		return "set -gx PATH " + strings.Join(quoted, " ")
+	case Pwsh:
+		// pwsh also runs on macOS and Linux, where PATH is ':'-separated
+		quoted := make([]string, len(entries))
+		for i, entry := range entries {
+			quoted[i] = s.Quote(entry)
+		}
+		return fmt.Sprintf("$env:PATH = @(%s) -join [IO.Path]::PathSeparator", strings.Join(quoted, ", "))
+	}
+	return s.Export("PATH", strings.Join(entries, ":"))
+}
+
+// Quote quotes a value for the shell
+func (s Shell) Quote(value string) string {
+	switch s {
+	case Pwsh:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case Fish:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// RCFile returns the startup file a shell reads, relative to the home directory
+func (s Shell) RCFile() string {
+	switch s {
+	case Zsh:
+		return common.ZshrcFile
+	case Fish:
+		return ".config/fish/config.fish"
+	case Pwsh:
+		return common.PowerShellProfile
+	}
+	return common.BashrcFile
+}
+
+// InitLine returns the single rc-file line that loads the gos integration
+func (s Shell) InitLine() string {
+	switch s {
+	case Fish:
+		return "gos init fish | source"
+	case Pwsh:
+		return "(& gos init pwsh) -join \"`n\" | Invoke-Expression"
+	}
+	return fmt.Sprintf(`eval "$(gos init %s)"`, s)
+}
@@ -0,0 +1,47 @@
+package shell
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	for input, want := range map[string]Shell{"bash": Bash, "ZSH": Zsh, "fish": Fish, "powershell": Pwsh, "pwsh": Pwsh} {
+		got, err := Parse(input)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := Parse("tcsh"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestExport(t *testing.T) {
+	cases := map[Shell]string{
+		Bash: `export GOROOT='/opt/it'\''s go'`,
+		Zsh:  `export GOROOT='/opt/it'\''s go'`,
+		Fish: `set -gx GOROOT '/opt/it\'s go'`,
+		Pwsh: `$env:GOROOT = '/opt/it''s go'`,
+	}
+
+	for sh, want := range cases {
+		t.Run(string(sh), func(t *testing.T) {
+			if got := sh.Export("GOROOT", "/opt/it's go"); got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	entries := []string{"/a/bin", "/b/bin"}
+
+	if got := Bash.SetPath(entries); got != `export PATH='/a/bin:/b/bin'` {
+		t.Errorf("unexpected bash PATH statement %s", got)
+	}
+	if got := Fish.SetPath(entries); got != `set -gx PATH '/a/bin' '/b/bin'` {
+		t.Errorf("unexpected fish PATH statement %s", got)
+	}
+	if got := Pwsh.SetPath(entries); got != `$env:PATH = @('/a/bin', '/b/bin') -join [IO.Path]::PathSeparator` {
+		t.Errorf("unexpected pwsh PATH statement %s", got)
+	}
+}
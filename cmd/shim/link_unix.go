@@ -0,0 +1,10 @@
+//go:build !windows
+
+package shim
+
+import "os"
+
+// linkShim points a shim at the gos executable
+func linkShim(executable, shim string) error {
+	return os.Symlink(executable, shim)
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package shim
+
+import (
+	"io"
+	"os"
+)
+
+// linkShim hard-links the gos executable, copying it when links are unsupported
+func linkShim(executable, shim string) error {
+	if err := os.Link(executable, shim); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(executable)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(shim, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
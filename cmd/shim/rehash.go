@@ -0,0 +1,72 @@
+package shim
+
+import (
+	"fmt"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewRehashCmd creates the rehash command
+func NewRehashCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rehash",
+		Short: "Regenerate the go/gofmt shims for per-directory versions",
+		Long: `Regenerate the shims in ~/.gos/shims and their version index.
+
+Each shim (go, gofmt and every other binary shipped in an installed
+toolchain's bin directory) resolves the version for the working directory
+on every invocation, the same way 'gos current' does, and runs the matching
+toolchain. Two terminals in two projects can therefore use different Go
+versions at the same time. Put ~/.gos/shims first in PATH to use them.
+
+Install and remove refresh the shims automatically once they exist.`,
+		Example: `  gos rehash    # Create or refresh the shims`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := backend.Active()
+			if err != nil {
+				return err
+			}
+
+			if err := RehashBackend(b); err != nil {
+				return fmt.Errorf("generating shims: %w", err)
+			}
+
+			index, _ := LoadIndex()
+			ui.Success.Printf("✅ Shims written to %s\n", Dir())
+			if index != nil {
+				ui.Note.Printf("  %d binaries across %d installed versions\n", len(index.Binaries), len(index.Versions))
+			}
+			ui.Hint.Printf("💡 Make sure %s comes first in PATH\n", Dir())
+			return nil
+		},
+	}
+}
+
+// RehashBackend regenerates the shims from the backend's installed versions
+func RehashBackend(b backend.Backend) error {
+	installed, err := b.ListInstalled()
+	if err != nil {
+		return fmt.Errorf("listing installed versions: %w", err)
+	}
+
+	versions := make(map[string]string, len(installed))
+	for _, version := range installed {
+		versions[version] = b.GOROOT(version)
+	}
+	return Rehash(b.Name(), b.GOROOT(""), versions)
+}
+
+// Refresh regenerates the shims after versions changed, if shims are in use.
+// Failures only leave the previous shims in place, so they are reported but
+// never fail the command that triggered the refresh.
+func Refresh(b backend.Backend) {
+	if !Enabled() {
+		return
+	}
+	if err := RehashBackend(b); err != nil {
+		ui.Warn.Printf("⚠️  Could not refresh shims: %v (run: gos rehash)\n", err)
+	}
+}
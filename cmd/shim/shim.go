@@ -0,0 +1,259 @@
+package shim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/usage"
+)
+
+// Index is the precomputed lookup table shims consult on every invocation,
+// so resolving a version never has to ask the backend or spawn a process
+type Index struct {
+	Backend  string            `json:"backend"`
+	Current  string            `json:"current"`           // GOROOT used when no version is configured
+	Version  string            `json:"version,omitempty"` // installed version Current pointed at when rehashing
+	Versions map[string]string `json:"versions"`          // installed version -> GOROOT
+	Binaries []string          `json:"binaries"`          // names of the shimmed binaries
+}
+
+// Dir returns the directory holding the shims
+func Dir() string {
+	return common.GetShimsDir()
+}
+
+// IndexFile returns the location of the shim index
+func IndexFile() string {
+	return filepath.Join(common.GetGosHome(), "shims.json")
+}
+
+// Enabled reports whether shims have been generated
+func Enabled() bool {
+	info, err := os.Stat(Dir())
+	return err == nil && info.IsDir()
+}
+
+// LoadIndex reads the shim index
+func LoadIndex() (*Index, error) {
+	data, err := fsys.ReadFile(IndexFile())
+	if err != nil {
+		return nil, err
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", IndexFile(), err)
+	}
+	return &index, nil
+}
+
+// Lookup maps a version spec to an installed version and its GOROOT
+func (idx *Index) Lookup(spec string) (version, goroot string, err error) {
+	// Linked toolchains match by name only
+	if goroot, ok := idx.Versions[strings.TrimSpace(spec)]; ok && !goversion.IsValid(spec) {
+		return strings.TrimSpace(spec), goroot, nil
+	}
+
+	installed := make([]string, 0, len(idx.Versions))
+	for v := range idx.Versions {
+		installed = append(installed, v)
+	}
+
+	constraint, err := goversion.ParseConstraint(spec)
+	if err != nil {
+		return "", "", err
+	}
+	if constraint.IsExact() {
+		for _, v := range installed {
+			if goversion.Equal(v, spec) {
+				return v, idx.Versions[v], nil
+			}
+		}
+		return "", "", fmt.Errorf("Go %s is not installed", goversion.Normalize(spec))
+	}
+
+	version, err = goversion.Resolve(spec, installed)
+	if err != nil {
+		return "", "", fmt.Errorf("no installed Go version matches %q", spec)
+	}
+	for _, v := range installed {
+		if goversion.Equal(v, version) {
+			return v, idx.Versions[v], nil
+		}
+	}
+	return version, idx.Versions[version], nil
+}
+
+// Invoked reports whether the program was started through a shim and returns
+// the shimmed binary name. Only names with a shim in Dir count, so a renamed
+// gos binary still behaves as the CLI.
+func Invoked(argv0 string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(argv0), ".exe")
+	if name == "" || name == "gos" {
+		return "", false
+	}
+	if _, err := os.Lstat(filepath.Join(Dir(), name+exeSuffix())); err != nil {
+		return "", false
+	}
+	return name, true
+}
+
+// Run resolves the Go version for the working directory and replaces the
+// process with the matching toolchain binary. It returns an exit code only
+// when the binary could not be started.
+func Run(name string, args []string) int {
+	index, err := LoadIndex()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gos: shim index unavailable (%v); run: gos rehash\n", err)
+		return 127
+	}
+
+	dir, _ := os.Getwd()
+	version, goroot, result, err := index.Select(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gos: %v (requested by %s)\n", err, describe(result))
+		fmt.Fprintf(os.Stderr, "gos: install it with: gos install %s\n", result.Version)
+		return 127
+	}
+
+	binary := filepath.Join(goroot, "bin", name+exeSuffix())
+	if _, err := os.Stat(binary); err != nil {
+		fmt.Fprintf(os.Stderr, "gos: %s is not available in %s\n", name, goroot)
+		return 127
+	}
+
+	usage.Touch(version)
+
+	env := append(os.Environ(),
+		"GOROOT="+goroot,
+		"PATH="+filepath.Join(goroot, "bin")+string(os.PathListSeparator)+os.Getenv("PATH"),
+	)
+	return common.ExecProcess(binary, append([]string{name}, args...), env)
+}
+
+// Select returns the version and GOROOT a shim started in dir runs: the one
+// the setting found for dir asks for, or the current one without a setting
+func (idx *Index) Select(dir string) (version, goroot string, result resolver.Result, err error) {
+	result, err = resolver.Resolve(dir)
+	if err != nil || !result.Found() {
+		return idx.Version, idx.Current, result, nil
+	}
+	version, goroot, err = idx.Choose(result.Version)
+	return version, goroot, result, err
+}
+
+// Choose maps a project's version spec to the toolchain a shim runs. Like
+// the auto-switch hook, it keeps the current version when that satisfies
+// the spec, so a go.mod "go 1.21" line does not override gos use or gos
+// default with the newest installed version.
+func (idx *Index) Choose(spec string) (version, goroot string, err error) {
+	goroot, installed := idx.Versions[idx.Version]
+	if constraint, err := goversion.ParseConstraint(spec); err == nil && installed &&
+		constraint.Channel() == "" && constraint.Matches(idx.Version) {
+		return idx.Version, goroot, nil
+	}
+	return idx.Lookup(spec)
+}
+
+// versionOf returns the installed version whose GOROOT is goroot once
+// symlinks are resolved, so the current link maps to the version it points at
+func versionOf(goroot string, versions map[string]string) string {
+	resolved, err := filepath.EvalSymlinks(goroot)
+	if err != nil {
+		return ""
+	}
+	for version, root := range versions {
+		if target, err := filepath.EvalSymlinks(root); err == nil && target == resolved {
+			return version
+		}
+	}
+	return ""
+}
+
+// describe names where a resolved version came from
+func describe(result resolver.Result) string {
+	if result.Path != "" {
+		return result.Path
+	}
+	return result.Source
+}
+
+// Rehash regenerates the shims and the index from the installed versions.
+// current is the GOROOT used when no version is configured, and versions maps
+// every installed version to its GOROOT.
+func Rehash(backendName, current string, versions map[string]string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gos executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	names := map[string]bool{"go": true, "gofmt": true}
+	for _, goroot := range versions {
+		entries, _ := os.ReadDir(filepath.Join(goroot, "bin"))
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names[strings.TrimSuffix(entry.Name(), ".exe")] = true
+			}
+		}
+	}
+
+	binaries := make([]string, 0, len(names))
+	for name := range names {
+		binaries = append(binaries, name)
+	}
+	sort.Strings(binaries)
+
+	// Build the new shims next to the old ones and swap them in at once
+	staging := Dir() + ".tmp"
+	os.RemoveAll(staging)
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return err
+	}
+	for _, name := range binaries {
+		if err := linkShim(executable, filepath.Join(staging, name+exeSuffix())); err != nil {
+			os.RemoveAll(staging)
+			return fmt.Errorf("creating %s shim: %w", name, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(Index{
+		Backend:  backendName,
+		Current:  current,
+		Version:  versionOf(current, versions),
+		Versions: versions,
+		Binaries: binaries,
+	}, "", "  ")
+	if err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.WriteFile(IndexFile()+".tmp", data, 0644); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+
+	os.RemoveAll(Dir())
+	if err := os.Rename(staging, Dir()); err != nil {
+		return err
+	}
+	return os.Rename(IndexFile()+".tmp", IndexFile())
+}
+
+// exeSuffix returns the executable suffix of the running platform
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+package shim
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+)
+
+// fakeToolchain creates a GOROOT whose bin directory holds the given binaries
+func fakeToolchain(t *testing.T, binaries ...string) string {
+	t.Helper()
+	goroot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(goroot, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range binaries {
+		if err := os.WriteFile(filepath.Join(goroot, "bin", name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return goroot
+}
+
+func TestLookup(t *testing.T) {
+	index := &Index{Versions: map[string]string{
+		"1.21.5": "/versions/1.21.5",
+		"1.22.0": "/versions/1.22.0",
+		"1.22.3": "/versions/1.22.3",
+		"gotip":  "/src/go",
+	}}
+
+	t.Run("exact version", func(t *testing.T) {
+		version, goroot, err := index.Lookup("go1.21.5")
+		if err != nil || version != "1.21.5" || goroot != "/versions/1.21.5" {
+			t.Errorf("unexpected lookup result %s %s %v", version, goroot, err)
+		}
+	})
+
+	t.Run("partial version picks the newest", func(t *testing.T) {
+		version, _, err := index.Lookup("1.22")
+		if err != nil || version != "1.22.3" {
+			t.Errorf("expected 1.22.3, got %s (%v)", version, err)
+		}
+	})
+
+	t.Run("linked toolchain by name", func(t *testing.T) {
+		version, goroot, err := index.Lookup("gotip")
+		if err != nil || version != "gotip" || goroot != "/src/go" {
+			t.Errorf("unexpected lookup result %s %s %v", version, goroot, err)
+		}
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		if _, _, err := index.Lookup("1.20.1"); err == nil {
+			t.Error("expected an error for a version that is not installed")
+		}
+	})
+}
+
+func TestChoose(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	t.Setenv(common.HomeVar, t.TempDir())
+	t.Setenv(resolver.EnvVar, "")
+	module := t.TempDir()
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := resolver.Resolve(module)
+	if err != nil || !result.Found() {
+		t.Fatalf("Resolve() = %+v, %v", result, err)
+	}
+
+	b := backend.NewNative()
+	for _, version := range []string{"1.20.14", "1.21.5", "1.22.3"} {
+		bin := filepath.Join(b.GOROOT(version), "bin")
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// choose switches to current through the current link and rehashes
+	choose := func(t *testing.T, current string) (string, string) {
+		t.Helper()
+		if err := b.Use(current); err != nil {
+			t.Fatal(err)
+		}
+		if err := RehashBackend(b); err != nil {
+			t.Fatal(err)
+		}
+		index, err := LoadIndex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index.Version != current {
+			t.Errorf("index version = %q, want %s", index.Version, current)
+		}
+		version, goroot, err := index.Choose(result.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return version, goroot
+	}
+
+	t.Run("the current version wins when it satisfies go.mod", func(t *testing.T) {
+		if version, goroot := choose(t, "1.21.5"); version != "1.21.5" || goroot != b.GOROOT("1.21.5") {
+			t.Errorf("Choose(%q) = %s %s, want the current 1.21.5", result.Version, version, goroot)
+		}
+	})
+
+	t.Run("the newest match otherwise", func(t *testing.T) {
+		if version, _ := choose(t, "1.20.14"); version != "1.22.3" {
+			t.Errorf("Choose(%q) = %s, want 1.22.3", result.Version, version)
+		}
+	})
+}
+
+func TestRehash(t *testing.T) {
+	t.Setenv(common.HomeVar, t.TempDir())
+
+	older := fakeToolchain(t, "go", "gofmt")
+	newer := fakeToolchain(t, "go", "gofmt", "gopls")
+
+	err := Rehash("native", newer, map[string]string{"1.21.5": older, "1.22.3": newer})
+	if err != nil {
+		t.Fatalf("Rehash failed: %v", err)
+	}
+
+	index, err := LoadIndex()
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if !reflect.DeepEqual(index.Binaries, []string{"go", "gofmt", "gopls"}) {
+		t.Errorf("unexpected binaries %v", index.Binaries)
+	}
+	if index.Current != newer || index.Version != "1.22.3" {
+		t.Errorf("expected current %s (1.22.3), got %s (%s)", newer, index.Current, index.Version)
+	}
+
+	for _, name := range index.Binaries {
+		if _, err := os.Lstat(filepath.Join(Dir(), name)); err != nil {
+			t.Errorf("missing %s shim: %v", name, err)
+		}
+	}
+
+	t.Run("shims are recognised by name", func(t *testing.T) {
+		if name, ok := Invoked(filepath.Join(Dir(), "gofmt")); !ok || name != "gofmt" {
+			t.Errorf("expected gofmt shim, got %q %v", name, ok)
+		}
+		if _, ok := Invoked("/usr/local/bin/gos"); ok {
+			t.Error("gos itself must not be treated as a shim")
+		}
+		if _, ok := Invoked("/usr/bin/vet"); ok {
+			t.Error("names without a shim must not be treated as shims")
+		}
+	})
+}
@@ -1,84 +1,75 @@
 package status
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/doctor"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// ShowEnvironment displays environment variables and PATH information
-func ShowEnvironment() {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
-	
-	// Expected values
-	expectedGoroot := filepath.Join(os.Getenv("HOME"), ".g", "go")
-	expectedGopath := filepath.Join(os.Getenv("HOME"), "go")
-	
-	envVars := map[string]string{
-		"GOROOT": expectedGoroot,
-		"GOPATH": expectedGopath,
-		"GOPROXY": "",
-		"GOSUMDB": "",
-		"GOMODCACHE": "",
+// EnvVar is a Go environment variable as the shell sets it
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PathEntry is a Go-related PATH entry
+type PathEntry struct {
+	Path    string `json:"path"`
+	Managed bool   `json:"managed"` // a backend bin directory or $GOPATH/bin
+}
+
+// GetEnvironment lists the Go environment variables
+func GetEnvironment() []EnvVar {
+	var vars []EnvVar
+	for _, name := range []string{"GOROOT", "GOPATH", "GOPROXY", "GOSUMDB", "GOMODCACHE"} {
+		vars = append(vars, EnvVar{Name: name, Value: os.Getenv(name)})
 	}
-	
-	for envVar, expected := range envVars {
-		if value := os.Getenv(envVar); value != "" {
-			if expected != "" && value == expected {
-				green.Printf("  ✅ %s: %s\n", envVar, value)
-			} else if expected != "" {
-				yellow.Printf("  ⚠️  %s: %s (expected: %s)\n", envVar, value, expected)
-			} else {
-				blue.Printf("  ℹ️  %s: %s\n", envVar, value)
-			}
-		} else {
-			if expected != "" {
-				yellow.Printf("  ❌ %s: (not set, should be: %s)\n", envVar, expected)
-			} else {
-				fmt.Printf("  %s: (not set)\n", envVar)
-			}
+	return vars
+}
+
+// GetPathEntries lists the Go-related PATH entries; managed directories
+// missing from PATH are reported by the path-entries check
+func GetPathEntries(env *doctor.Env) []PathEntry {
+	entries := []PathEntry{}
+	for _, entry := range env.Path {
+		if managed := slices.Contains(env.ManagedDirs(), filepath.Clean(entry)); managed || strings.Contains(entry, "go") {
+			entries = append(entries, PathEntry{Path: entry, Managed: managed})
 		}
 	}
-
-	// Show PATH entries related to Go
-	showGoPathEntries()
+	return entries
 }
 
-// showGoPathEntries displays Go-related PATH entries
-func showGoPathEntries() {
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	blue := color.New(color.FgBlue)
+// GetChecks runs the gos doctor checks on the environment variables and PATH
+func GetChecks(env *doctor.Env) []doctor.Result {
+	return doctor.Run(env, doctor.ChecksIn(doctor.SectionEnvironment, doctor.SectionPath), false).Results
+}
 
-	fmt.Println("  PATH (Go-related entries):")
-	path := os.Getenv("PATH")
-	pathEntries := strings.Split(path, ":")
-	hasGoBin := false
-	hasGBin := false
-	
-	for _, entry := range pathEntries {
-		if strings.Contains(entry, "go") || strings.Contains(entry, ".g") {
-			if strings.Contains(entry, ".g/bin") {
-				hasGBin = true
-				green.Printf("    ✅ %s\n", entry)
-			} else if strings.Contains(entry, "go/bin") {
-				hasGoBin = true
-				green.Printf("    ✅ %s\n", entry)
-			} else {
-				blue.Printf("    ℹ️  %s\n", entry)
-			}
+// ShowEnvironment displays the environment variables, the Go-related PATH
+// entries and the checks on them
+func ShowEnvironment(vars []EnvVar, entries []PathEntry, checks []doctor.Result) {
+	for _, v := range vars {
+		if v.Value != "" {
+			ui.Out.Printf("  ℹ️  %s: %s\n", v.Name, v.Value)
+		} else {
+			ui.Out.Printf("  %s: (not set)\n", v.Name)
 		}
 	}
-	
-	if !hasGBin {
-		yellow.Println("    ⚠️  ~/.g/bin not found in PATH")
+
+	// Show PATH entries related to Go
+	ui.Out.Println("  PATH (Go-related entries):")
+	for _, entry := range entries {
+		if entry.Managed {
+			ui.Success.Printf("    ✅ %s\n", entry.Path)
+		} else {
+			ui.Out.Printf("    ℹ️  %s\n", entry.Path)
+		}
 	}
-	if !hasGoBin {
-		yellow.Println("    ⚠️  $GOPATH/bin not found in PATH")
+
+	for _, check := range checks {
+		doctor.ShowResult(check)
 	}
 }
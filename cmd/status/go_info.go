@@ -1,70 +1,101 @@
 package status
 
 import (
-	"fmt"
+	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// ShowCurrentGo displays information about the current Go installation
-func ShowCurrentGo() {
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	blue := color.New(color.FgBlue)
+// GetCurrentGo inspects the go binary in PATH
+func GetCurrentGo(b backend.Backend) CurrentGo {
+	info := CurrentGo{GoInfo: common.CurrentGo()}
+	info.Managed = info.Available && sameDir(info.GOROOT, b.GOROOT(""))
+	return info
+}
 
-	if _, err := exec.LookPath("go"); err != nil {
-		yellow.Println("  ⚠️  Go is not available in PATH")
-		yellow.Println("  💡 Try running: source ~/.zshrc")
+// ShowCurrentGo displays information about the current Go installation
+func ShowCurrentGo(info CurrentGo) {
+	if !info.Available {
+		ui.Caution.Println("  ⚠️  Go is not available in PATH")
+		ui.Hint.Println("  💡 Try running: source ~/.zshrc")
 		return
 	}
 
-	// Show version with better formatting
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		version := strings.TrimSpace(string(output))
-		green.Printf("  ✅ %s\n", version)
-	}
+	ui.Success.Printf("  ✅ go version go%s %s\n", info.Version, info.Platform)
 
 	// Show GOROOT with validation
-	if output, err := exec.Command("go", "env", "GOROOT").Output(); err == nil {
-		goroot := strings.TrimSpace(string(output))
-		expectedGoroot := filepath.Join(os.Getenv("HOME"), ".g", "go")
-		if goroot == expectedGoroot {
-			green.Printf("  ✅ GOROOT: %s\n", goroot)
+	if info.GOROOT != "" {
+		if info.Managed {
+			ui.Success.Printf("  ✅ GOROOT: %s\n", info.GOROOT)
 		} else {
-			blue.Printf("  ℹ️  GOROOT: %s\n", goroot)
+			ui.Out.Printf("  ℹ️  GOROOT: %s\n", info.GOROOT)
 		}
 	}
 
-	// Show GOPATH
-	if output, err := exec.Command("go", "env", "GOPATH").Output(); err == nil {
-		fmt.Printf("  GOPATH: %s", string(output))
+	if info.GOPATH != "" {
+		ui.Out.Printf("  GOPATH: %s\n", info.GOPATH)
 	}
 }
 
-// ShowDiskUsage displays disk usage information for Go directories
-func ShowDiskUsage() {
-	homeDir := os.Getenv("HOME")
-	gDir := filepath.Join(homeDir, ".g")
+// DiskUsage is the size of a directory gos manages or uses
+type DiskUsage struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// GetDiskUsage measures the backend's directory and the Go workspace
+func GetDiskUsage(b backend.Backend) []DiskUsage {
+	return []DiskUsage{
+		measure(b.Name()+" directory", b.Root()),
+		measure("~/go directory", filepath.Join(common.GetHomeDir(), "go")),
+	}
+}
 
-	if _, err := os.Stat(gDir); err == nil {
-		if output, err := exec.Command("du", "-sh", gDir).Output(); err == nil {
-			fmt.Printf("  ~/.g directory: %s", string(output))
+// measure sums the size of the regular files below dir
+func measure(name, dir string) DiskUsage {
+	usage := DiskUsage{Name: name, Path: dir}
+	if _, err := os.Stat(dir); err != nil {
+		return usage
+	}
+	usage.Exists = true
+
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				usage.Bytes += info.Size()
+			}
+		}
+		return nil
+	})
+	return usage
+}
+
+// ShowDiskUsage displays disk usage information for Go directories
+func ShowDiskUsage(usages []DiskUsage) {
+	for _, usage := range usages {
+		if usage.Exists {
+			ui.Out.Printf("  %s: %s\t%s\n", usage.Name, common.FormatBytes(usage.Bytes), usage.Path)
 		} else {
-			fmt.Println("  Could not calculate ~/.g directory size")
+			ui.Out.Printf("  %s not found (%s)\n", usage.Name, usage.Path)
 		}
-	} else {
-		fmt.Println("  ~/.g directory not found")
 	}
+}
 
-	// Show Go workspace size if it exists
-	goDir := filepath.Join(homeDir, "go")
-	if _, err := os.Stat(goDir); err == nil {
-		if output, err := exec.Command("du", "-sh", goDir).Output(); err == nil {
-			fmt.Printf("  ~/go directory: %s", string(output))
-		}
+// sameDir reports whether two paths refer to the same directory, resolving symlinks
+func sameDir(a, b string) bool {
+	if a == b {
+		return true
 	}
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
 }
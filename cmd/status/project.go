@@ -1,37 +1,58 @@
 package status
 
 import (
+	"fmt"
 	"os"
-	"strings"
 
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// ShowProjectConfig displays project-specific Go configuration
-func ShowProjectConfig() {
-	// Check for .go-version file
-	if _, err := os.Stat(".go-version"); err == nil {
-		if content, err := os.ReadFile(".go-version"); err == nil {
-			version := strings.TrimSpace(string(content))
-			color.Green("  ✅ .go-version found: %s", version)
-		}
-	} else {
-		color.Yellow("  ℹ️  No .go-version file in current directory")
+// Project is the Go version configured for the current directory
+type Project struct {
+	Version  string `json:"version"`
+	Source   string `json:"source"`
+	Path     string `json:"path,omitempty"` // file the version came from
+	Shadowed int    `json:"shadowed"`       // other settings found with lower precedence
+}
+
+// GetProjectConfig resolves which Go version applies to the current
+// directory, searching parent directories and the global default; it returns
+// nil when none is configured
+func GetProjectConfig() (*Project, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("reading current directory: %w", err)
+	}
+
+	result, err := resolver.Resolve(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving Go version: %w", err)
+	}
+	if !result.Found() {
+		return nil, nil
 	}
 
-	// Check for go.mod
-	if _, err := os.Stat("go.mod"); err == nil {
-		if content, err := os.ReadFile("go.mod"); err == nil {
-			lines := strings.Split(string(content), "\n")
-			for _, line := range lines {
-				if strings.HasPrefix(line, "go ") {
-					version := strings.TrimPrefix(line, "go ")
-					color.Green("  ✅ go.mod found, Go version: %s", version)
-					break
-				}
-			}
-		}
-	} else {
-		color.Yellow("  ℹ️  No go.mod file in current directory")
+	project := &Project{Version: result.Version, Source: result.Source, Path: result.Path}
+	if len(result.Steps) > 1 {
+		project.Shadowed = len(result.Steps) - 1
+	}
+	return project, nil
+}
+
+// ShowProjectConfig displays which Go version applies to the current directory
+func ShowProjectConfig(project *Project) {
+	if project == nil {
+		ui.Out.Printf("  ℹ️  No .go-version, .tool-versions or go.mod found, and no default set\n")
+		return
+	}
+
+	location := project.Path
+	if location == "" {
+		location = project.Source
+	}
+	ui.Success.Printf("  ✅ Go %s from %s (%s)\n", project.Version, project.Source, location)
+	if project.Shadowed > 0 {
+		ui.Out.Printf("  ℹ️  %d other settings are shadowed; see: gos current --explain\n", project.Shadowed)
 	}
 }
@@ -1,63 +1,132 @@
 package status
 
 import (
-	"fmt"
-
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/doctor"
+	"github.com/cristobalcontreras/gos/cmd/list"
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/spf13/cobra"
 )
 
 // CreateStatusCommand creates the status command
 func CreateStatusCommand() *cobra.Command {
-	return &cobra.Command{
+	return output.Enable(&cobra.Command{
 		Use:   "status",
 		Short: "Show Go system status",
 		Long: `Show comprehensive information about the current Go installation,
 including version manager status, installed versions, and system configuration.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			ShowStatus()
+		Example: `  gos status             # Show the system status
+  gos status -o json     # The same as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result := GetStatus()
+			if output.Structured() {
+				return output.Print(result)
+			}
+			ShowStatus(result)
+			return nil
 		},
+	})
+}
+
+// Status is the result of gos status
+type Status struct {
+	Backend     string                  `json:"backend"`
+	Backends    []Manager               `json:"backends"`
+	Go          CurrentGo               `json:"go"`
+	Versions    []list.InstalledVersion `json:"versions"`
+	Disk        []DiskUsage             `json:"disk"`
+	Environment []EnvVar                `json:"environment"`
+	Path        []PathEntry             `json:"path"`
+	Checks      []doctor.Result         `json:"checks"`  // the gos doctor environment and PATH checks
+	Project     *Project                `json:"project"` // null when no version is configured
+	Errors      []string                `json:"errors,omitempty"`
+}
+
+// GetStatus collects the system status. Problems with one part are recorded
+// in Errors and the rest is still collected.
+func GetStatus() Status {
+	var result Status
+
+	b, err := backend.Detect()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		b = backend.NewNative()
+	}
+	result.Backend = b.Name()
+
+	result.Backends = GetVersionManagers(b)
+	result.Go = GetCurrentGo(b)
+
+	result.Versions = []list.InstalledVersion{}
+	if installed, err := list.GetInstalled(b); err == nil {
+		result.Versions = installed.Versions
+	} else {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	result.Disk = GetDiskUsage(b)
+	env := doctor.NewEnv(b)
+	result.Environment = GetEnvironment()
+	result.Path = GetPathEntries(env)
+	result.Checks = GetChecks(env)
+
+	project, err := GetProjectConfig()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
 	}
+	result.Project = project
+
+	return result
 }
 
 // ShowStatus displays comprehensive Go system status
-func ShowStatus() {
-	blue := color.New(color.FgBlue)
+func ShowStatus(result Status) {
+	ui.Title.Println("📊 Go system status:")
+	ui.Out.Println("")
 
-	blue.Println("📊 Go system status:")
-	fmt.Println("")
+	for _, err := range result.Errors {
+		ui.Error.Printf("  ❌ %s\n", err)
+	}
 
 	// Check version managers
-	blue.Println("🔧 Version Managers:")
-	CheckVersionManagers()
+	ui.Title.Println("🔧 Version Managers:")
+	ShowVersionManagers(result.Backends)
 
-	fmt.Println("")
+	ui.Out.Println("")
 
 	// Current Go installation
-	blue.Println("🐹 Current Go:")
-	ShowCurrentGo()
+	ui.Title.Println("🐹 Current Go:")
+	ShowCurrentGo(result.Go)
 
-	fmt.Println("")
+	ui.Out.Println("")
 
 	// Installed versions
-	blue.Println("📦 Installed versions:")
-	CheckInstalledVersions()
+	ui.Title.Println("📦 Installed versions:")
+	ShowInstalledVersions(result.Versions)
 
-	fmt.Println("")
+	ui.Out.Println("")
 
 	// Disk space
-	blue.Println("💾 Disk space:")
-	ShowDiskUsage()
+	ui.Title.Println("💾 Disk space:")
+	ShowDiskUsage(result.Disk)
 
-	fmt.Println("")
+	ui.Out.Println("")
 
 	// Environment variables
-	blue.Println("🌍 Environment:")
-	ShowEnvironment()
+	ui.Title.Println("🌍 Environment:")
+	ShowEnvironment(result.Environment, result.Path, result.Checks)
 
-	fmt.Println("")
+	ui.Out.Println("")
 
 	// Project configuration
-	blue.Println("📁 Project configuration:")
-	ShowProjectConfig()
+	ui.Title.Println("📁 Project configuration:")
+	ShowProjectConfig(result.Project)
+}
+
+// CurrentGo is the go binary in PATH and whether it belongs to the backend
+type CurrentGo struct {
+	common.GoInfo
+	Managed bool `json:"managed"` // GOROOT is the active backend's
 }
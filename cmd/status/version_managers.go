@@ -1,107 +1,73 @@
 package status
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/list"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 )
 
-// CheckVersionManagers displays information about available version managers
-func CheckVersionManagers() {
-	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
-	yellow := color.New(color.FgYellow)
+// Manager describes one backend gos can manage toolchains with
+type Manager struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Active    bool   `json:"active"`
+	Version   string `json:"version,omitempty"` // the tool's version, when installed
+}
 
-	// Check gobrew
-	if _, err := exec.LookPath("gobrew"); err == nil {
-		green.Print("  ✅ gobrew: ")
-		if versionCmd := exec.Command("gobrew", "--version"); versionCmd.Run() == nil {
-			versionCmd.Stdout = os.Stdout
-			versionCmd.Run()
-		} else {
-			fmt.Println("installed")
+// GetVersionManagers collects the available backends
+func GetVersionManagers(active backend.Backend) []Manager {
+	managers := []Manager{}
+	for _, b := range backend.All() {
+		manager := Manager{Name: b.Name(), Installed: b.Available(), Active: b.Name() == active.Name()}
+		if manager.Installed {
+			manager.Version = backendVersion(b)
 		}
+		managers = append(managers, manager)
 	}
-
-	// Check if no version managers are installed
-	if !common.IsCommandAvailable("gobrew") {
-		red.Println("  ❌ No version managers installed")
-		yellow.Println("  💡 Run: gos setup")
-	}
+	return managers
 }
 
-// CheckInstalledVersions displays installed Go versions
-func CheckInstalledVersions() {
-	yellow := color.New(color.FgYellow)
-
-	if common.IsCommandAvailable("gobrew") {
-		// Show available versions using direct commands
-		showInstalledVersions()
-	} else {
-		yellow.Println("  No version manager installed")
-		yellow.Println("  💡 Run: gos setup")
+// ShowVersionManagers displays information about available version managers
+func ShowVersionManagers(managers []Manager) {
+	for _, manager := range managers {
+		switch {
+		case !manager.Installed:
+			ui.Out.Printf("  ➖ %s: not installed\n", manager.Name)
+		case manager.Active:
+			ui.Success.Printf("  ✅ %s: %s (active)\n", manager.Name, manager.Version)
+		default:
+			ui.Out.Printf("  ℹ️  %s: %s\n", manager.Name, manager.Version)
+		}
 	}
 }
 
-// showInstalledVersions displays installed Go versions using gobrew
-func showInstalledVersions() {
-	yellow := color.New(color.FgYellow)
-
-	if common.IsCommandAvailable("gobrew") {
-		showInstalledVersionsWithGobrew()
-	} else {
-		yellow.Println("  No version manager available")
+// backendVersion describes the backend's tool version, when it has one
+func backendVersion(b backend.Backend) string {
+	if b.Name() == "native" {
+		return "built in (" + b.Root() + ")"
 	}
-}
-
-// showInstalledVersionsWithGobrew lists installed versions using gobrew
-func showInstalledVersionsWithGobrew() {
-	yellow := color.New(color.FgYellow)
-
-	output, err := exec.Command("gobrew", "ls").Output()
-	if err != nil {
-		yellow.Printf("  Error listing gobrew versions: %v\n", err)
-		return
+	if output, err := runner.Output(b.Name(), "--version"); err == nil {
+		return strings.TrimSpace(output)
 	}
-
-	lines := parseVersionOutput(output)
-	if len(lines) == 0 {
-		yellow.Println("  No Go versions installed")
-		return
-	}
-
-	displayVersions(lines)
+	return "installed"
 }
 
-// parseVersionOutput parses the output from version manager commands
-func parseVersionOutput(output []byte) []string {
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var validLines []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			validLines = append(validLines, line)
-		}
+// ShowInstalledVersions displays installed Go versions
+func ShowInstalledVersions(versions []list.InstalledVersion) {
+	if len(versions) == 0 {
+		ui.Out.Println("  No Go versions installed")
+		ui.Hint.Println("  💡 Run: gos install latest")
+		return
 	}
 
-	return validLines
-}
-
-// displayVersions displays the parsed version lines with appropriate formatting
-func displayVersions(lines []string) {
-	green := color.New(color.FgGreen)
-
-	for _, line := range lines {
-		if strings.Contains(line, "*") {
-			cleanLine := strings.Replace(line, "*", "", -1)
-			green.Printf("  ✅ %s (current)\n", cleanLine)
+	for _, version := range versions {
+		if version.Current {
+			ui.Success.Printf("  ✅ %s (current)\n", version.Version)
 		} else {
-			fmt.Printf("  📦 %s\n", line)
+			ui.Out.Printf("  📦 %s\n", version.Version)
 		}
 	}
 }
@@ -0,0 +1,60 @@
+package testenv
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+// Home returns the fake home directory tests run in
+func Home() string {
+	return filepath.Join(string(filepath.Separator), "home", "gopher")
+}
+
+// Sandbox points GOS_HOME at the fake home and replaces the filesystem and
+// the command runner with in-memory fakes until the test ends. GOROOT and
+// GOS_VERSION are cleared so the caller's shell does not leak in. dirs are
+// created below the home directory.
+func Sandbox(t testing.TB, dirs ...string) (home string, mem *fsys.Mem, fake *runner.Fake) {
+	t.Helper()
+	home = Home()
+	t.Setenv(common.HomeVar, home)
+	t.Setenv("GOROOT", "")
+	t.Setenv(resolver.EnvVar, "")
+
+	mem = fsys.NewMem()
+	t.Cleanup(fsys.Replace(mem))
+	fake = runner.NewFake()
+	t.Cleanup(runner.Replace(fake))
+
+	for _, dir := range append([]string{""}, dirs...) {
+		if err := mem.MkdirAll(filepath.Join(home, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home, mem, fake
+}
+
+// Backend is the native backend with fixed version lists, so tests need
+// neither real toolchains nor a current link. Create it after Sandbox so its
+// directories are below the fake home.
+type Backend struct {
+	*backend.Native
+	Installed []string
+	Remote    []string
+	Version   string // the current version
+}
+
+// NewBackend returns a Backend with the given versions installed
+func NewBackend(installed ...string) *Backend {
+	return &Backend{Native: backend.NewNative(), Installed: installed}
+}
+
+func (b *Backend) ListInstalled() ([]string, error) { return b.Installed, nil }
+func (b *Backend) ListRemote() ([]string, error)    { return b.Remote, nil }
+func (b *Backend) Current() (string, error)         { return b.Version, nil }
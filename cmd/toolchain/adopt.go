@@ -0,0 +1,119 @@
+package toolchain
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// How Adopt brings an existing toolchain into the versions directory
+const (
+	AdoptCopy = "copy" // copy the files, leaving the original alone
+	AdoptMove = "move" // move the directory, removing the original
+	AdoptLink = "link" // link to the original where it is
+)
+
+// AdoptModes lists the supported Adopt modes
+var AdoptModes = []string{AdoptCopy, AdoptMove, AdoptLink}
+
+// Adopt makes the Go installation at goroot an installed version, by copying
+// it, moving it or linking to it in place. Adopting an already present
+// version is a no-op.
+func (i *Installer) Adopt(goroot, version, mode string) error {
+	version = NormalizeVersion(version)
+	if version == "" {
+		return fmt.Errorf("no version specified")
+	}
+	if i.IsInstalled(version) {
+		return nil
+	}
+	if err := os.MkdirAll(i.VersionsDir(), 0755); err != nil {
+		return fmt.Errorf("creating versions directory: %w", err)
+	}
+
+	switch mode {
+	case AdoptLink:
+		if err := os.Symlink(goroot, i.VersionDir(version)); err != nil {
+			return fmt.Errorf("linking %s: %w", goroot, err)
+		}
+		return nil
+	case AdoptMove:
+		if err := os.Rename(goroot, i.VersionDir(version)); err == nil {
+			return nil
+		}
+		// Another filesystem: copy, then remove the original
+		if err := i.copyIn(goroot, version); err != nil {
+			return err
+		}
+		return os.RemoveAll(goroot)
+	case AdoptCopy:
+		return i.copyIn(goroot, version)
+	}
+	return fmt.Errorf("unknown adopt mode %q", mode)
+}
+
+// copyIn copies goroot into place through a staging directory
+func (i *Installer) copyIn(goroot, version string) error {
+	return i.stage("adopt", version, func(dir string) error {
+		if err := copyTree(goroot, dir); err != nil {
+			return fmt.Errorf("copying %s: %w", goroot, err)
+		}
+		return nil
+	})
+}
+
+// copyTree copies the files, directories and symlinks below src into dest,
+// leaving out directories named in skip
+func copyTree(src, dest string, skip ...string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		switch {
+		case entry.IsDir() && path != src && slices.Contains(skip, entry.Name()):
+			return filepath.SkipDir
+		case entry.IsDir():
+			return os.MkdirAll(target, 0755)
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !entry.Type().IsRegular():
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return writeFile(target, file, info.Mode())
+	})
+}
+
+// isVersionDir reports whether an entry of the versions directory is a
+// toolchain: a directory, or a link to one made by Adopt
+func (i *Installer) isVersionDir(entry fs.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(i.VersionsDir(), entry.Name()))
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,189 @@
+package toolchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/runner"
+)
+
+// BuildFile holds the BuildInfo of a toolchain built from source, in its GOROOT
+const BuildFile = ".gos-build.json"
+
+// BuildOptions describes a toolchain to build from source
+type BuildOptions struct {
+	Name             string // the name the toolchain is installed under
+	Source           string // a Go source checkout or source tarball
+	Bootstrap        string // GOROOT of the Go that compiles it (GOROOT_BOOTSTRAP)
+	BootstrapVersion string
+
+	// Prepared, when set, is called once the source is in place and the
+	// build starts
+	Prepared func()
+	// Log receives the output of make.bash as it is produced
+	Log io.Writer
+}
+
+// BuildInfo records how a toolchain was built
+type BuildInfo struct {
+	Source    string    `json:"source"`
+	Commit    string    `json:"commit,omitempty"`
+	Bootstrap string    `json:"bootstrap"`
+	BuiltAt   time.Time `json:"built_at"`
+	GoVersion string    `json:"go_version,omitempty"`
+}
+
+// Build compiles a Go source checkout or tarball with make.bash and installs
+// the result under opts.Name. The source is copied (without .git) or
+// extracted next to the versions first, so the checkout is left untouched
+// and a failed build leaves nothing behind.
+func (i *Installer) Build(opts BuildOptions) (BuildInfo, error) {
+	if err := CheckName(opts.Name); err != nil {
+		return BuildInfo{}, err
+	}
+	if _, err := os.Lstat(i.VersionDir(opts.Name)); err == nil {
+		return BuildInfo{}, fmt.Errorf("%s is already installed; remove it first", opts.Name)
+	}
+	if err := os.MkdirAll(i.VersionsDir(), 0755); err != nil {
+		return BuildInfo{}, fmt.Errorf("creating versions directory: %w", err)
+	}
+
+	info := BuildInfo{Source: opts.Source, Bootstrap: opts.BootstrapVersion}
+	err := i.stage("build", opts.Name, func(dir string) error {
+		var err error
+		if info.Commit, err = prepareSource(opts.Source, dir, opts.Name); err != nil {
+			return err
+		}
+		if opts.Prepared != nil {
+			opts.Prepared()
+		}
+		return makeBash(dir, i.VersionDir(opts.Name), opts.Bootstrap, opts.Log)
+	})
+	if err != nil {
+		return BuildInfo{}, err
+	}
+
+	info.BuiltAt = time.Now().UTC().Truncate(time.Second)
+	goBinary := filepath.Join(i.VersionDir(opts.Name), "bin", "go"+exeSuffix())
+	if output, err := runner.Output(goBinary, "version"); err == nil {
+		info.GoVersion = strings.TrimSpace(output)
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return info, err
+	}
+	return info, os.WriteFile(filepath.Join(i.VersionDir(opts.Name), BuildFile), data, 0644)
+}
+
+// ReadBuildInfo returns how a version was built; ok is false for versions
+// that were not built from source
+func (i *Installer) ReadBuildInfo(version string) (info BuildInfo, ok bool) {
+	data, err := os.ReadFile(filepath.Join(i.VersionDir(version), BuildFile))
+	if err != nil {
+		return BuildInfo{}, false
+	}
+	return info, json.Unmarshal(data, &info) == nil
+}
+
+// prepareSource puts the Go source tree at source into dest and returns
+// its git commit, when it is a checkout
+func prepareSource(source, dest, name string) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		if err := extractArchive(source, dest); err != nil {
+			return "", fmt.Errorf("extracting %s: %w", source, err)
+		}
+		return "", checkSourceTree(dest, source)
+	}
+
+	if err := checkSourceTree(source, source); err != nil {
+		return "", err
+	}
+	if err := copyTree(source, dest, ".git"); err != nil {
+		return "", fmt.Errorf("copying %s: %w", source, err)
+	}
+
+	commit := ""
+	if output, err := runner.Output("git", "-C", source, "rev-parse", "HEAD"); err == nil {
+		commit = strings.TrimSpace(output)
+	}
+
+	// Without .git, make.bash needs a VERSION file to name the toolchain
+	version := filepath.Join(dest, "VERSION")
+	if _, err := os.Stat(version); os.IsNotExist(err) {
+		content := "devel " + name
+		if len(commit) >= 12 {
+			content += "-" + commit[:12]
+		}
+		if err := os.WriteFile(version, []byte(content+"\n"), 0644); err != nil {
+			return "", err
+		}
+	}
+	return commit, nil
+}
+
+// checkSourceTree fails unless root holds the Go sources
+func checkSourceTree(root, source string) error {
+	if _, err := os.Stat(filepath.Join(root, "src", makeScript())); err != nil {
+		return fmt.Errorf("%s is not a Go source tree: src/%s is missing", source, makeScript())
+	}
+	return nil
+}
+
+// makeBash runs make.bash (make.bat on Windows) in goroot with the bootstrap
+// toolchain, streaming its output to log
+func makeBash(goroot, final, bootstrap string, log io.Writer) error {
+	if log == nil {
+		log = io.Discard
+	}
+	cmd := runner.Command{
+		Name: filepath.Join(goroot, "src", makeScript()),
+		Dir:  filepath.Join(goroot, "src"),
+		Env: []string{
+			"GOROOT_BOOTSTRAP=" + bootstrap,
+			"GOROOT_FINAL=" + final,
+			"GOROOT=",
+			"GOTOOLCHAIN=local",
+		},
+		Stdout: log,
+		Stderr: log,
+	}
+	if runtime.GOOS == "windows" {
+		cmd.Args = []string{"/c", cmd.Name}
+		cmd.Name = "cmd"
+	}
+	// The log already holds the output; keep the error to the exit code
+	if result, err := runner.Default.Run(context.Background(), cmd); err != nil {
+		if result.ExitCode < 0 {
+			return fmt.Errorf("running %s: %w", makeScript(), err)
+		}
+		return fmt.Errorf("%s failed with exit code %d", makeScript(), result.ExitCode)
+	}
+	return nil
+}
+
+// makeScript names the build script of the platform
+func makeScript() string {
+	if runtime.GOOS == "windows" {
+		return "make.bat"
+	}
+	return "make.bash"
+}
+
+// exeSuffix is the file extension of executables on the platform
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package toolchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/releases"
+)
+
+// download fetches a release file into a temporary file and returns its path.
+// The file's SHA-256 is checked against the release index; a mismatching
+// download is deleted and reported as a *ChecksumError.
+func (i *Installer) download(file releases.File) (string, error) {
+	url := i.Index.DownloadURL(file)
+
+	resp, err := i.Index.HTTP.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%s was not found at %s: %w", file.Filename, url, &errs.VersionNotFoundError{Spec: strings.TrimPrefix(file.Version, "go")})
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	out, err := os.CreateTemp("", "gos-download-*")
+	if err != nil {
+		return "", fmt.Errorf("creating download file: %w", err)
+	}
+
+	var body io.Reader = resp.Body
+	if i.Progress != nil {
+		body = i.Progress(resp.ContentLength, body)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), body); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", fmt.Errorf("downloading %s: %w", url, err)
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+
+	if err := verifySHA256(file.Filename, file.SHA256, hex.EncodeToString(hash.Sum(nil))); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
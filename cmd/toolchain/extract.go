@@ -0,0 +1,211 @@
+package toolchain
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractArchive unpacks a Go release archive into dest, stripping the
+// leading "go/" directory that official archives wrap everything in.
+func extractArchive(archive, dest string) error {
+	if strings.HasSuffix(archive, ".zip") || isZip(archive) {
+		return extractZip(archive, dest)
+	}
+	return extractTarGz(archive, dest)
+}
+
+// isZip sniffs the archive header for the zip magic number
+func isZip(archive string) bool {
+	file, err := os.Open(archive)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(file, magic); err != nil {
+		return false
+	}
+	return string(magic) == "PK\x03\x04"
+}
+
+// extractTarGz unpacks a .tar.gz archive
+func extractTarGz(archive, dest string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, ok, err := targetPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := checkLink(dest, target, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZip unpacks a .zip archive
+func extractZip(archive, dest string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		target, ok, err := targetPath(dest, entry.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if entry.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		src, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, src, entry.Mode())
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targetPath maps an archive entry to its destination, stripping the "go/"
+// prefix and rejecting entries that would escape dest
+func targetPath(dest, name string) (string, bool, error) {
+	name = filepath.ToSlash(name)
+	name = strings.TrimPrefix(name, "./")
+	name = strings.TrimPrefix(name, "go/")
+	if name == "" || name == "go" {
+		return "", false, nil
+	}
+
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if !within(dest, target) {
+		return "", false, fmt.Errorf("archive entry %q escapes destination", name)
+	}
+	// An entry below an extracted link would be written wherever it points
+	rel, _ := filepath.Rel(dest, filepath.Dir(target))
+	dir := filepath.Clean(dest)
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		if dir = filepath.Join(dir, part); isLink(dir) {
+			return "", false, fmt.Errorf("archive entry %q is below a link", name)
+		}
+	}
+	return target, true, nil
+}
+
+// checkLink rejects a symlink entry whose target is absolute, goes through
+// another link or resolves outside dest. Together with targetPath, this
+// keeps every extracted link pointing inside dest, however links chain.
+func checkLink(dest, target, linkname string) error {
+	if filepath.IsAbs(linkname) || strings.HasPrefix(filepath.ToSlash(linkname), "/") {
+		return fmt.Errorf("archive link %q points to an absolute path", linkname)
+	}
+	dir := filepath.Dir(target)
+	for _, part := range strings.Split(filepath.ToSlash(linkname), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			dir = filepath.Dir(dir)
+			continue
+		}
+		if dir = filepath.Join(dir, part); isLink(dir) {
+			return fmt.Errorf("archive link %q goes through another link", linkname)
+		}
+	}
+	if !within(dest, dir) {
+		return fmt.Errorf("archive link %q escapes destination", linkname)
+	}
+	return nil
+}
+
+// within reports whether path is below dest
+func within(dest, path string) bool {
+	return strings.HasPrefix(path, filepath.Clean(dest)+string(os.PathSeparator))
+}
+
+// isLink reports whether path is a symlink on disk
+func isLink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// writeFile writes an archive entry to disk, creating parent directories
+func writeFile(target string, src io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if mode.Perm() == 0 {
+		mode = 0644
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,251 @@
+package toolchain
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/releases"
+)
+
+// Installer downloads official Go release archives and manages them in a
+// gos-owned versions directory with a "current" link pointing at the active one.
+type Installer struct {
+	Root   string           // gos state directory, e.g. ~/.gos
+	Index  *releases.Client // release index used to resolve and locate archives
+	GOOS   string           // target operating system
+	GOARCH string           // target architecture
+
+	// Progress, when set, wraps the download stream so callers can render progress.
+	Progress func(total int64, r io.Reader) io.Reader
+}
+
+// NewInstaller creates an installer for the running platform using the default
+// gos home and the configured release index (see releases.NewClient)
+func NewInstaller() *Installer {
+	return &Installer{
+		Root:   common.GetGosHome(),
+		Index:  releases.NewClient(),
+		GOOS:   runtime.GOOS,
+		GOARCH: runtime.GOARCH,
+	}
+}
+
+// NormalizeVersion strips the optional "go" or "v" prefix from a version
+// string. Names of linked toolchains, such as gotip, are kept as they are.
+func NormalizeVersion(version string) string {
+	if normalized := goversion.Normalize(version); goversion.IsValid(normalized) {
+		return normalized
+	}
+	return strings.TrimSpace(version)
+}
+
+// VersionsDir returns the directory holding all installed toolchains
+func (i *Installer) VersionsDir() string {
+	return filepath.Join(i.Root, "versions")
+}
+
+// VersionDir returns the GOROOT of an installed version
+func (i *Installer) VersionDir(version string) string {
+	return filepath.Join(i.VersionsDir(), NormalizeVersion(version))
+}
+
+// CurrentLink returns the path of the link pointing at the active toolchain
+func (i *Installer) CurrentLink() string {
+	return filepath.Join(i.Root, "current")
+}
+
+// ArchiveName returns the official archive file name for a version on the target platform
+func (i *Installer) ArchiveName(version string) string {
+	ext := "tar.gz"
+	if i.GOOS == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("go%s.%s-%s.%s", NormalizeVersion(version), i.GOOS, i.GOARCH, ext)
+}
+
+// ArchiveURL returns the download URL for a version on the target platform
+func (i *Installer) ArchiveURL(version string) string {
+	return i.Index.DownloadURL(i.archiveFile(version))
+}
+
+// archiveFile describes the platform archive of a version without consulting the index
+func (i *Installer) archiveFile(version string) releases.File {
+	return releases.File{
+		Filename: i.ArchiveName(version),
+		OS:       i.GOOS,
+		Arch:     i.GOARCH,
+		Version:  "go" + NormalizeVersion(version),
+		Kind:     "archive",
+	}
+}
+
+// resolveArchive looks a version up in the release index and returns its platform archive
+func (i *Installer) resolveArchive(version string) (releases.File, error) {
+	release, err := i.Index.Find(version)
+	if err != nil {
+		return releases.File{}, err
+	}
+
+	file, ok := release.Archive(i.GOOS, i.GOARCH)
+	if !ok {
+		return releases.File{}, &errs.VersionNotFoundError{Spec: version, Platform: i.GOOS + "/" + i.GOARCH}
+	}
+	return file, nil
+}
+
+// IsInstalled reports whether a version is present in the versions directory
+func (i *Installer) IsInstalled(version string) bool {
+	info, err := os.Stat(i.VersionDir(version))
+	return err == nil && info.IsDir()
+}
+
+// Install downloads and extracts a Go version. Installing an already present
+// version is a no-op.
+func (i *Installer) Install(version string) error {
+	version = NormalizeVersion(version)
+	if version == "" {
+		return fmt.Errorf("no version specified")
+	}
+	if i.IsInstalled(version) {
+		return nil
+	}
+
+	if err := os.MkdirAll(i.VersionsDir(), 0755); err != nil {
+		return fmt.Errorf("creating versions directory: %w", err)
+	}
+
+	file, err := i.resolveArchive(version)
+	if err != nil {
+		return err
+	}
+
+	archive, err := i.download(file)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+
+	return i.stage("install", version, func(dir string) error {
+		if err := extractArchive(archive, dir); err != nil {
+			return fmt.Errorf("extracting %s: %w", file.Filename, err)
+		}
+		return nil
+	})
+}
+
+// stage fills a staging directory next to the versions and renames it to
+// version, so an interrupted install, copy or build never leaves a
+// half-populated version behind. kind names the staging directory.
+func (i *Installer) stage(kind, version string, fill func(dir string) error) error {
+	staging, err := os.MkdirTemp(i.VersionsDir(), "."+kind+"-"+version+"-")
+	if err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := fill(staging); err != nil {
+		return err
+	}
+	// MkdirTemp creates the staging directory private to the user
+	if err := os.Chmod(staging, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(staging, i.VersionDir(version)); err != nil {
+		return fmt.Errorf("finalizing Go %s: %w", version, err)
+	}
+	return nil
+}
+
+// Uninstall removes an installed version. The active version cannot be removed.
+func (i *Installer) Uninstall(version string) error {
+	version = NormalizeVersion(version)
+	if !i.IsInstalled(version) {
+		return &errs.VersionNotFoundError{Spec: version, Installed: true}
+	}
+	if current, err := i.Current(); err == nil && current == version {
+		return fmt.Errorf("Go %s is the active version; switch to another version first", version)
+	}
+	return os.RemoveAll(i.VersionDir(version))
+}
+
+// Use points the current link at an installed version
+func (i *Installer) Use(version string) error {
+	version = NormalizeVersion(version)
+	if !i.IsInstalled(version) {
+		return &errs.VersionNotFoundError{Spec: version, Installed: true}
+	}
+
+	// Build the new link beside the old one and rename it into place
+	tmpLink := i.CurrentLink() + ".tmp"
+	os.Remove(tmpLink)
+	if err := os.Symlink(i.VersionDir(version), tmpLink); err != nil {
+		return fmt.Errorf("creating current link: %w", err)
+	}
+	if err := os.Rename(tmpLink, i.CurrentLink()); err != nil {
+		os.Remove(tmpLink)
+		return fmt.Errorf("activating Go %s: %w", version, err)
+	}
+	return nil
+}
+
+// Current returns the version the current link points at
+func (i *Installer) Current() (string, error) {
+	target, err := os.Readlink(i.CurrentLink())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(target), nil
+}
+
+// ListInstalled returns all installed versions, oldest first
+func (i *Installer) ListInstalled() ([]string, error) {
+	entries, err := os.ReadDir(i.VersionsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if i.isVersionDir(entry) && !strings.HasPrefix(entry.Name(), ".") {
+			versions = append(versions, entry.Name())
+		}
+	}
+	goversion.Sort(versions)
+	return versions, nil
+}
+
+// LatestVersion asks the release index for the newest stable version
+func (i *Installer) LatestVersion() (string, error) {
+	release, err := i.Index.Latest()
+	if err != nil {
+		return "", err
+	}
+	return release.Number(), nil
+}
+
+// RemoteVersions returns every version in the release index with an archive
+// for the target platform, newest first
+func (i *Installer) RemoteVersions() ([]string, error) {
+	releases, err := i.Index.Releases()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, release := range releases {
+		if _, ok := release.Archive(i.GOOS, i.GOARCH); ok {
+			versions = append(versions, release.Number())
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,483 @@
+package toolchain
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/releases"
+)
+
+// fakeArchive builds a tar.gz laid out like an official Go release
+func fakeArchive(t *testing.T, version string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	files := map[string]string{
+		"go/VERSION": "go" + version + "\n",
+		"go/bin/go":  "#!/bin/sh\necho go version go" + version + "\n",
+	}
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// newTestInstaller returns an installer pointed at a local server serving a
+// release index and fake archives
+func newTestInstaller(t *testing.T, versions ...string) *Installer {
+	t.Helper()
+
+	installer := &Installer{Root: t.TempDir(), GOOS: "linux", GOARCH: "amd64"}
+
+	mux := http.NewServeMux()
+	index := []releases.Release{{Version: "go1.23rc1", Stable: false}}
+	for _, version := range versions {
+		archive := fakeArchive(t, version)
+		name := installer.ArchiveName(version)
+		mux.HandleFunc("/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(archive)
+		})
+
+		sum := sha256.Sum256(archive)
+		file := releases.File{Filename: name, OS: "linux", Arch: "amd64", Version: "go" + version,
+			SHA256: hex.EncodeToString(sum[:]), Kind: "archive"}
+		index = append(index, releases.Release{Version: "go" + version, Stable: true, Files: []releases.File{file}})
+	}
+	// Listed in the index but never published, to exercise download failures
+	index = append(index, releases.Release{Version: "go1.0.0", Stable: true, Files: []releases.File{
+		{Filename: installer.ArchiveName("1.0.0"), OS: "linux", Arch: "amd64", Version: "go1.0.0", Kind: "archive"},
+	}})
+
+	// Published with a checksum that does not match its content
+	tampered := fakeArchive(t, "1.0.1")
+	mux.HandleFunc("/"+installer.ArchiveName("1.0.1"), func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tampered)
+	})
+	index = append(index, releases.Release{Version: "go1.0.1", Stable: true, Files: []releases.File{
+		{Filename: installer.ArchiveName("1.0.1"), OS: "linux", Arch: "amd64", Version: "go1.0.1",
+			SHA256: strings.Repeat("0", 64), Kind: "archive"},
+	}})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mode") == "json" {
+			json.NewEncoder(w).Encode(index)
+			return
+		}
+		http.NotFound(w, r)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	installer.Index = &releases.Client{BaseURL: server.URL + "/", HTTP: http.DefaultClient}
+	return installer
+}
+
+func TestInstall(t *testing.T) {
+	t.Run("extracts archive into versions directory", func(t *testing.T) {
+		installer := newTestInstaller(t, "1.21.5")
+
+		if err := installer.Install("go1.21.5"); err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+
+		goBin := filepath.Join(installer.VersionDir("1.21.5"), "bin", "go")
+		if _, err := os.Stat(goBin); err != nil {
+			t.Errorf("expected %s to exist: %v", goBin, err)
+		}
+		if info, err := os.Stat(installer.VersionDir("1.21.5")); err != nil {
+			t.Error(err)
+		} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+			t.Errorf("version directory mode = %v, want 0755", info.Mode().Perm())
+		}
+	})
+
+	t.Run("version missing from the index is rejected", func(t *testing.T) {
+		installer := newTestInstaller(t)
+
+		if err := installer.Install("9.9.9"); err == nil {
+			t.Fatal("expected an error for a missing version")
+		}
+	})
+
+	t.Run("failed download leaves nothing behind", func(t *testing.T) {
+		installer := newTestInstaller(t)
+
+		if err := installer.Install("1.0.0"); err == nil {
+			t.Fatal("expected an error for an unpublished archive")
+		}
+
+		versions, _ := installer.ListInstalled()
+		if len(versions) != 0 {
+			t.Errorf("expected no installed versions, got %v", versions)
+		}
+	})
+}
+
+func TestExtractLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+
+	// archive writes a tar.gz holding the given links, in order, and a file
+	// below the first one
+	archive := func(t *testing.T, links ...[2]string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, link := range links {
+			tw.WriteHeader(&tar.Header{Name: "go/" + link[0], Linkname: link[1], Typeflag: tar.TypeSymlink})
+		}
+		tw.WriteHeader(&tar.Header{Name: "go/" + links[0][0] + "/nested", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+		tw.Write([]byte("data"))
+		tw.Close()
+		gz.Close()
+
+		path := filepath.Join(t.TempDir(), "go.tar.gz")
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	for _, linkname := range []string{"/tmp", "../..", "bin/../../outside"} {
+		t.Run(linkname, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "dest")
+			if err := extractArchive(archive(t, [2]string{"link", linkname}), dest); err == nil {
+				t.Errorf("a link to %q was extracted", linkname)
+			}
+		})
+	}
+
+	t.Run("chained links", func(t *testing.T) {
+		for name, links := range map[string][][2]string{
+			"link below a link":   {{"d/e/a", ".."}, {"d/e/a/b", "../../outside"}},
+			"link through a link": {{"d/e/a", ".."}, {"c", "d/e/a/../../../outside"}},
+		} {
+			dest := filepath.Join(t.TempDir(), "dest")
+			if err := extractArchive(archive(t, links...), dest); err == nil {
+				t.Errorf("%s: the links were extracted", name)
+			}
+		}
+	})
+
+	t.Run("links within the destination are kept", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "dest")
+		if err := os.MkdirAll(filepath.Join(dest, "pkg"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		err := extractArchive(archive(t, [2]string{"link", "pkg"}), dest)
+		if err == nil || !strings.Contains(err.Error(), "below a link") {
+			t.Errorf("a file below the link was extracted: %v", err)
+		}
+		if target, err := os.Readlink(filepath.Join(dest, "link")); err != nil || target != "pkg" {
+			t.Errorf("link = %q, %v", target, err)
+		}
+	})
+}
+
+func TestChecksumVerification(t *testing.T) {
+	installer := newTestInstaller(t)
+
+	err := installer.Install("1.0.1")
+
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("expected a ChecksumError, got %v", err)
+	}
+	if checksumErr.Expected != strings.Repeat("0", 64) || checksumErr.Actual == "" {
+		t.Errorf("expected both digests in the error, got %+v", checksumErr)
+	}
+	if installer.IsInstalled("1.0.1") {
+		t.Error("tampered archive must not be installed")
+	}
+
+	entries, _ := os.ReadDir(installer.VersionsDir())
+	if len(entries) != 0 {
+		t.Errorf("expected no leftovers in versions directory, found %d entries", len(entries))
+	}
+}
+
+func TestUseAndUninstall(t *testing.T) {
+	installer := newTestInstaller(t, "1.21.5", "1.22.0")
+	for _, version := range []string{"1.21.5", "1.22.0"} {
+		if err := installer.Install(version); err != nil {
+			t.Fatalf("Install %s failed: %v", version, err)
+		}
+	}
+
+	t.Run("use switches the current link", func(t *testing.T) {
+		if err := installer.Use("1.22.0"); err != nil {
+			t.Fatalf("Use failed: %v", err)
+		}
+		if current, _ := installer.Current(); current != "1.22.0" {
+			t.Errorf("expected current 1.22.0, got %q", current)
+		}
+	})
+
+	t.Run("active version cannot be removed", func(t *testing.T) {
+		if err := installer.Uninstall("1.22.0"); err == nil {
+			t.Error("expected an error removing the active version")
+		}
+	})
+
+	t.Run("inactive version is removed", func(t *testing.T) {
+		if err := installer.Uninstall("1.21.5"); err != nil {
+			t.Fatalf("Uninstall failed: %v", err)
+		}
+		if installer.IsInstalled("1.21.5") {
+			t.Error("expected 1.21.5 to be removed")
+		}
+	})
+}
+
+func TestLatestVersion(t *testing.T) {
+	installer := newTestInstaller(t, "1.21.5")
+
+	latest, err := installer.LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion failed: %v", err)
+	}
+	if latest != "1.21.5" {
+		t.Errorf("expected latest stable 1.21.5, got %q", latest)
+	}
+}
+
+// fakeGOROOT lays out a directory like an unpacked Go release
+func fakeGOROOT(t *testing.T, version string) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "go"+version)
+	if err := os.MkdirAll(filepath.Join(root, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "VERSION"), []byte("go"+version+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bin", "go"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestAdopt(t *testing.T) {
+	for _, mode := range AdoptModes {
+		t.Run(mode, func(t *testing.T) {
+			installer := &Installer{Root: t.TempDir()}
+			root := fakeGOROOT(t, "1.21.5")
+
+			if err := installer.Adopt(root, "go1.21.5", mode); err != nil {
+				t.Fatalf("Adopt failed: %v", err)
+			}
+			if versions, _ := installer.ListInstalled(); len(versions) != 1 || versions[0] != "1.21.5" {
+				t.Fatalf("ListInstalled() = %v, want [1.21.5]", versions)
+			}
+			data, err := os.ReadFile(filepath.Join(installer.VersionDir("1.21.5"), "VERSION"))
+			if err != nil || string(data) != "go1.21.5\n" {
+				t.Errorf("VERSION = %q, %v", data, err)
+			}
+			info, err := os.Stat(filepath.Join(installer.VersionDir("1.21.5"), "bin", "go"))
+			if err != nil || info.Mode().Perm()&0100 == 0 {
+				t.Errorf("bin/go is not executable: %v", err)
+			}
+
+			_, err = os.Stat(root)
+			if kept := err == nil; kept != (mode != AdoptMove) {
+				t.Errorf("original kept = %v after %s", kept, mode)
+			}
+		})
+	}
+
+	t.Run("a linked version is uninstalled without touching the original", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		root := fakeGOROOT(t, "1.22.0")
+		if err := installer.Adopt(root, "1.22.0", AdoptLink); err != nil {
+			t.Fatalf("Adopt failed: %v", err)
+		}
+		if err := installer.Uninstall("1.22.0"); err != nil {
+			t.Fatalf("Uninstall failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "VERSION")); err != nil {
+			t.Errorf("original removed: %v", err)
+		}
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		if err := installer.Adopt(fakeGOROOT(t, "1.22.0"), "1.22.0", "steal"); err == nil {
+			t.Error("expected an error for an unknown mode")
+		}
+	})
+}
+
+func TestLink(t *testing.T) {
+	t.Run("names", func(t *testing.T) {
+		for _, name := range []string{"corp-1.22-fips", "gotip", "patched"} {
+			if err := CheckName(name); err != nil {
+				t.Errorf("CheckName(%q) = %v", name, err)
+			}
+		}
+		for _, name := range []string{"", ".hidden", "a/b", "1.22", "go1.21.5", "latest", ">=1.21"} {
+			if err := CheckName(name); err == nil {
+				t.Errorf("CheckName(%q) accepted a bad name", name)
+			}
+		}
+	})
+
+	t.Run("link, use and unlink", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		root := fakeGOROOT(t, "1.22.0")
+		if err := installer.Link("gotip", root); err != nil {
+			t.Fatalf("Link failed: %v", err)
+		}
+		if err := installer.Link("gotip", root); err == nil {
+			t.Error("expected an error linking a name twice")
+		}
+		if target, ok := installer.LinkTarget("gotip"); !ok || target != root {
+			t.Errorf("LinkTarget() = %q, %v", target, ok)
+		}
+
+		if err := installer.Use("gotip"); err != nil {
+			t.Fatalf("Use failed: %v", err)
+		}
+		if current, _ := installer.Current(); current != "gotip" {
+			t.Errorf("expected current gotip, got %q", current)
+		}
+		if err := installer.Unlink("gotip"); err == nil {
+			t.Error("expected an error unlinking the active version")
+		}
+
+		os.Remove(installer.CurrentLink())
+		if err := installer.Unlink("gotip"); err != nil {
+			t.Fatalf("Unlink failed: %v", err)
+		}
+		if installer.IsInstalled("gotip") {
+			t.Error("expected gotip to be unlinked")
+		}
+		if _, err := os.Stat(filepath.Join(root, "VERSION")); err != nil {
+			t.Errorf("original removed: %v", err)
+		}
+	})
+
+	t.Run("installed versions cannot be unlinked", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		if err := installer.Adopt(fakeGOROOT(t, "1.21.5"), "1.21.5", AdoptCopy); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := installer.LinkTarget("1.21.5"); ok {
+			t.Error("LinkTarget() reported a copied version as linked")
+		}
+		if err := installer.Unlink("1.21.5"); err == nil {
+			t.Error("expected an error unlinking an installed version")
+		}
+	})
+}
+
+// fakeSource lays out a Go checkout whose make.bash runs script and then
+// writes a bin/go reporting the VERSION file
+func fakeSource(t *testing.T, script string) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "go")
+	for _, dir := range []string{"src", ".git"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref: refs/heads/master\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	makeBash := "#!/bin/sh\nset -e\n" + script + `
+mkdir -p ../bin
+printf '#!/bin/sh\necho "go version %s %s"\n' "$(head -n 1 ../VERSION)" "$GOROOT_BOOTSTRAP" > ../bin/go
+chmod +x ../bin/go
+echo "Installed Go"
+`
+	if err := os.WriteFile(filepath.Join(root, "src", "make.bash"), []byte(makeBash), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestBuild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("make.bash needs a POSIX shell")
+	}
+
+	t.Run("builds a checkout and records how", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		source := fakeSource(t, "")
+		var log bytes.Buffer
+		info, err := installer.Build(BuildOptions{
+			Name: "gotip", Source: source, Bootstrap: "/opt/go1.22", BootstrapVersion: "1.22.1", Log: &log,
+		})
+		if err != nil {
+			t.Fatalf("Build failed: %v\n%s", err, log.String())
+		}
+
+		if !strings.Contains(log.String(), "Installed Go") {
+			t.Errorf("log = %q, want the make.bash output", log.String())
+		}
+		if !strings.HasPrefix(info.GoVersion, "go version devel gotip") || !strings.HasSuffix(info.GoVersion, "/opt/go1.22") {
+			t.Errorf("GoVersion = %q", info.GoVersion)
+		}
+		if _, err := os.Stat(filepath.Join(installer.VersionDir("gotip"), ".git")); !os.IsNotExist(err) {
+			t.Error(".git was copied into the version")
+		}
+		if _, err := os.Stat(filepath.Join(source, "bin")); !os.IsNotExist(err) {
+			t.Error("the checkout was built in place")
+		}
+
+		recorded, ok := installer.ReadBuildInfo("gotip")
+		if !ok || recorded.Source != source || recorded.Bootstrap != "1.22.1" || recorded.BuiltAt.IsZero() {
+			t.Errorf("ReadBuildInfo() = %+v, %v", recorded, ok)
+		}
+		if versions, _ := installer.ListInstalled(); len(versions) != 1 || versions[0] != "gotip" {
+			t.Errorf("ListInstalled() = %v", versions)
+		}
+		if _, err := installer.Build(BuildOptions{Name: "gotip", Source: source}); err == nil {
+			t.Error("expected an error building over an installed name")
+		}
+	})
+
+	t.Run("a failed build leaves nothing behind", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		_, err := installer.Build(BuildOptions{Name: "broken", Source: fakeSource(t, "exit 3")})
+		if err == nil || !strings.Contains(err.Error(), "exit code 3") {
+			t.Fatalf("Build() error = %v", err)
+		}
+		if entries, _ := os.ReadDir(installer.VersionsDir()); len(entries) != 0 {
+			t.Errorf("versions directory holds %d entries", len(entries))
+		}
+	})
+
+	t.Run("sources without make.bash are rejected", func(t *testing.T) {
+		installer := &Installer{Root: t.TempDir()}
+		_, err := installer.Build(BuildOptions{Name: "empty", Source: t.TempDir()})
+		if err == nil || !strings.Contains(err.Error(), "not a Go source tree") {
+			t.Errorf("Build() error = %v", err)
+		}
+	})
+}
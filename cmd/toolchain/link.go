@@ -0,0 +1,79 @@
+package toolchain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+)
+
+// CheckName reports why name cannot name a linked toolchain: it must be a
+// plain file name that is not a version, a version spec or a channel, so
+// that it never shadows a release
+func CheckName(name string) error {
+	switch {
+	case name == "" || name == "." || name == "..":
+		return fmt.Errorf("invalid name %q", name)
+	case strings.HasPrefix(name, "."):
+		return fmt.Errorf("invalid name %q: it must not start with a dot", name)
+	case strings.ContainsAny(name, `/\ `):
+		return fmt.Errorf("invalid name %q: it must not contain slashes or spaces", name)
+	}
+	if _, err := goversion.ParseConstraint(name); err == nil {
+		return fmt.Errorf("invalid name %q: it reads as a Go version; pick a name such as corp-%s", name, goversion.Normalize(name))
+	}
+	return nil
+}
+
+// Link registers the GOROOT at goroot under name, as a link in the
+// versions directory
+func (i *Installer) Link(name, goroot string) error {
+	if err := CheckName(name); err != nil {
+		return err
+	}
+	if _, err := fsys.Lstat(i.VersionDir(name)); err == nil {
+		return fmt.Errorf("%s is already installed or linked; unlink it first", name)
+	}
+	if err := fsys.MkdirAll(i.VersionsDir(), 0755); err != nil {
+		return fmt.Errorf("creating versions directory: %w", err)
+	}
+	if err := fsys.Symlink(goroot, i.VersionDir(name)); err != nil {
+		return fmt.Errorf("linking %s: %w", goroot, err)
+	}
+	return nil
+}
+
+// LinkTarget returns the GOROOT a linked version points at; ok is false for
+// versions gos installed itself
+func (i *Installer) LinkTarget(version string) (goroot string, ok bool) {
+	dir := i.VersionDir(version)
+	if !fsys.IsLink(dir) {
+		return "", false
+	}
+	target, err := fsys.Readlink(dir)
+	if err != nil {
+		return "", false
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(i.VersionsDir(), target)
+	}
+	return target, true
+}
+
+// Unlink removes the registration of a linked version, leaving its files
+// alone. The active version cannot be unlinked.
+func (i *Installer) Unlink(name string) error {
+	if _, err := fsys.Lstat(i.VersionDir(name)); err != nil {
+		return &errs.VersionNotFoundError{Spec: name, Installed: true}
+	}
+	if _, ok := i.LinkTarget(name); !ok {
+		return fmt.Errorf("Go %s was installed by gos, not linked; remove it with: gos remove %s", name, name)
+	}
+	if current, err := i.Current(); err == nil && current == NormalizeVersion(name) {
+		return fmt.Errorf("%s is the active version; switch to another version first", name)
+	}
+	return fsys.Remove(i.VersionDir(name))
+}
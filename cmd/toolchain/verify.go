@@ -0,0 +1,33 @@
+package toolchain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+)
+
+// ChecksumError reports a downloaded file whose SHA-256 does not match the release index
+type ChecksumError struct {
+	File     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected sha256 %s, got %s", e.File, e.Expected, e.Actual)
+}
+
+// ExitCode implements the exit code lookup of errs.Code
+func (e *ChecksumError) ExitCode() int { return errs.ExitChecksumMismatch }
+
+// verifySHA256 checks a digest computed while downloading against the expected one
+func verifySHA256(name, expected, actual string) error {
+	if expected == "" {
+		return fmt.Errorf("no sha256 checksum published for %s; refusing to install unverified archive", name)
+	}
+	if !strings.EqualFold(expected, actual) {
+		return &ChecksumError{File: name, Expected: strings.ToLower(expected), Actual: actual}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StripEmoji removes emoji and pictographic symbols from s, with the spaces
+// that separated them from the text, keeping each line's indentation
+func StripEmoji(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		lines[i] = stripLine(line)
+	}
+	return strings.Join(lines, "")
+}
+
+func stripLine(line string) string {
+	text, newline := strings.CutSuffix(line, "\n")
+
+	var out strings.Builder
+	removed, dropSpace, glued := false, false, false
+	var last rune
+	for _, r := range text {
+		switch {
+		case isEmoji(r):
+			// An icon glued to text, like a keycap digit, keeps one space after it
+			glued = glued || !dropSpace && last != 0 && !unicode.IsSpace(last)
+			removed, dropSpace = true, true
+			continue
+		case dropSpace && r == ' ':
+			continue
+		}
+		if glued {
+			out.WriteRune(' ')
+		}
+		dropSpace, glued = false, false
+		out.WriteRune(r)
+		last = r
+	}
+	if !removed {
+		return line
+	}
+
+	text = out.String()
+	if dropSpace {
+		// The line ended with an icon; drop the space that led up to it
+		text = strings.TrimRightFunc(text, unicode.IsSpace)
+	}
+	if newline {
+		text += "\n"
+	}
+	return text
+}
+
+// isEmoji reports whether r is an emoji, a pictographic symbol gos uses as an
+// icon, or a joiner or variation selector that belongs to one
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF, // emoji and pictographs
+		r >= 0x2600 && r <= 0x27BF, // symbols and dingbats (✅ ❌ ⚠ ⚡ ➖)
+		r >= 0x2B00 && r <= 0x2BFF, // stars and arrows (⭐)
+		r >= 0x2300 && r <= 0x23FF, // technical symbols (⏭ ⏳)
+		r >= 0x25A0 && r <= 0x25FF, // geometric shapes (▸)
+		r == 0x2139,                // ℹ
+		r == 0x200D,                // zero width joiner
+		r == 0x20E3,                // combining keycap (1️⃣)
+		r == 0xFE0E || r == 0xFE0F: // variation selectors
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+)
+
+// AssumeYesVar answers every prompt with yes, like --yes
+const AssumeYesVar = "GOS_ASSUME_YES"
+
+// reader is where answers are read from; tests replace it
+var reader = bufio.NewReader(os.Stdin)
+
+// interactive reports whether a prompt can wait for an answer; tests replace it
+var interactive = func() bool { return IsTerminal(os.Stdin) }
+
+// assumeYesEnv reports whether GOS_ASSUME_YES is set to a true value
+func assumeYesEnv() bool {
+	switch strings.ToLower(os.Getenv(AssumeYesVar)) {
+	case "", "0", "false", "no":
+		return false
+	}
+	return true
+}
+
+// Confirm asks a yes/no question; def is the answer an empty reply gives.
+// With --yes it answers yes and with --no-input it takes def without asking.
+// When stdin is not a terminal it fails with errs.InputRequiredError rather
+// than waiting for input that never comes.
+func Confirm(question string, def bool) (bool, error) {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+
+	switch {
+	case options.AssumeYes:
+		Prompt.Printf("%s (%s): yes (--yes)\n", question, choices)
+		return true, nil
+	case options.NoInput:
+		Prompt.Printf("%s (%s): %s (--no-input)\n", question, choices, yesNo(def))
+		return def, nil
+	}
+
+	answer, err := ask(question, fmt.Sprintf("%s (%s): ", question, choices))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// Choose shows a numbered menu and returns the index of the chosen option.
+// With --yes it takes the option at yes, with --no-input the one at def,
+// which is also what an answer outside the menu gives.
+func Choose(question string, choices []string, yes, def int) (int, error) {
+	Prompt.Println(question)
+	numbers := make([]string, len(choices))
+	for i, choice := range choices {
+		numbers[i] = fmt.Sprint(i + 1)
+		Prompt.Printf("  %s️⃣  %s\n", numbers[i], choice)
+	}
+	Prompt.Println()
+
+	switch {
+	case options.AssumeYes:
+		Prompt.Printf("Enter your choice: %d (--yes)\n", yes+1)
+		return yes, nil
+	case options.NoInput:
+		Prompt.Printf("Enter your choice: %d (--no-input)\n", def+1)
+		return def, nil
+	}
+
+	answer, err := ask(question, fmt.Sprintf("Enter your choice (%s): ", strings.Join(numbers, "/")))
+	if err != nil {
+		return 0, err
+	}
+	for i, number := range numbers {
+		if answer == number {
+			return i, nil
+		}
+	}
+	return def, nil
+}
+
+// ask prints prompt and reads one line of input
+func ask(question, prompt string) (string, error) {
+	if !interactive() {
+		return "", &errs.InputRequiredError{Prompt: strings.TrimSpace(question)}
+	}
+
+	Prompt.Print(prompt)
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		// stdin was closed before an answer came
+		Prompt.Println()
+		return "", &errs.InputRequiredError{Prompt: strings.TrimSpace(question)}
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func yesNo(answer bool) string {
+	if answer {
+		return "yes"
+	}
+	return "no"
+}
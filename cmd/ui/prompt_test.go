@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/errs"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		input string
+		def   bool
+		want  bool
+	}{
+		{name: "yes", input: "y\n", want: true},
+		{name: "full word", input: "YES\n", want: true},
+		{name: "no", input: "n\n", def: true, want: false},
+		{name: "empty reply takes the default", input: "\n", def: true, want: true},
+		{name: "anything else is no", input: "maybe\n", want: false},
+		{name: "--yes", opts: Options{AssumeYes: true}, want: true},
+		{name: "--no-input", opts: Options{NoInput: true}, def: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configure(t, tt.opts)
+			answer(t, true, tt.input)
+			capture(t)
+
+			got, err := Confirm("Continue?", tt.def)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("GOS_ASSUME_YES", func(t *testing.T) {
+		t.Setenv(AssumeYesVar, "1")
+		configure(t, Options{})
+		answer(t, false, "")
+		capture(t)
+
+		if got, err := Confirm("Continue?", false); err != nil || !got {
+			t.Errorf("Confirm() = %v, %v; want true", got, err)
+		}
+	})
+
+	t.Run("no terminal fails fast", func(t *testing.T) {
+		configure(t, Options{})
+		answer(t, false, "y\n")
+		capture(t)
+
+		_, err := Confirm("Continue?", false)
+		var required *errs.InputRequiredError
+		if !errors.As(err, &required) || required.Prompt != "Continue?" {
+			t.Errorf("Confirm() error = %v, want InputRequiredError", err)
+		}
+	})
+
+	t.Run("closed stdin", func(t *testing.T) {
+		configure(t, Options{})
+		answer(t, true, "")
+		capture(t)
+
+		if _, err := Confirm("Continue?", false); errs.Code(err) != errs.ExitUsage {
+			t.Errorf("Confirm() error = %v, want an input required error", err)
+		}
+	})
+}
+
+func TestChoose(t *testing.T) {
+	choices := []string{"Fix it", "Show instructions", "Cancel"}
+	tests := []struct {
+		name  string
+		opts  Options
+		input string
+		want  int
+	}{
+		{name: "listed number", input: "3\n", want: 2},
+		{name: "unknown answer takes the default", input: "9\n", want: 1},
+		{name: "--yes", opts: Options{AssumeYes: true}, want: 0},
+		{name: "--no-input", opts: Options{NoInput: true}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configure(t, tt.opts)
+			answer(t, true, tt.input)
+			_, stderr := capture(t)
+
+			got, err := Choose("Choose an option:", choices, 0, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Choose() = %d, want %d", got, tt.want)
+			}
+			if !strings.Contains(stderr(), "2️⃣  Show instructions") {
+				t.Errorf("menu not shown on stderr:\n%s", stderr())
+			}
+		})
+	}
+}
+
+// answer makes prompts read input, as if stdin were a terminal when interactive is set
+func answer(t *testing.T, isTerminal bool, input string) {
+	t.Helper()
+	savedReader, savedInteractive := reader, interactive
+	reader = bufio.NewReader(strings.NewReader(input))
+	interactive = func() bool { return isTerminal }
+	t.Cleanup(func() { reader, interactive = savedReader, savedInteractive })
+}
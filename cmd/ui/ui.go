@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
+)
+
+// Options are the display and prompt settings from the root flags and environment
+type Options struct {
+	NoColor bool // --no-color, NO_COLOR or TERM=dumb
+	Plain   bool // --plain: no emoji
+	Quiet   bool // --quiet: only results, warnings and errors
+	Verbose bool // --verbose: also debug details
+
+	AssumeYes bool // --yes or GOS_ASSUME_YES: answer prompts with yes
+	NoInput   bool // --no-input: answer prompts with their default
+}
+
+// options is the active configuration; colors honour NO_COLOR from the start
+var options = Options{NoColor: noColorEnv()}
+
+// Configure applies the display settings. NO_COLOR and TERM=dumb disable
+// colors and GOS_ASSUME_YES answers prompts regardless of opts.
+func Configure(opts Options) {
+	opts.NoColor = opts.NoColor || noColorEnv()
+	opts.AssumeYes = (opts.AssumeYes || assumeYesEnv()) && !opts.NoInput
+	options = opts
+
+	// Keep code that still formats with color directly (and cobra's output) in line
+	color.NoColor = color.NoColor || opts.NoColor
+}
+
+// Current returns the active display settings
+func Current() Options { return options }
+
+// noColorEnv reports whether the environment asks for no colors (https://no-color.org)
+func noColorEnv() bool {
+	return os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+}
+
+// level decides when a printer's output is shown
+type level int
+
+const (
+	levelMessage level = iota // hidden by --quiet
+	levelResult               // always shown
+	levelDebug                // shown with --verbose
+)
+
+// Printer writes one kind of output with its color, stream and level. Its
+// methods mirror fmt and are safe to use when the output is redirected.
+type Printer struct {
+	color  color.Attribute // 0 for none
+	stderr bool
+	level  level
+}
+
+// Printers for each kind of output. Messages describe what gos is doing;
+// results, warnings and errors are what the user asked for or must see.
+var (
+	// Info is a heading or progress step
+	Info = Printer{color: color.FgBlue}
+	// Note is a plain detail of a step
+	Note = Printer{}
+	// Hint is a suggestion of what to run next
+	Hint = Printer{color: color.FgYellow}
+
+	// Title is a heading of a report
+	Title = Printer{color: color.FgBlue, level: levelResult}
+	// Out is a plain result line
+	Out = Printer{level: levelResult}
+	// Success is a result that went well, or a report line that is fine
+	Success = Printer{color: color.FgGreen, level: levelResult}
+	// Caution is a report line that needs attention, e.g. a mismatching setting
+	Caution = Printer{color: color.FgYellow, level: levelResult}
+	// Problem is a report line for something broken, e.g. a missing directory
+	Problem = Printer{color: color.FgRed, level: levelResult}
+
+	// Warn is a diagnostic the command continues after; it goes to stderr
+	Warn = Printer{color: color.FgYellow, stderr: true, level: levelResult}
+	// Error is a failure; it goes to stderr
+	Error = Printer{color: color.FgRed, stderr: true, level: levelResult}
+	// Prompt is a question or menu waiting for input; it goes to stderr
+	Prompt = Printer{stderr: true, level: levelResult}
+	// Debug is a detail shown with --verbose; it goes to stderr
+	Debug = Printer{stderr: true, level: levelDebug}
+)
+
+// Printf formats like fmt.Printf
+func (p Printer) Printf(format string, a ...any) {
+	p.write(fmt.Sprintf(format, a...))
+}
+
+// Println formats like fmt.Println
+func (p Printer) Println(a ...any) {
+	p.write(fmt.Sprintln(a...))
+}
+
+// Print formats like fmt.Print
+func (p Printer) Print(a ...any) {
+	p.write(fmt.Sprint(a...))
+}
+
+// Enabled reports whether the printer's output is shown with the current settings
+func (p Printer) Enabled() bool {
+	switch p.level {
+	case levelMessage:
+		return !options.Quiet
+	case levelDebug:
+		return options.Verbose
+	}
+	return true
+}
+
+// file returns the stream the printer writes to, looked up on each call so
+// redirections of os.Stdout (e.g. for --output json) are followed
+func (p Printer) file() *os.File {
+	if p.stderr {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func (p Printer) write(s string) {
+	if !p.Enabled() {
+		return
+	}
+	if options.Plain {
+		s = StripEmoji(s)
+	}
+
+	f := p.file()
+	if p.color == 0 || options.NoColor || !IsTerminal(f) {
+		io.WriteString(f, s)
+		return
+	}
+	// Windows consoles need the escape codes translated
+	io.WriteString(colorable.NewColorable(f), colorize(p.color, s))
+}
+
+// colorize wraps each line of s in the color, leaving line breaks outside
+// the escape codes
+func colorize(attr color.Attribute, s string) string {
+	c := color.New(attr)
+	c.EnableColor()
+
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		if text != "" {
+			lines[i] = c.Sprint(text) + line[len(text):]
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// IsTerminal reports whether f is an interactive terminal rather than a pipe,
+// file or device such as /dev/null
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Progress returns where progress bars are drawn: stderr, or nowhere with --quiet
+func Progress() io.Writer {
+	if options.Quiet {
+		return io.Discard
+	}
+	return os.Stderr
+}
+
+// Diff prints a unified diff with headers, hunks and changed lines colored
+func Diff(diff string) {
+	header := Printer{color: color.Bold, level: levelResult}
+	hunk := Printer{color: color.FgCyan, level: levelResult}
+
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			header.Println(line)
+		case strings.HasPrefix(line, "@@"):
+			hunk.Println(line)
+		case strings.HasPrefix(line, "+"):
+			Success.Println(line)
+		case strings.HasPrefix(line, "-"):
+			Problem.Println(line)
+		default:
+			Out.Println(line)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStripEmoji(t *testing.T) {
+	tests := map[string]string{
+		"✅ Go 1.22.1 installed\n":             "Go 1.22.1 installed\n",
+		"  ⚠️  GOPATH: /tmp (expected: ~/go)": "  GOPATH: /tmp (expected: ~/go)",
+		"    ℹ️  /usr/local/go/bin\n":         "    /usr/local/go/bin\n",
+		"  1️⃣  Clean shell files":            "  1 Clean shell files",
+		"📂 GOROOT: ":                          "GOROOT: ",
+		"go version go1.22.1 linux/amd64\n":   "go version go1.22.1 linux/amd64\n",
+		"done ✅\n":                            "done\n",
+		"🔧 first\n  💡 second\n":               "first\n  second\n",
+	}
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got := StripEmoji(in); got != want {
+				t.Errorf("StripEmoji(%q) = %q, want %q", in, got, want)
+			}
+		})
+	}
+}
+
+func TestPrinterLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		printer Printer
+		want    bool
+	}{
+		{"message by default", Options{}, Info, true},
+		{"message with quiet", Options{Quiet: true}, Note, false},
+		{"result with quiet", Options{Quiet: true}, Success, true},
+		{"error with quiet", Options{Quiet: true}, Error, true},
+		{"debug by default", Options{}, Debug, false},
+		{"debug with verbose", Options{Verbose: true}, Debug, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configure(t, tt.opts)
+			if got := tt.printer.Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinterWrite(t *testing.T) {
+	t.Run("stdout and stderr", func(t *testing.T) {
+		configure(t, Options{})
+		stdout, stderr := capture(t)
+
+		Success.Printf("✅ %s\n", "done")
+		Warn.Println("⚠️  careful")
+
+		if got := stdout(); got != "✅ done\n" {
+			t.Errorf("stdout = %q", got)
+		}
+		if got := stderr(); got != "⚠️  careful\n" {
+			t.Errorf("stderr = %q", got)
+		}
+	})
+
+	t.Run("no escape codes when not a terminal", func(t *testing.T) {
+		configure(t, Options{})
+		stdout, _ := capture(t)
+
+		Problem.Println("broken")
+
+		if got := stdout(); got != "broken\n" {
+			t.Errorf("stdout = %q", got)
+		}
+	})
+
+	t.Run("plain and quiet", func(t *testing.T) {
+		configure(t, Options{Plain: true, Quiet: true})
+		stdout, _ := capture(t)
+
+		Info.Println("📦 Installing Go 1.22.1...")
+		Out.Println("🎯 Go 1.22.1")
+
+		if got := stdout(); got != "Go 1.22.1\n" {
+			t.Errorf("stdout = %q", got)
+		}
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	t.Run("NO_COLOR wins over the flags", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		configure(t, Options{})
+		if !Current().NoColor {
+			t.Error("expected colors to be disabled")
+		}
+	})
+
+	t.Run("progress is discarded with quiet", func(t *testing.T) {
+		configure(t, Options{Quiet: true})
+		if Progress() != io.Discard {
+			t.Error("expected progress to be discarded")
+		}
+	})
+}
+
+// configure applies opts for the duration of the test
+func configure(t *testing.T, opts Options) {
+	t.Helper()
+	previous := options
+	Configure(opts)
+	t.Cleanup(func() { options = previous })
+}
+
+// capture redirects os.Stdout and os.Stderr to files and returns readers for
+// what was written
+func capture(t *testing.T) (stdout, stderr func() string) {
+	t.Helper()
+	dir := t.TempDir()
+	outFile, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errFile, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedOut, savedErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outFile, errFile
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = savedOut, savedErr
+		outFile.Close()
+		errFile.Close()
+	})
+
+	read := func(f *os.File) func() string {
+		return func() string {
+			data, err := os.ReadFile(f.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(data)
+		}
+	}
+	return read(outFile), read(errFile)
+}
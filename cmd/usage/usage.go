@@ -0,0 +1,112 @@
+package usage
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+)
+
+// resolution is how often the last use of a version is written down; shims
+// record every go invocation, so most of them only read the record
+const resolution = time.Hour
+
+// Dir returns the directory holding one last-used record per version
+func Dir() string {
+	return filepath.Join(common.GetGosHome(), "usage")
+}
+
+// ProjectsFile returns the file listing the projects registered with gos project
+func ProjectsFile() string {
+	return filepath.Join(common.GetGosHome(), "projects")
+}
+
+// Touch records that a version was used now. Failures are ignored: usage
+// tracking must never get in the way of running Go.
+func Touch(version string) {
+	touch(version, time.Now())
+}
+
+// touch records that a version was used at when
+func touch(version string, when time.Time) {
+	if version == "" || strings.ContainsAny(version, `/\`) {
+		return
+	}
+	if last, ok := LastUsed(version); ok && when.Sub(last) < resolution {
+		return
+	}
+	if err := fsys.MkdirAll(Dir(), 0755); err != nil {
+		return
+	}
+	fsys.WriteFile(filepath.Join(Dir(), version), []byte(when.UTC().Format(time.RFC3339)+"\n"), 0644)
+}
+
+// LastUsed returns when a version was last used; ok is false when gos has
+// no record of it
+func LastUsed(version string) (time.Time, bool) {
+	data, err := fsys.ReadFile(filepath.Join(Dir(), version))
+	if err != nil {
+		return time.Time{}, false
+	}
+	when, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	return when, err == nil
+}
+
+// Forget drops the record of a removed version
+func Forget(version string) {
+	fsys.Remove(filepath.Join(Dir(), version))
+}
+
+// Projects lists the registered project directories
+func Projects() []string {
+	data, err := fsys.ReadFile(ProjectsFile())
+	if err != nil {
+		return nil
+	}
+	var projects []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			projects = append(projects, line)
+		}
+	}
+	return projects
+}
+
+// RegisterProject remembers dir as a project whose .go-version pins a
+// version, so gos prune keeps that version
+func RegisterProject(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	projects := Projects()
+	if slices.Contains(projects, dir) {
+		return nil
+	}
+	return writeProjects(append(projects, dir))
+}
+
+// UnregisterProject forgets a project directory
+func UnregisterProject(dir string) error {
+	projects := Projects()
+	i := slices.Index(projects, dir)
+	if i < 0 {
+		return nil
+	}
+	return writeProjects(slices.Delete(projects, i, i+1))
+}
+
+// writeProjects replaces the projects file
+func writeProjects(projects []string) error {
+	if err := fsys.MkdirAll(filepath.Dir(ProjectsFile()), 0755); err != nil {
+		return err
+	}
+	content := strings.Join(projects, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return fsys.WriteFile(ProjectsFile(), []byte(content), 0644)
+}
@@ -0,0 +1,54 @@
+package usage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+)
+
+func TestTouch(t *testing.T) {
+	testenv.Sandbox(t)
+	if _, ok := LastUsed("1.22.1"); ok {
+		t.Fatal("LastUsed() found a version never used")
+	}
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	touch("1.22.1", start)
+	touch("1.22.1", start.Add(10*time.Minute))
+	if last, _ := LastUsed("1.22.1"); !last.Equal(start) {
+		t.Errorf("LastUsed() = %v, want %v: uses within the hour are not rewritten", last, start)
+	}
+	touch("1.22.1", start.Add(2*time.Hour))
+	if last, _ := LastUsed("1.22.1"); !last.Equal(start.Add(2 * time.Hour)) {
+		t.Errorf("LastUsed() = %v", last)
+	}
+
+	Forget("1.22.1")
+	if _, ok := LastUsed("1.22.1"); ok {
+		t.Error("LastUsed() still finds a forgotten version")
+	}
+}
+
+func TestProjects(t *testing.T) {
+	testenv.Sandbox(t)
+	app := filepath.Join(common.GetHomeDir(), "src", "app")
+	api := filepath.Join(common.GetHomeDir(), "src", "api")
+	for _, dir := range []string{app, api, app} {
+		if err := RegisterProject(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := Projects(); len(got) != 2 || got[0] != app || got[1] != api {
+		t.Errorf("Projects() = %v", got)
+	}
+
+	if err := UnregisterProject(app); err != nil {
+		t.Fatal(err)
+	}
+	if got := Projects(); len(got) != 1 || got[0] != api {
+		t.Errorf("Projects() = %v after unregistering %s", got, app)
+	}
+}
@@ -0,0 +1,73 @@
+package use
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/session"
+	"github.com/cristobalcontreras/gos/cmd/shell"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/cristobalcontreras/gos/cmd/usage"
+)
+
+// useInShell runs `gos use --shell`: shell code goes to stdout for the gos
+// shell function to eval, every message to stderr
+func useInShell(shellName string, args []string) error {
+	code, restore := session.DivertOutput()
+	defer restore()
+
+	sh, _, err := session.EvalShell(shellName, os.Getenv)
+	if err != nil {
+		return &errs.UsageError{Err: err}
+	}
+	b, err := backend.Active()
+	if err != nil {
+		return err
+	}
+	version, err := requestedVersion(args)
+	if err != nil {
+		return err
+	}
+	return UseVersionInShell(code, b, version, sh)
+}
+
+// UseVersionInShell writes the code that switches only the calling shell
+// session to a version; the global version and other terminals are untouched
+func UseVersionInShell(code *os.File, b backend.Backend, version string, sh shell.Shell) error {
+	resolved, err := backend.ResolveInstalled(b, version)
+	if err != nil {
+		ui.Hint.Printf("💡 See installed versions with: gos list, or install one with: gos install %s\n", version)
+		return err
+	}
+	if !goversion.Equal(resolved, version) {
+		ui.Info.Printf("🔎 %s resolved to Go %s\n", version, resolved)
+	}
+
+	usage.Touch(resolved)
+	fmt.Fprint(code, session.Use(sh, os.Getenv, resolved, filepath.Join(b.GOROOT(resolved), "bin")))
+
+	if ui.IsTerminal(code) {
+		ui.Hint.Println("💡 The code above only takes effect when evaluated. Load it with:")
+		ui.Hint.Printf("   %s\n", evalLine(sh, resolved))
+		ui.Hint.Println("   or add the gos shell function (see 'gos init --help') so 'gos use --shell' does it for you.")
+		return nil
+	}
+	ui.Success.Printf("✅ Go %s is active in this shell session only\n", resolved)
+	ui.Hint.Println("💡 Run 'gos reload' to return to the global version.")
+	return nil
+}
+
+// evalLine returns the command that evaluates `gos use <version> --shell` in a shell
+func evalLine(sh shell.Shell, version string) string {
+	switch sh {
+	case shell.Fish:
+		return fmt.Sprintf("gos use %s --shell=fish | source", version)
+	case shell.Pwsh:
+		return fmt.Sprintf("(& gos use %s --shell=pwsh) -join \"`n\" | Invoke-Expression", version)
+	}
+	return fmt.Sprintf(`eval "$(gos use %s --shell=%s)"`, version, sh)
+}
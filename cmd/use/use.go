@@ -1,38 +1,77 @@
 package use
 
 import (
-	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"errors"
+	"os"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/errs"
+	"github.com/cristobalcontreras/gos/cmd/resolver"
+	"github.com/cristobalcontreras/gos/cmd/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewUseCmd creates the use command
 func NewUseCmd() *cobra.Command {
+	var shellName string
+
 	cmd := &cobra.Command{
 		Use:   "use [version]",
 		Short: "Switch to a specific Go version",
-		Long:  `Switch to a specific Go version that has been previously installed using gobrew.`,
+		Long: `Switch to a specific Go version that has been previously installed with gos install.
+The version may be a partial version or range (1.21, ~1.22, '>=1.21 <1.23'),
+which picks the newest matching installed version.
+
+Without a version, the one configured for the current directory is used
+(see 'gos current --explain').
+
+With --shell the switch applies to the calling shell session only: gos prints
+the code that puts the version first in PATH and sets GOS_VERSION, and the gos
+shell function from 'gos init' evaluates it. The global version and other
+terminals stay untouched; 'gos reload' returns the session to the global one.`,
 		Example: `  gos use 1.21.5        # Switch to Go 1.21.5
-  gos use latest         # Switch to latest installed version`,
-		Args: cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			if !checkVersionManagerAvailable() {
-				return
+  gos use ~1.22         # Switch to the newest installed 1.22.x
+  gos use latest         # Switch to latest installed version
+  gos use                # Switch to the version configured for this directory
+  gos use 1.22 --shell   # Use Go 1.22 in this terminal only`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("shell") {
+				return useInShell(shellName, args)
 			}
-			UseVersion(args[0])
+
+			b, err := backend.Active()
+			if err != nil {
+				return err
+			}
+			version, err := requestedVersion(args)
+			if err != nil {
+				return err
+			}
+			return UseVersionWith(b, version)
 		},
 	}
 
+	cmd.Flags().StringVar(&shellName, "shell", "", "Switch only this shell session by printing code to eval (bash, zsh, fish, pwsh)")
+	cmd.Flags().Lookup("shell").NoOptDefVal = "auto"
+
 	return cmd
 }
 
-// checkVersionManagerAvailable verifies if gobrew is available
-func checkVersionManagerAvailable() bool {
-	if common.IsCommandAvailable("gobrew") {
-		return true
+// requestedVersion returns the version argument, or the version configured
+// for the current directory when none was given
+func requestedVersion(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
 	}
 
-	color.Red("❌ Error: gobrew is not installed.")
-	color.Yellow("💡 Run first: gos setup")
-	return false
+	dir, _ := os.Getwd()
+	result, err := resolver.Resolve(dir)
+	if err != nil || !result.Found() {
+		ui.Hint.Printf("💡 Pass a version (gos use 1.21.5) or pin one with: gos project <version>\n")
+		return "", &errs.UsageError{Err: errors.New("no Go version configured for this directory")}
+	}
+	ui.Debug.Printf("resolved Go %s in %s (%s %s)\n", result.Version, dir, result.Source, result.Path)
+	ui.Info.Printf("📄 Using Go %s from %s\n", result.Version, result.Source)
+	return result.Version, nil
 }
@@ -2,172 +2,174 @@ package use
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 
+	"github.com/cristobalcontreras/gos/cmd/backend"
 	"github.com/cristobalcontreras/gos/cmd/common"
-	"github.com/fatih/color"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/cristobalcontreras/gos/cmd/usage"
 )
 
-// UseVersion switches to a specific Go version
-func UseVersion(version string) {
-	blue := color.New(color.FgBlue)
-	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
-	yellow := color.New(color.FgYellow)
-
-	blue.Printf("🔄 Switching to Go %s...\n", version)
-
-	cmd := createVersionSwitchCommand(version, blue, red)
-	if cmd == nil {
-		return
+// UseVersion switches to a specific Go version with the active backend
+func UseVersion(version string) error {
+	b, err := backend.Active()
+	if err != nil {
+		return err
 	}
+	return UseVersionWith(b, version)
+}
 
-	if !executeVersionSwitch(cmd, version, blue, red, yellow) {
-		return
+// UseVersionWith switches to a specific Go version with the given backend
+func UseVersionWith(b backend.Backend, version string) error {
+	resolved, err := backend.ResolveInstalled(b, version)
+	if err != nil {
+		ui.Hint.Printf("💡 See installed versions with: gos list, or install one with: gos install %s\n", version)
+		return err
 	}
+	if !goversion.Equal(resolved, version) {
+		ui.Info.Printf("🔎 %s resolved to Go %s\n", version, resolved)
+	}
+	version = resolved
 
-	green.Printf("✅ Version switch command completed\n")
+	ui.Info.Printf("🔄 Switching to Go %s...\n", version)
 
-	// Update PATH and verify installation
-	performPostSwitchVerification(version, blue, green, yellow)
-}
+	if err := switchVersion(b, version); err != nil {
+		return err
+	}
 
-// createVersionSwitchCommand creates the command for version switching using gobrew
-func createVersionSwitchCommand(version string, blue, red *color.Color) *exec.Cmd {
-	blue.Println("  Using gobrew...")
-	cmd := exec.Command("gobrew", "use", version)
+	ui.Success.Printf("✅ Version switch command completed\n")
 
-	// Set output to see what's happening
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd
+	// Verify which go the calling shell resolves
+	return performPostSwitchVerification(b, version)
 }
 
-// executeVersionSwitch executes the version switch command
-func executeVersionSwitch(cmd *exec.Cmd, version string, blue, red, yellow *color.Color) bool {
-	blue.Println("  Executing version switch...")
-	if err := cmd.Run(); err != nil {
-		red.Printf("❌ Error switching to Go %s: %v\n", version, err)
-		yellow.Printf("💡 Is this version installed? Use: gos list\n")
-		return false
+// switchVersion activates the version through the backend
+func switchVersion(b backend.Backend, version string) error {
+	ui.Info.Printf("  Using %s...\n", b.Name())
+	if err := b.Use(version); err != nil {
+		ui.Hint.Printf("💡 Is this version installed? Use: gos list\n")
+		return fmt.Errorf("switching to Go %s: %w", version, err)
 	}
-	return true
+	usage.Touch(version)
+	shim.Refresh(b)
+	return nil
 }
 
-// performPostSwitchVerification handles PATH update and verification
-func performPostSwitchVerification(version string, blue, green, yellow *color.Color) {
-	// Clean all Go paths and update PATH for version manager
-	blue.Println("  Cleaning Go paths and updating PATH...")
-	common.UpdatePathForVersionManagerClean()
-
-	// Show current version and PATH update instructions
-	blue.Println("\n📋 Verifying installation...")
+// performPostSwitchVerification checks which go the calling shell's PATH
+// resolves to; gos cannot change the PATH of the shell that started it
+func performPostSwitchVerification(b backend.Backend, version string) error {
+	ui.Info.Println("\n📋 Verifying installation...")
 
 	// Use the version manager's Go binary directly for verification
-	goPath := getVersionManagerGoPath()
+	goPath := getVersionManagerGoPath(b)
 
 	if goPath != "" {
-		verifyWithDirectPath(goPath, green, yellow)
-	} else {
-		verifyWithPathResolution(version, green, yellow)
+		return verifyWithDirectPath(b, goPath)
 	}
+	verifyWithPathResolution(b, version)
+	return nil
 }
 
-// getVersionManagerGoPath returns the path to gobrew's Go binary
-func getVersionManagerGoPath() string {
-	homeDir := common.GetHomeDir()
-	return filepath.Join(homeDir, ".gobrew", "current", "bin", "go")
+// getVersionManagerGoPath returns the path to the version manager's Go binary
+func getVersionManagerGoPath(b backend.Backend) string {
+	return filepath.Join(b.GOROOT(""), "bin", "go")
 }
 
-// verifyWithDirectPath verifies Go version using direct path
-func verifyWithDirectPath(goPath string, green, yellow *color.Color) {
+// verifyWithDirectPath verifies Go version using direct path; it fails only
+// when the PATH cleanup prompt cannot be answered
+func verifyWithDirectPath(b backend.Backend, goPath string) error {
 	// Check version manager's Go version
 	var vmVersion string
-	if output, err := exec.Command(goPath, "version").Output(); err == nil {
-		vmVersion = strings.TrimSpace(string(output))
-		green.Printf("✅ Version manager: %s\n", vmVersion)
+	if output, err := runner.Output(goPath, "version"); err == nil {
+		vmVersion = strings.TrimSpace(output)
+		ui.Success.Printf("✅ Version manager: %s\n", vmVersion)
 	} else {
-		yellow.Printf("⚠️  Error checking version manager: %v\n", err)
-		showPathUpdateInstructions(yellow)
-		return
+		ui.Warn.Printf("⚠️  Error checking version manager: %v\n", err)
+		showPathUpdateInstructions()
+		return nil
 	}
 
 	// Check system's Go version (from PATH)
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		systemVersion := strings.TrimSpace(string(output))
+	if output, err := runner.Output("go", "version"); err == nil {
+		systemVersion := strings.TrimSpace(output)
 		if vmVersion == systemVersion {
-			green.Printf("✅ System version: %s\n", systemVersion)
+			ui.Success.Printf("✅ System version: %s\n", systemVersion)
 		} else {
-			yellow.Printf("⚠️  System version: %s\n", systemVersion)
-			yellow.Println("⚠️  Version mismatch! Multiple Go installations detected in PATH.")
-			showPathCleanupInstructions(yellow)
+			ui.Warn.Printf("⚠️  System version: %s\n", systemVersion)
+			ui.Warn.Println("⚠️  Version mismatch! Multiple Go installations detected in PATH.")
+			ui.Hint.Println("💡 Run 'gos which go' to see each one, where it comes from and which one wins")
+			return showPathCleanupInstructions(b)
 		}
 	} else {
-		yellow.Println("⚠️  Go binary not found in system PATH")
-		showPathUpdateInstructions(yellow)
+		ui.Warn.Println("⚠️  Go binary not found in system PATH")
+		showPathUpdateInstructions()
 	}
+	return nil
 }
 
 // verifyWithPathResolution verifies Go version using PATH resolution
-func verifyWithPathResolution(version string, green, yellow *color.Color) {
-	if output, err := exec.Command("go", "version").Output(); err == nil {
-		currentVersion := strings.TrimSpace(string(output))
-		if strings.Contains(currentVersion, version) {
-			green.Printf("✅ Current version: %s\n", currentVersion)
+func verifyWithPathResolution(b backend.Backend, version string) {
+	if output, err := runner.Output("go", "version"); err == nil {
+		currentVersion := strings.TrimSpace(output)
+		if goversion.Equal(goversion.FromGoVersionOutput(currentVersion), version) {
+			ui.Success.Printf("✅ Current version: %s\n", currentVersion)
 		} else {
-			yellow.Printf("⚠️  Version mismatch - found: %s\n", currentVersion)
-			yellow.Printf("   Expected: %s\n", version)
-			showPathUpdateInstructions(yellow)
+			ui.Warn.Printf("⚠️  Version mismatch - found: %s\n", currentVersion)
+			ui.Hint.Printf("   Expected: %s\n", version)
+			showPathUpdateInstructions()
 		}
 	} else {
-		yellow.Println("⚠️  Go binary not found in PATH")
-		showPathUpdateInstructions(yellow)
+		ui.Warn.Println("⚠️  Go binary not found in PATH")
+		showPathUpdateInstructions()
 	}
 }
 
-// showPathUpdateInstructions displays instructions for updating PATH
-func showPathUpdateInstructions(yellow *color.Color) {
-	yellow.Println("⚠️  PATH needs to be updated for this terminal session.")
-	yellow.Println("💡 To use the new Go version immediately, run:")
+// showPathUpdateInstructions explains how to load the switch into the current shell
+func showPathUpdateInstructions() {
+	ui.Warn.Println("⚠️  This shell's PATH does not point at the active version yet.")
+	ui.Hint.Println("💡 With the gos shell function from 'gos init', run: gos reload")
+	ui.Hint.Println("   Without it, load the configuration yourself:")
 	if runtime.GOOS == "windows" {
-		yellow.Println("   $env:PATH = \"$env:USERPROFILE\\.gobrew\\current\\bin;$env:USERPROFILE\\.gobrew\\bin;$env:PATH\"")
+		ui.Hint.Println("   (& gos reload --shell=pwsh) -join \"`n\" | Invoke-Expression")
 	} else {
-		yellow.Println("   export PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$PATH\"")
+		ui.Hint.Println(`   eval "$(gos reload --shell)"`)
 	}
-	fmt.Println()
-	color.New(color.FgBlue).Println("🔄 Or simply open a new terminal window.")
+	ui.Note.Println()
 }
 
 // showPathCleanupInstructions displays instructions for cleaning Go paths from PATH
-func showPathCleanupInstructions(yellow *color.Color) {
-	yellow.Println("💡 To fix PATH conflicts, you have several options:")
-	fmt.Println()
+func showPathCleanupInstructions(b backend.Backend) error {
+	ui.Hint.Println("💡 To fix PATH conflicts, you have several options:")
+	ui.Note.Println()
 
 	// Offer interactive cleanup
-	fmt.Println("Would you like automated help with PATH cleanup? (y/n)")
-	var response string
-	fmt.Scanln(&response)
+	help, err := ui.Confirm("Would you like automated help with PATH cleanup?", false)
+	if err != nil {
+		return err
+	}
 
-	if strings.ToLower(response) == "y" || strings.ToLower(response) == "yes" {
-		common.PromptUserForPathCleanup()
-	} else {
-		// Show manual instructions
-		yellow.Println("📋 Manual cleanup instructions:")
-		yellow.Println()
-		yellow.Println("   🧹 Clean your shell configuration files (~/.zshrc, ~/.bashrc, etc.):")
-		yellow.Println("   Remove or comment out lines that add Go paths like:")
-		yellow.Println("     - export PATH=/usr/local/go/bin:$PATH")
-		yellow.Println("     - export PATH=$HOME/sdk/go*/bin:$PATH")
-		yellow.Println("     - export PATH=$HOME/go/bin:$PATH (keep this one)")
-		yellow.Println()
-		yellow.Println("   ✅ Keep only the gobrew paths:")
-		yellow.Println("     export PATH=\"$HOME/.gobrew/current/bin:$HOME/.gobrew/bin:$HOME/go/bin:$PATH\"")
-		yellow.Println()
-		yellow.Println("   🔄 After editing, restart your terminal or run: source ~/.zshrc")
-		fmt.Println()
+	if help {
+		return common.PromptUserForPathCleanup(backend.PathLine(b))
 	}
+
+	// Show manual instructions
+	ui.Hint.Println("📋 Manual cleanup instructions:")
+	ui.Hint.Println()
+	ui.Hint.Println("   🧹 Clean your shell configuration files (~/.zshrc, ~/.bashrc, etc.):")
+	ui.Hint.Println("   Remove or comment out lines that add Go paths like:")
+	ui.Hint.Println("     - export PATH=/usr/local/go/bin:$PATH")
+	ui.Hint.Println("     - export PATH=$HOME/sdk/go*/bin:$PATH")
+	ui.Hint.Println("     - export PATH=$HOME/go/bin:$PATH (keep this one)")
+	ui.Hint.Println()
+	ui.Hint.Println("   ✅ Keep only the version manager paths:")
+	ui.Hint.Println("     " + backend.PathLine(b))
+	ui.Hint.Println()
+	ui.Hint.Println("   🔄 After editing, restart your terminal or run: source ~/.zshrc")
+	ui.Note.Println()
+	return nil
 }
@@ -0,0 +1,90 @@
+package use
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+)
+
+func TestVerifyWithDirectPath(t *testing.T) {
+	b := backend.NewGobrew()
+	goPath := getVersionManagerGoPath(b)
+	vmVersion := runner.Response{Stdout: "go version go1.22.1 linux/amd64\n"}
+
+	t.Run("versions match", func(t *testing.T) {
+		fake := runner.NewFake().
+			On(goPath+" version", vmVersion).
+			On("go version", vmVersion)
+		defer runner.Replace(fake)()
+		stderr := captureStderr(t)
+
+		if err := verifyWithDirectPath(b, goPath); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{goPath + " version", "go version"}
+		if got := fake.Lines(); !reflect.DeepEqual(got, want) {
+			t.Errorf("commands = %v, want %v", got, want)
+		}
+		if strings.Contains(stderr(), "mismatch") {
+			t.Errorf("unexpected mismatch warning:\n%s", stderr())
+		}
+	})
+
+	t.Run("another go comes first in PATH", func(t *testing.T) {
+		fake := runner.NewFake().
+			On(goPath+" version", vmVersion).
+			On("go version", runner.Response{Stdout: "go version go1.20.14 linux/amd64\n"})
+		defer runner.Replace(fake)()
+		ui.Configure(ui.Options{NoInput: true})
+		defer ui.Configure(ui.Options{})
+		stderr := captureStderr(t)
+
+		if err := verifyWithDirectPath(b, goPath); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(stderr(), "Version mismatch") || !strings.Contains(stderr(), "go1.20.14") {
+			t.Errorf("expected a mismatch warning, got:\n%s", stderr())
+		}
+	})
+
+	t.Run("version manager go is broken", func(t *testing.T) {
+		fake := runner.NewFake().On(goPath+" version", runner.Response{ExitCode: 1})
+		defer runner.Replace(fake)()
+		stderr := captureStderr(t)
+
+		if err := verifyWithDirectPath(b, goPath); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.Calls()) != 1 || !strings.Contains(stderr(), "Error checking version manager") {
+			t.Errorf("commands %v, stderr:\n%s", fake.Lines(), stderr())
+		}
+	})
+}
+
+// captureStderr redirects os.Stderr to a file and returns a reader for it
+func captureStderr(t *testing.T) func() string {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() {
+		os.Stderr = saved
+		f.Close()
+	})
+	return func() string {
+		data, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
@@ -2,17 +2,25 @@ package version
 
 import (
 	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/output"
 	"github.com/spf13/cobra"
 )
 
 // NewVersionCmd creates the version command to show Go version info
 func NewVersionCmd() *cobra.Command {
-	return &cobra.Command{
+	return output.Enable(&cobra.Command{
 		Use:   "version",
 		Short: "Show current Go version information",
 		Long:  `Display detailed information about the currently active Go version, including GOROOT and GOPATH.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			common.DisplayCurrentGoVersion()
+		Example: `  gos version            # Show the Go version in PATH
+  gos version -o json    # The same as JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := common.CurrentGo()
+			if output.Structured() {
+				return output.Print(info)
+			}
+			common.DisplayCurrentGoVersion(info)
+			return nil
 		},
-	}
+	})
 }
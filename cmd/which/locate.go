@@ -0,0 +1,231 @@
+package which
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/backend"
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/goversion"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+)
+
+// Install sources a binary can come from
+const (
+	SourceGos      = "gos"
+	SourceHomebrew = "Homebrew"
+	SourceApt      = "apt"
+	SourceSnap     = "snap"
+	SourceUsrLocal = "/usr/local/go"
+	SourceSDK      = "~/sdk"
+	SourceGOPATH   = "GOPATH/bin"
+	SourceMSI      = "Go installer"
+	SourceUnknown  = "unknown"
+)
+
+// Binary is one copy of a program found in PATH
+type Binary struct {
+	Path    string `json:"path"`              // where PATH finds it
+	Target  string `json:"target"`            // the file it resolves to through symlinks
+	Version string `json:"version,omitempty"` // the Go release it was built with
+	Source  string `json:"source"`            // the installation that owns it
+	Backend string `json:"backend,omitempty"` // the gos backend, when Source is gos
+	Wins    bool   `json:"wins"`              // the one the shell runs
+	SameAs  string `json:"same_as,omitempty"` // an earlier entry resolving to the same file
+}
+
+// Program is every copy of one program in PATH, in PATH order
+type Program struct {
+	Name     string   `json:"name"`
+	Binaries []Binary `json:"binaries"`
+}
+
+// Winner returns the copy the shell runs, if any
+func (p Program) Winner() (Binary, bool) {
+	if len(p.Binaries) == 0 {
+		return Binary{}, false
+	}
+	return p.Binaries[0], true
+}
+
+// Conflicts reports whether copies of the program that are different files
+// are in PATH
+func (p Program) Conflicts() bool {
+	distinct := 0
+	for _, binary := range p.Binaries {
+		if binary.SameAs == "" {
+			distinct++
+		}
+	}
+	return distinct > 1
+}
+
+// Find walks the directories of path in order and describes every copy of
+// the program called name
+func Find(path []string, name string) Program {
+	exe := name
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+
+	program := Program{Name: name, Binaries: []Binary{}}
+	seen := map[string]string{}
+	for _, dir := range path {
+		if dir == "" {
+			continue
+		}
+		file := filepath.Join(dir, exe)
+		info, err := fsys.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		binary := Binary{Path: file, Target: file, Wins: len(program.Binaries) == 0}
+		if resolved, err := filepath.EvalSymlinks(file); err == nil {
+			binary.Target = resolved
+		}
+		binary.Source, binary.Backend = Source(binary.Path, binary.Target)
+
+		if first, ok := seen[binary.Target]; ok {
+			binary.SameAs = first
+			for _, earlier := range program.Binaries {
+				if earlier.Path == first {
+					binary.Version = earlier.Version
+				}
+			}
+		} else {
+			seen[binary.Target] = file
+			binary.Version = version(name, binary.Path, binary.Target)
+		}
+		program.Binaries = append(program.Binaries, binary)
+	}
+	return program
+}
+
+// version asks Go which release built a binary: go reports its own with
+// `go version`, other programs through `go version <file>`. Shims are links
+// to gos itself, so their version comes from the shim index instead.
+func version(name, path, target string) string {
+	if within(path, shim.Dir()) {
+		return shimVersion()
+	}
+
+	var output string
+	var err error
+	if name == "go" {
+		output, err = runner.Output(target, "version")
+	} else {
+		output, err = runner.Output("go", "version", target)
+	}
+	if err != nil {
+		return ""
+	}
+
+	if v := goversion.FromGoVersionOutput(output); v != "" {
+		return v
+	}
+	// `go version <file>` prints "<file>: go1.22.1"
+	_, v, found := strings.Cut(strings.TrimSpace(output), ": ")
+	if !found || !strings.HasPrefix(v, "go") {
+		return ""
+	}
+	v = goversion.Normalize(strings.Fields(v)[0])
+	if cut := strings.Index(v, "-"); cut >= 0 {
+		v = v[:cut]
+	}
+	return v
+}
+
+// shimVersion returns the version the shims run in the working directory
+func shimVersion() string {
+	index, err := shim.LoadIndex()
+	if err != nil {
+		return ""
+	}
+	dir, _ := os.Getwd()
+	version, _, _, err := index.Select(dir)
+	if err != nil {
+		return ""
+	}
+	return version
+}
+
+// Source names the installation a binary belongs to from where it lives:
+// the directory of a gos backend, Homebrew, a distribution package, a snap,
+// the go.dev tarball in /usr/local/go, `go install golang.org/dl/...` in
+// ~/sdk, GOPATH/bin or the Windows installer. The resolved target decides
+// before the PATH entry, since /usr/bin/go links into /usr/lib/go-1.x.
+func Source(path, target string) (source, backendName string) {
+	for _, p := range []string{target, path} {
+		if source, backendName = classify(p); source != SourceUnknown {
+			return source, backendName
+		}
+	}
+	return SourceUnknown, ""
+}
+
+// classify matches one path against the known installation directories
+func classify(path string) (string, string) {
+	home := common.GetHomeDir()
+	for _, b := range backend.All() {
+		if within(path, b.Root()) {
+			return SourceGos, b.Name()
+		}
+	}
+	if within(path, common.GetGosHome()) {
+		return SourceGos, "native"
+	}
+
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" || common.Sandboxed() {
+		gopath = filepath.Join(home, "go")
+	}
+	switch {
+	case within(path, filepath.Join(gopath, "bin")):
+		return SourceGOPATH, ""
+	case within(path, filepath.Join(home, "sdk")):
+		return SourceSDK, ""
+	}
+
+	slashed := filepath.ToSlash(path)
+	prefixes := []struct{ prefix, source string }{
+		{"/opt/homebrew/", SourceHomebrew},
+		{"/usr/local/Cellar/", SourceHomebrew},
+		{"/usr/local/Homebrew/", SourceHomebrew},
+		{"/home/linuxbrew/.linuxbrew/", SourceHomebrew},
+		{"/snap/", SourceSnap},
+		{"/var/lib/snapd/snap/", SourceSnap},
+		{"/usr/lib/go-", SourceApt},
+		{"/usr/lib/go/", SourceApt},
+		{"/usr/lib/golang/", SourceApt},
+		{"/usr/share/go", SourceApt},
+		{"/usr/local/go/", SourceUsrLocal},
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(slashed, p.prefix) {
+			return p.source, ""
+		}
+	}
+	if strings.Contains(strings.ToLower(slashed), "/program files/go/") {
+		return SourceMSI, ""
+	}
+	return SourceUnknown, ""
+}
+
+// within reports whether path is dir or below it
+func within(path, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// pathList returns the directories of PATH
+func pathList() []string {
+	return filepath.SplitList(os.Getenv("PATH"))
+}
@@ -0,0 +1,134 @@
+package which
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristobalcontreras/gos/cmd/output"
+	"github.com/cristobalcontreras/gos/cmd/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewWhichCmd creates the which command
+func NewWhichCmd() *cobra.Command {
+	return output.Enable(&cobra.Command{
+		Use:   "which [program...]",
+		Short: "Show every copy of go (or another program) in PATH and which one wins",
+		Long: `Walk PATH in order and show each copy of a program: the file it resolves
+to through symlinks, the Go release it was built with and the installation it
+belongs to (gos, Homebrew, apt, snap, /usr/local/go, ~/sdk, GOPATH/bin or the
+Go installer). The first copy is the one the shell runs; the others are
+shadowed by it. Use it when gos reports a version mismatch.
+
+Without arguments, go is looked up.`,
+		Example: `  gos which               # Every go in PATH
+  gos which gofmt gopls   # Other programs
+  gos which go -o json    # As JSON`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"go"}
+			}
+			return RunWhich(args)
+		},
+	})
+}
+
+// RunWhich looks up every program and prints what it found; it fails when
+// a program is not in PATH at all
+func RunWhich(names []string) error {
+	var programs []Program
+	var missing []string
+	for _, name := range names {
+		program := Find(pathList(), name)
+		if len(program.Binaries) == 0 {
+			missing = append(missing, name)
+		}
+		programs = append(programs, program)
+	}
+
+	if output.Structured() {
+		if err := output.Print(programs); err != nil {
+			return err
+		}
+	} else {
+		for i, program := range programs {
+			if i > 0 {
+				ui.Out.Println()
+			}
+			ShowProgram(program)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("not found in PATH: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ShowProgram prints each copy of a program in PATH order and explains which
+// one the shell runs
+func ShowProgram(program Program) {
+	ui.Title.Printf("🔎 %s\n", program.Name)
+	if len(program.Binaries) == 0 {
+		ui.Problem.Printf("  ❌ %s is not in PATH\n", program.Name)
+		return
+	}
+
+	for i, binary := range program.Binaries {
+		if binary.Wins {
+			ui.Success.Printf("  %d. ✅ %s\n", i+1, binary.Path)
+		} else {
+			ui.Out.Printf("  %d. 🚫 %s\n", i+1, binary.Path)
+		}
+		if binary.Target != binary.Path {
+			ui.Out.Printf("       → %s\n", binary.Target)
+		}
+		ui.Out.Printf("       %s\n", describe(binary))
+	}
+
+	if !program.Conflicts() {
+		return
+	}
+	winner, _ := program.Winner()
+	ui.Out.Println()
+	ui.Caution.Printf("⚠️  %s runs %s (%s); the other copies are shadowed\n", program.Name, winner.Path, describeSource(winner))
+	if winner.Source == SourceGos {
+		ui.Hint.Println("💡 gos comes first; remove the other copies if you no longer need them")
+		return
+	}
+	for _, binary := range program.Binaries {
+		if binary.Source == SourceGos {
+			ui.Hint.Println("💡 Run 'gos clean path' to take the other installations out of PATH, or 'gos env --fix' to put gos first")
+			return
+		}
+	}
+	ui.Hint.Println("💡 Remove the installations you do not use, or reorder PATH so the one you want comes first")
+}
+
+// describe summarises the version, owner and standing of one copy
+func describe(binary Binary) string {
+	parts := []string{}
+	if binary.Version != "" {
+		parts = append(parts, "go"+binary.Version)
+	} else {
+		parts = append(parts, "unknown version")
+	}
+	parts = append(parts, describeSource(binary))
+	switch {
+	case binary.Wins:
+		parts = append(parts, "wins")
+	case binary.SameAs != "":
+		parts = append(parts, "same file as "+binary.SameAs)
+	default:
+		parts = append(parts, "shadowed")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// describeSource names the installation, with the backend for gos
+func describeSource(binary Binary) string {
+	if binary.Backend != "" {
+		return fmt.Sprintf("%s (%s)", binary.Source, binary.Backend)
+	}
+	return binary.Source
+}
@@ -0,0 +1,141 @@
+package which
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cristobalcontreras/gos/cmd/common"
+	"github.com/cristobalcontreras/gos/cmd/fsys"
+	"github.com/cristobalcontreras/gos/cmd/runner"
+	"github.com/cristobalcontreras/gos/cmd/shim"
+	"github.com/cristobalcontreras/gos/cmd/testenv"
+)
+
+// install puts an empty executable at dir/name and returns its path
+func install(t *testing.T, mem *fsys.Mem, dir, name string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if err := mem.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, name)
+	if err := mem.WriteFile(file, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestSource(t *testing.T) {
+	home := filepath.Join(string(filepath.Separator), "home", "gopher")
+	t.Setenv(common.HomeVar, home)
+
+	tests := []struct {
+		name    string
+		path    string
+		target  string
+		source  string
+		backend string
+	}{
+		{"native", filepath.Join(home, ".gos", "current", "bin", "go"), "", SourceGos, "native"},
+		{"gobrew", filepath.Join(home, ".gobrew", "current", "bin", "go"), "", SourceGos, "gobrew"},
+		{"sdk", filepath.Join(home, "sdk", "go1.21.5", "bin", "go"), "", SourceSDK, ""},
+		{"GOPATH/bin", filepath.Join(home, "go", "bin", "gopls"), "", SourceGOPATH, ""},
+		{"homebrew", "/usr/local/bin/go", "/usr/local/Cellar/go/1.22.1/libexec/bin/go", SourceHomebrew, ""},
+		{"apt", "/usr/bin/go", "/usr/lib/go-1.22/bin/go", SourceApt, ""},
+		{"snap", "/snap/bin/go", "/usr/bin/snap", SourceSnap, ""},
+		{"tarball", "/usr/local/go/bin/go", "", SourceUsrLocal, ""},
+		{"unknown", "/opt/tools/go", "", SourceUnknown, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target
+			if target == "" {
+				target = tt.path
+			}
+			source, backendName := Source(tt.path, target)
+			if source != tt.source || backendName != tt.backend {
+				t.Errorf("Source(%q, %q) = %q, %q; want %q, %q", tt.path, target, source, backendName, tt.source, tt.backend)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Run("first copy wins", func(t *testing.T) {
+		home, mem, fake := testenv.Sandbox(t)
+		system := install(t, mem, filepath.Join(string(filepath.Separator), "usr", "local", "go", "bin"), "go")
+		managed := install(t, mem, filepath.Join(home, ".gos", "current", "bin"), "go")
+		fake.On(system+" version", runner.Response{Stdout: "go version go1.21.5 linux/amd64\n"})
+		fake.On(managed+" version", runner.Response{Stdout: "go version go1.22.1 linux/amd64\n"})
+
+		program := Find([]string{"", filepath.Dir(system), filepath.Join(home, "bin"), filepath.Dir(managed)}, "go")
+		if len(program.Binaries) != 2 {
+			t.Fatalf("Find() = %+v", program)
+		}
+		first, second := program.Binaries[0], program.Binaries[1]
+		if !first.Wins || first.Source != SourceUsrLocal || first.Version != "1.21.5" {
+			t.Errorf("first = %+v", first)
+		}
+		if second.Wins || second.Source != SourceGos || second.Backend != "native" || second.Version != "1.22.1" {
+			t.Errorf("second = %+v", second)
+		}
+		if !program.Conflicts() {
+			t.Error("Conflicts() = false, want true")
+		}
+	})
+
+	t.Run("other programs ask go for their version", func(t *testing.T) {
+		home, mem, fake := testenv.Sandbox(t)
+		gopls := install(t, mem, filepath.Join(home, "go", "bin"), "gopls")
+		fake.On("go version "+gopls, runner.Response{Stdout: gopls + ": go1.22.1\n"})
+
+		program := Find([]string{filepath.Dir(gopls)}, "gopls")
+		if len(program.Binaries) != 1 || program.Binaries[0].Version != "1.22.1" || program.Binaries[0].Source != SourceGOPATH {
+			t.Errorf("Find() = %+v", program)
+		}
+		if program.Conflicts() {
+			t.Error("Conflicts() = true for a single copy")
+		}
+	})
+
+	t.Run("shims report the version they run", func(t *testing.T) {
+		home, mem, _ := testenv.Sandbox(t)
+		goroot := filepath.Join(home, ".gos", "versions", "1.22.3")
+		index, err := json.Marshal(shim.Index{
+			Backend:  "native",
+			Current:  filepath.Join(home, ".gos", "current"),
+			Version:  "1.22.3",
+			Versions: map[string]string{"1.22.3": goroot},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		install(t, mem, shim.Dir(), "go")
+		gofmt := install(t, mem, shim.Dir(), "gofmt")
+		if err := mem.WriteFile(shim.IndexFile(), index, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Neither the shim nor gos is run: the fake runner knows no commands
+		for _, name := range []string{"go", "gofmt"} {
+			program := Find([]string{filepath.Dir(gofmt)}, name)
+			if len(program.Binaries) != 1 || program.Binaries[0].Version != "1.22.3" {
+				t.Errorf("Find(%q) = %+v", name, program)
+			}
+		}
+	})
+
+	t.Run("not in PATH", func(t *testing.T) {
+		testenv.Sandbox(t)
+		if program := Find([]string{"/nowhere"}, "go"); len(program.Binaries) != 0 {
+			t.Errorf("Find() = %+v", program)
+		}
+		if _, ok := (Program{Name: "go"}).Winner(); ok {
+			t.Error("Winner() found a copy in an empty program")
+		}
+	})
+}
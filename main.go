@@ -1,7 +1,10 @@
 package main
 
 import (
+	"os"
+
 	"github.com/cristobalcontreras/gos/cmd"
+	"github.com/cristobalcontreras/gos/cmd/shim"
 )
 
 // Build information. Populated at build-time via ldflags.
@@ -12,6 +15,11 @@ var (
 )
 
 func main() {
+	// Invoked through a shim such as ~/.gos/shims/go: run the project's toolchain
+	if name, ok := shim.Invoked(os.Args[0]); ok {
+		os.Exit(shim.Run(name, os.Args[1:]))
+	}
+
 	// Set version information in the root command
 	cmd.SetVersionInfo(version, commit, date)
 	cmd.Execute()